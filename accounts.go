@@ -2,6 +2,7 @@ package mastodon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -32,13 +33,21 @@ type Account struct {
 	Bot            bool           `json:"bot"`
 	Discoverable   bool           `json:"discoverable"`
 	Source         *AccountSource `json:"source"`
+	LastStatusAt   Date           `json:"last_status_at"`
+
+	// Pleroma holds the pleroma object Pleroma and Akkoma add to accounts,
+	// with fields like is_admin and relationship.
+	Pleroma json.RawMessage `json:"pleroma,omitempty"`
+	// Extra holds the members of the JSON object without a field, like
+	// the additions of other servers, when Client.ExtraFields is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Field is a Mastodon account profile field.
 type Field struct {
-	Name       string    `json:"name"`
-	Value      string    `json:"value"`
-	VerifiedAt time.Time `json:"verified_at"`
+	Name       string   `json:"name"`
+	Value      string   `json:"value"`
+	VerifiedAt NullTime `json:"verified_at"`
 }
 
 // AccountSource is a Mastodon account profile field.
@@ -131,8 +140,46 @@ func (c *Client) AccountUpdate(ctx context.Context, profile *Profile) (*Account,
 
 // GetAccountStatuses return statuses by specified account.
 func (c *Client) GetAccountStatuses(ctx context.Context, id ID, pg *Pagination) ([]*Status, error) {
+	return c.GetAccountStatusesWithOptions(ctx, id, nil, pg)
+}
+
+// AccountStatusesOptions filters the statuses returned by
+// GetAccountStatusesWithOptions.
+type AccountStatusesOptions struct {
+	// Pinned only returns the pinned statuses.
+	Pinned bool
+	// OnlyMedia only returns statuses with media attachments.
+	OnlyMedia      bool
+	ExcludeReplies bool
+	ExcludeReblogs bool
+	// Tagged only returns statuses with this hashtag, without the #.
+	Tagged string
+}
+
+// GetAccountStatusesWithOptions returns the statuses of the account id
+// filtered by opts, which may be nil.
+func (c *Client) GetAccountStatusesWithOptions(ctx context.Context, id ID, opts *AccountStatusesOptions, pg *Pagination) ([]*Status, error) {
+	params := url.Values{}
+	if opts != nil {
+		if opts.Pinned {
+			params.Set("pinned", "true")
+		}
+		if opts.OnlyMedia {
+			params.Set("only_media", "true")
+		}
+		if opts.ExcludeReplies {
+			params.Set("exclude_replies", "true")
+		}
+		if opts.ExcludeReblogs {
+			params.Set("exclude_reblogs", "true")
+		}
+		if opts.Tagged != "" {
+			params.Set("tagged", opts.Tagged)
+		}
+	}
+
 	var statuses []*Status
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/statuses", url.PathEscape(string(id))), nil, &statuses, pg)
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/statuses", url.PathEscape(string(id))), params, &statuses, pg)
 	if err != nil {
 		return nil, err
 	}
@@ -141,17 +188,12 @@ func (c *Client) GetAccountStatuses(ctx context.Context, id ID, pg *Pagination)
 
 // GetAccountPinnedStatuses returns statuses pinned by specified accuont.
 func (c *Client) GetAccountPinnedStatuses(ctx context.Context, id ID) ([]*Status, error) {
-	var statuses []*Status
-	params := url.Values{}
-	params.Set("pinned", "true")
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/statuses", url.PathEscape(string(id))), params, &statuses, nil)
-	if err != nil {
-		return nil, err
-	}
-	return statuses, nil
+	return c.GetAccountStatusesWithOptions(ctx, id, &AccountStatusesOptions{Pinned: true}, nil)
 }
 
-// GetAccountFollowers returns followers list.
+// GetAccountFollowers returns followers list. The cursors of pg are follow
+// IDs, not account IDs, so page with the cursors set on pg by the previous
+// call, using Pagination.Next and Pagination.Prev.
 func (c *Client) GetAccountFollowers(ctx context.Context, id ID, pg *Pagination) ([]*Account, error) {
 	var accounts []*Account
 	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/followers", url.PathEscape(string(id))), nil, &accounts, pg)
@@ -161,7 +203,8 @@ func (c *Client) GetAccountFollowers(ctx context.Context, id ID, pg *Pagination)
 	return accounts, nil
 }
 
-// GetAccountFollowing returns following list.
+// GetAccountFollowing returns following list, paged like
+// GetAccountFollowers.
 func (c *Client) GetAccountFollowing(ctx context.Context, id ID, pg *Pagination) ([]*Account, error) {
 	var accounts []*Account
 	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts/%s/following", url.PathEscape(string(id))), nil, &accounts, pg)
@@ -183,16 +226,18 @@ func (c *Client) GetBlocks(ctx context.Context, pg *Pagination) ([]*Account, err
 
 // Relationship holds information for relationship to the account.
 type Relationship struct {
-	ID                  ID   `json:"id"`
-	Following           bool `json:"following"`
-	FollowedBy          bool `json:"followed_by"`
-	Blocking            bool `json:"blocking"`
-	Muting              bool `json:"muting"`
-	MutingNotifications bool `json:"muting_notifications"`
-	Requested           bool `json:"requested"`
-	DomainBlocking      bool `json:"domain_blocking"`
-	ShowingReblogs      bool `json:"showing_reblogs"`
-	Endorsed            bool `json:"endorsed"`
+	ID                  ID       `json:"id"`
+	Following           bool     `json:"following"`
+	FollowedBy          bool     `json:"followed_by"`
+	Blocking            bool     `json:"blocking"`
+	Muting              bool     `json:"muting"`
+	MutingNotifications bool     `json:"muting_notifications"`
+	Requested           bool     `json:"requested"`
+	DomainBlocking      bool     `json:"domain_blocking"`
+	ShowingReblogs      bool     `json:"showing_reblogs"`
+	Notifying           bool     `json:"notifying"`
+	Languages           []string `json:"languages"`
+	Endorsed            bool     `json:"endorsed"`
 }
 
 // AccountFollow follows the account.
@@ -270,6 +315,29 @@ func (c *Client) GetAccountRelationships(ctx context.Context, ids []string) ([]*
 	return relationships, nil
 }
 
+// FamiliarFollowers holds the accounts followed by the current user that
+// also follow the account of ID.
+type FamiliarFollowers struct {
+	ID       ID         `json:"id"`
+	Accounts []*Account `json:"accounts"`
+}
+
+// GetFamiliarFollowers returns, for each of the accounts, the accounts
+// followed by the current user that also follow it.
+func (c *Client) GetFamiliarFollowers(ctx context.Context, ids []ID) ([]*FamiliarFollowers, error) {
+	params := url.Values{}
+	for _, id := range ids {
+		params.Add("id[]", string(id))
+	}
+
+	var familiar []*FamiliarFollowers
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/accounts/familiar_followers", params, &familiar, nil)
+	if err != nil {
+		return nil, err
+	}
+	return familiar, nil
+}
+
 // AccountsSearch searches accounts by query.
 func (c *Client) AccountsSearch(ctx context.Context, q string, limit int64) ([]*Account, error) {
 	params := url.Values{}
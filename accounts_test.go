@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 )
 
 func TestGetAccount(t *testing.T) {
@@ -92,7 +91,7 @@ func TestAccountUpdate(t *testing.T) {
 		t.Fatalf("should be fail: %v", err)
 	}
 	tbool := true
-	fields := []Field{{"foo", "bar", time.Time{}}, {"dum", "baz", time.Time{}}}
+	fields := []Field{{"foo", "bar", NullTime{}}, {"dum", "baz", NullTime{}}}
 	source := AccountSource{Language: String("de"), Privacy: String("public"), Sensitive: &tbool}
 	a, err := client.AccountUpdate(context.Background(), &Profile{
 		DisplayName: String("display_name"),
@@ -109,6 +108,12 @@ func TestAccountUpdate(t *testing.T) {
 	if a.Username != "zzz" {
 		t.Fatalf("want %q but %q", "zzz", a.Username)
 	}
+	_, err = client.AccountUpdate(context.Background(), &Profile{
+		Source: &AccountSource{Privacy: String("local"), Language: String("zh-TW")},
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
 }
 
 func TestGetAccountStatuses(t *testing.T) {
@@ -143,6 +148,38 @@ func TestGetAccountStatuses(t *testing.T) {
 	}
 }
 
+func TestGetAccountStatusesWithOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/v1/accounts/1234567/statuses" || q.Get("pinned") != "" ||
+			q.Get("only_media") != "true" || q.Get("exclude_replies") != "true" ||
+			q.Get("exclude_reblogs") != "true" || q.Get("tagged") != "golang" || q.Get("limit") != "5" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Link", `<http://example.com/api/v1/accounts/1234567/statuses?max_id=7>; rel="next"`)
+		fmt.Fprintln(w, `[{"id": "8", "content": "foo"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	opts := &AccountStatusesOptions{OnlyMedia: true, ExcludeReplies: true, ExcludeReblogs: true, Tagged: "golang"}
+	if _, err := client.GetAccountStatusesWithOptions(context.Background(), "1234567", nil, nil); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	pg := &Pagination{Limit: 5}
+	ss, err := client.GetAccountStatusesWithOptions(context.Background(), "1234567", opts, pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(ss) != 1 || ss[0].Content != "foo" {
+		t.Fatalf("want %q but %v", "foo", ss)
+	}
+	if pg.MaxID != "7" {
+		t.Fatalf("want %q but %q", "7", pg.MaxID)
+	}
+}
+
 func TestGetAccountPinnedStatuses(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/accounts/1234567/statuses" {
@@ -250,6 +287,61 @@ func TestGetAccountFollowing(t *testing.T) {
 	}
 }
 
+func TestGetAccountFollowsPagination(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		base := "http://example.com" + r.URL.Path
+		switch {
+		case q.Get("max_id") == "" && q.Get("min_id") == "" && q.Get("since_id") == "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?limit=2&max_id=90>; rel="next", <%s?limit=2&since_id=95>; rel="prev"`, base, base))
+			fmt.Fprintln(w, `[{"id": "5"}, {"id": "4"}]`)
+		case q.Get("max_id") == "90":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?limit=2&since_id=89>; rel="prev"`, base))
+			fmt.Fprintln(w, `[{"id": "3"}]`)
+		case q.Get("since_id") == "95":
+			fmt.Fprintln(w, `[{"id": "6"}]`)
+		default:
+			fmt.Fprintln(w, `[]`)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	for _, get := range []func(context.Context, ID, *Pagination) ([]*Account, error){
+		client.GetAccountFollowers,
+		client.GetAccountFollowing,
+	} {
+		pg := &Pagination{Limit: 2}
+		fl, err := get(context.Background(), "1", pg)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if len(fl) != 2 || !pg.HasNext() || !pg.HasPrev() {
+			t.Fatalf("want next and prev pages but %+v", pg)
+		}
+		newer := pg.Prev()
+		older := pg.Next()
+		fl, err = get(context.Background(), "1", older)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if len(fl) != 1 || fl[0].ID != "3" || older.HasNext() {
+			t.Fatalf("want %q but %v", "3", fl)
+		}
+		fl, err = get(context.Background(), "1", newer)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if len(fl) != 1 || fl[0].ID != "6" {
+			t.Fatalf("want %q but %v", "6", fl)
+		}
+	}
+}
+
 func TestGetBlocks(t *testing.T) {
 	canErr := true
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -735,3 +827,34 @@ func TestGetMutes(t *testing.T) {
 		t.Fatalf("want %q but %q", "bar", mutes[1].Username)
 	}
 }
+
+func TestGetFamiliarFollowers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/familiar_followers" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		ids := r.URL.Query()["id[]"]
+		if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "1", "accounts": [{"id": "10", "acct": "alice"}]}, {"id": "2", "accounts": []}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	familiar, err := client.GetFamiliarFollowers(context.Background(), []ID{"1", "2"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(familiar) != 2 {
+		t.Fatalf("result should be two: %d", len(familiar))
+	}
+	if familiar[0].ID != "1" || len(familiar[0].Accounts) != 1 || familiar[0].Accounts[0].Acct != "alice" {
+		t.Fatalf("unexpected familiar followers: %+v", familiar[0])
+	}
+}
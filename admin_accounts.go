@@ -0,0 +1,142 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminAccount holds admin-level information about an account.
+type AdminAccount struct {
+	ID                     ID        `json:"id"`
+	Username               string    `json:"username"`
+	Domain                 string    `json:"domain"`
+	CreatedAt              time.Time `json:"created_at"`
+	Email                  string    `json:"email"`
+	IP                     string    `json:"ip"`
+	IPs                    []AdminIP `json:"ips"`
+	Locale                 string    `json:"locale"`
+	InviteRequest          string    `json:"invite_request"`
+	Role                   *Role     `json:"role"`
+	Confirmed              bool      `json:"confirmed"`
+	Approved               bool      `json:"approved"`
+	Disabled               bool      `json:"disabled"`
+	Silenced               bool      `json:"silenced"`
+	Suspended              bool      `json:"suspended"`
+	Account                *Account  `json:"account"`
+	CreatedByApplicationID ID        `json:"created_by_application_id"`
+	InvitedByAccountID     ID        `json:"invited_by_account_id"`
+}
+
+// AdminIP holds an IP address associated with a user.
+type AdminIP struct {
+	IP     string    `json:"ip"`
+	UsedAt time.Time `json:"used_at"`
+}
+
+// Role holds information for a user role.
+type Role struct {
+	ID          ID     `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Permissions string `json:"permissions"`
+	Highlighted bool   `json:"highlighted"`
+}
+
+// AdminAccountsFilter holds the filters for AdminGetAccounts.
+// Empty fields are not sent.
+type AdminAccountsFilter struct {
+	// Origin is "local" or "remote".
+	Origin string
+	// Status is one of "active", "pending", "disabled", "silenced" or "suspended".
+	Status string
+	// Permissions is "staff" to only return accounts with staff permissions.
+	Permissions string
+	RoleIDs     []ID
+	InvitedBy   ID
+	Username    string
+	DisplayName string
+	ByDomain    string
+	Email       string
+	IP          string
+}
+
+func (f *AdminAccountsFilter) toValues() url.Values {
+	params := url.Values{}
+	if f == nil {
+		return params
+	}
+	if f.Origin != "" {
+		params.Set("origin", f.Origin)
+	}
+	if f.Status != "" {
+		params.Set("status", f.Status)
+	}
+	if f.Permissions != "" {
+		params.Set("permissions", f.Permissions)
+	}
+	for _, id := range f.RoleIDs {
+		params.Add("role_ids[]", string(id))
+	}
+	if f.InvitedBy != "" {
+		params.Set("invited_by", string(f.InvitedBy))
+	}
+	if f.Username != "" {
+		params.Set("username", f.Username)
+	}
+	if f.DisplayName != "" {
+		params.Set("display_name", f.DisplayName)
+	}
+	if f.ByDomain != "" {
+		params.Set("by_domain", f.ByDomain)
+	}
+	if f.Email != "" {
+		params.Set("email", f.Email)
+	}
+	if f.IP != "" {
+		params.Set("ip", f.IP)
+	}
+	return params
+}
+
+// AdminGetAccounts returns accounts matching filter.
+func (c *Client) AdminGetAccounts(ctx context.Context, filter *AdminAccountsFilter, pg *Pagination) ([]*AdminAccount, error) {
+	var accounts []*AdminAccount
+	err := c.doAPI(ctx, http.MethodGet, "/api/v2/admin/accounts", filter.toValues(), &accounts, pg)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// AdminGetAccount returns the account specified by id.
+func (c *Client) AdminGetAccount(ctx context.Context, id ID) (*AdminAccount, error) {
+	var account AdminAccount
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/accounts/%s", url.PathEscape(string(id))), nil, &account, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// AdminApproveAccount approves the pending account specified by id.
+func (c *Client) AdminApproveAccount(ctx context.Context, id ID) (*AdminAccount, error) {
+	var account AdminAccount
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/accounts/%s/approve", url.PathEscape(string(id))), nil, &account, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// AdminRejectAccount rejects the pending account specified by id.
+func (c *Client) AdminRejectAccount(ctx context.Context, id ID) (*AdminAccount, error) {
+	var account AdminAccount
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/accounts/%s/reject", url.PathEscape(string(id))), nil, &account, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
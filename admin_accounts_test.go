@@ -0,0 +1,122 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminGetAccounts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/admin/accounts" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("origin") != "local" || q.Get("status") != "pending" || q.Get("by_domain") != "" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if ids := q["role_ids[]"]; len(ids) != 2 || ids[0] != "1" || ids[1] != "3" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if q.Get("limit") != "10" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "108965218747268792", "username": "admin", "domain": null, "email": "admin@example.com", "ip": "192.168.42.1", "ips": [{"ip": "192.168.42.1", "used_at": "2022-09-15T01:38:58.851Z"}], "role": {"id": "3", "name": "Owner", "permissions": "1"}, "confirmed": true, "approved": false, "account": {"id": "108965218747268792", "username": "admin"}, "created_by_application_id": null, "invited_by_account_id": "1"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminGetAccounts(context.Background(), nil, nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	accounts, err := client.AdminGetAccounts(context.Background(), &AdminAccountsFilter{
+		Origin:  "local",
+		Status:  "pending",
+		RoleIDs: []ID{"1", "3"},
+	}, &Pagination{Limit: 10})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(accounts) != 1 {
+		t.Fatalf("result should be one: %d", len(accounts))
+	}
+	a := accounts[0]
+	if a.Email != "admin@example.com" {
+		t.Fatalf("want %q but %q", "admin@example.com", a.Email)
+	}
+	if len(a.IPs) != 1 || a.IPs[0].IP != "192.168.42.1" {
+		t.Fatalf("unexpected ips: %v", a.IPs)
+	}
+	if a.Role == nil || a.Role.Name != "Owner" {
+		t.Fatalf("unexpected role: %v", a.Role)
+	}
+	if a.Account == nil || a.Account.Username != "admin" {
+		t.Fatalf("unexpected account: %v", a.Account)
+	}
+	if a.CreatedByApplicationID != "" {
+		t.Fatalf("want %q but %q", "", a.CreatedByApplicationID)
+	}
+	if a.InvitedByAccountID != "1" {
+		t.Fatalf("want %q but %q", "1", a.InvitedByAccountID)
+	}
+}
+
+func TestAdminAccountActions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/accounts/1":
+			fmt.Fprintln(w, `{"id": "1", "username": "foo", "approved": false}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/accounts/1/approve":
+			fmt.Fprintln(w, `{"id": "1", "username": "foo", "approved": true}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/accounts/2/reject":
+			fmt.Fprintln(w, `{"id": "2", "username": "bar", "approved": false}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminGetAccount(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	account, err := client.AdminGetAccount(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if account.Username != "foo" {
+		t.Fatalf("want %q but %q", "foo", account.Username)
+	}
+	account, err = client.AdminApproveAccount(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !account.Approved {
+		t.Fatalf("want %v but %v", true, account.Approved)
+	}
+	_, err = client.AdminRejectAccount(context.Background(), "1")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	account, err = client.AdminRejectAccount(context.Background(), "2")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if account.ID != "2" {
+		t.Fatalf("want %q but %q", "2", account.ID)
+	}
+}
@@ -0,0 +1,86 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// AdminCanonicalEmailBlock holds information for a blocked canonical email.
+// The hash is the SHA256 of the canonicalized email address.
+type AdminCanonicalEmailBlock struct {
+	ID                 ID     `json:"id"`
+	CanonicalEmailHash string `json:"canonical_email_hash"`
+}
+
+// AdminGetCanonicalEmailBlocks returns the blocked canonical emails.
+func (c *Client) AdminGetCanonicalEmailBlocks(ctx context.Context, pg *Pagination) ([]*AdminCanonicalEmailBlock, error) {
+	var blocks []*AdminCanonicalEmailBlock
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/canonical_email_blocks", nil, &blocks, pg)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AdminGetCanonicalEmailBlock returns the canonical email block specified by id.
+func (c *Client) AdminGetCanonicalEmailBlock(ctx context.Context, id ID) (*AdminCanonicalEmailBlock, error) {
+	var block AdminCanonicalEmailBlock
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/canonical_email_blocks/%s", url.PathEscape(string(id))), nil, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminTestCanonicalEmailBlocks returns the canonical email blocks matching email.
+func (c *Client) AdminTestCanonicalEmailBlocks(ctx context.Context, email string) ([]*AdminCanonicalEmailBlock, error) {
+	if email == "" {
+		return nil, errors.New("email can't be empty")
+	}
+	params := url.Values{}
+	params.Set("email", email)
+
+	var blocks []*AdminCanonicalEmailBlock
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/canonical_email_blocks/test", params, &blocks, nil)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AdminCreateCanonicalEmailBlock blocks the canonical form of email.
+func (c *Client) AdminCreateCanonicalEmailBlock(ctx context.Context, email string) (*AdminCanonicalEmailBlock, error) {
+	if email == "" {
+		return nil, errors.New("email can't be empty")
+	}
+	params := url.Values{}
+	params.Set("email", email)
+	return c.adminCreateCanonicalEmailBlock(ctx, params)
+}
+
+// AdminCreateCanonicalEmailBlockHash blocks a canonical email by its hash.
+func (c *Client) AdminCreateCanonicalEmailBlockHash(ctx context.Context, hash string) (*AdminCanonicalEmailBlock, error) {
+	if hash == "" {
+		return nil, errors.New("hash can't be empty")
+	}
+	params := url.Values{}
+	params.Set("canonical_email_hash", hash)
+	return c.adminCreateCanonicalEmailBlock(ctx, params)
+}
+
+func (c *Client) adminCreateCanonicalEmailBlock(ctx context.Context, params url.Values) (*AdminCanonicalEmailBlock, error) {
+	var block AdminCanonicalEmailBlock
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/canonical_email_blocks", params, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminDeleteCanonicalEmailBlock lifts the canonical email block specified by id.
+func (c *Client) AdminDeleteCanonicalEmailBlock(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/canonical_email_blocks/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
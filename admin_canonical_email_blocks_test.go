@@ -0,0 +1,155 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testEmailHash = "b344e55d11b3fc25d0d53194e0475838bf17e9be67ce3e6469956222d9a34f9c"
+
+func TestAdminGetCanonicalEmailBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/admin/canonical_email_blocks":
+			fmt.Fprintf(w, `[{"id": "1", "canonical_email_hash": %q}]`, testEmailHash)
+		case "/api/v1/admin/canonical_email_blocks/1":
+			fmt.Fprintf(w, `{"id": "1", "canonical_email_hash": %q}`, testEmailHash)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	blocks, err := client.AdminGetCanonicalEmailBlocks(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].CanonicalEmailHash != testEmailHash {
+		t.Fatalf("unexpected blocks: %v", blocks)
+	}
+	_, err = client.AdminGetCanonicalEmailBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminGetCanonicalEmailBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.ID != "1" {
+		t.Fatalf("want %q but %q", "1", block.ID)
+	}
+}
+
+func TestAdminTestCanonicalEmailBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/canonical_email_blocks/test" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("email") != "spam.mer+foo@example.com" {
+			fmt.Fprintln(w, `[]`)
+			return
+		}
+		fmt.Fprintf(w, `[{"id": "1", "canonical_email_hash": %q}]`, testEmailHash)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminTestCanonicalEmailBlocks(context.Background(), "")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	blocks, err := client.AdminTestCanonicalEmailBlocks(context.Background(), "someone@example.com")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 0 {
+		t.Fatalf("result should be zero: %d", len(blocks))
+	}
+	blocks, err = client.AdminTestCanonicalEmailBlocks(context.Background(), "spam.mer+foo@example.com")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("result should be one: %d", len(blocks))
+	}
+}
+
+func TestAdminCreateCanonicalEmailBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/canonical_email_blocks" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("email") == "" && r.PostFormValue("canonical_email_hash") != testEmailHash {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "canonical_email_hash": %q}`, testEmailHash)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminCreateCanonicalEmailBlock(context.Background(), "")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateCanonicalEmailBlockHash(context.Background(), "")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateCanonicalEmailBlockHash(context.Background(), "bad")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminCreateCanonicalEmailBlock(context.Background(), "spammer@example.com")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.CanonicalEmailHash != testEmailHash {
+		t.Fatalf("want %q but %q", testEmailHash, block.CanonicalEmailHash)
+	}
+	block, err = client.AdminCreateCanonicalEmailBlockHash(context.Background(), testEmailHash)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.ID != "1" {
+		t.Fatalf("want %q but %q", "1", block.ID)
+	}
+}
+
+func TestAdminDeleteCanonicalEmailBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/admin/canonical_email_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	err := client.AdminDeleteCanonicalEmailBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AdminDeleteCanonicalEmailBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminDomainAllow holds information for a domain allowed to federate in
+// limited federation mode.
+type AdminDomainAllow struct {
+	ID        ID        `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminGetDomainAllows returns the allowed domains.
+func (c *Client) AdminGetDomainAllows(ctx context.Context, pg *Pagination) ([]*AdminDomainAllow, error) {
+	var allows []*AdminDomainAllow
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/domain_allows", nil, &allows, pg)
+	if err != nil {
+		return nil, err
+	}
+	return allows, nil
+}
+
+// AdminGetDomainAllow returns the allowed domain specified by id.
+func (c *Client) AdminGetDomainAllow(ctx context.Context, id ID) (*AdminDomainAllow, error) {
+	var allow AdminDomainAllow
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/domain_allows/%s", url.PathEscape(string(id))), nil, &allow, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &allow, nil
+}
+
+// AdminCreateDomainAllow allows a domain to federate.
+func (c *Client) AdminCreateDomainAllow(ctx context.Context, domain string) (*AdminDomainAllow, error) {
+	if domain == "" {
+		return nil, errors.New("domain can't be empty")
+	}
+	params := url.Values{}
+	params.Set("domain", domain)
+
+	var allow AdminDomainAllow
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/domain_allows", params, &allow, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &allow, nil
+}
+
+// AdminDeleteDomainAllow removes the allowed domain specified by id.
+func (c *Client) AdminDeleteDomainAllow(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/domain_allows/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
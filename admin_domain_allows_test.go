@@ -0,0 +1,59 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminDomainAllows(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/domain_allows":
+			fmt.Fprintln(w, `[{"id": "1", "domain": "friends.example", "created_at": "2024-05-01T12:00:00.000Z"}]`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/domain_allows/1":
+			fmt.Fprintln(w, `{"id": "1", "domain": "friends.example"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/domain_allows":
+			fmt.Fprintf(w, `{"id": "2", "domain": %q}`, r.PostFormValue("domain"))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/admin/domain_allows/1":
+			fmt.Fprintln(w, `{}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	allows, err := client.AdminGetDomainAllows(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(allows) != 1 || allows[0].Domain != "friends.example" || allows[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected allows: %v", allows)
+	}
+	allow, err := client.AdminGetDomainAllow(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if allow.Domain != "friends.example" {
+		t.Fatalf("want %q but %q", "friends.example", allow.Domain)
+	}
+	if _, err := client.AdminCreateDomainAllow(context.Background(), ""); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	allow, err = client.AdminCreateDomainAllow(context.Background(), "new.example")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if allow.ID != "2" || allow.Domain != "new.example" {
+		t.Fatalf("unexpected allow: %v", allow)
+	}
+	if err := client.AdminDeleteDomainAllow(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := client.AdminDeleteDomainAllow(context.Background(), "3"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
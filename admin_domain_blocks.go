@@ -0,0 +1,114 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DomainBlockSeverity is the severity of an AdminDomainBlock.
+type DomainBlockSeverity string
+
+// Severities of domain blocks.
+const (
+	DomainBlockSeverityNoop    DomainBlockSeverity = "noop"
+	DomainBlockSeveritySilence DomainBlockSeverity = "silence"
+	DomainBlockSeveritySuspend DomainBlockSeverity = "suspend"
+)
+
+func (s DomainBlockSeverity) String() string { return string(s) }
+
+// AdminDomainBlock holds information for a blocked domain.
+type AdminDomainBlock struct {
+	ID             ID                  `json:"id"`
+	Domain         string              `json:"domain"`
+	Digest         string              `json:"digest"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Severity       DomainBlockSeverity `json:"severity"`
+	RejectMedia    bool                `json:"reject_media"`
+	RejectReports  bool                `json:"reject_reports"`
+	PrivateComment string              `json:"private_comment"`
+	PublicComment  string              `json:"public_comment"`
+	Obfuscate      bool                `json:"obfuscate"`
+}
+
+func (b *AdminDomainBlock) toValues() url.Values {
+	params := url.Values{}
+	if b.Severity != "" {
+		params.Set("severity", b.Severity.String())
+	}
+	params.Set("reject_media", strconv.FormatBool(b.RejectMedia))
+	params.Set("reject_reports", strconv.FormatBool(b.RejectReports))
+	params.Set("private_comment", b.PrivateComment)
+	params.Set("public_comment", b.PublicComment)
+	params.Set("obfuscate", strconv.FormatBool(b.Obfuscate))
+	return params
+}
+
+// AdminGetDomainBlocks returns the blocked domains.
+func (c *Client) AdminGetDomainBlocks(ctx context.Context, pg *Pagination) ([]*AdminDomainBlock, error) {
+	var blocks []*AdminDomainBlock
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/domain_blocks", nil, &blocks, pg)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AdminGetDomainBlock returns the domain block specified by id.
+func (c *Client) AdminGetDomainBlock(ctx context.Context, id ID) (*AdminDomainBlock, error) {
+	var block AdminDomainBlock
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/domain_blocks/%s", url.PathEscape(string(id))), nil, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminCreateDomainBlock blocks a domain. An empty Severity lets the server
+// use its default, silence.
+func (c *Client) AdminCreateDomainBlock(ctx context.Context, block *AdminDomainBlock) (*AdminDomainBlock, error) {
+	if block == nil {
+		return nil, errors.New("block can't be nil")
+	}
+	if block.Domain == "" {
+		return nil, errors.New("domain can't be empty")
+	}
+	params := block.toValues()
+	params.Set("domain", block.Domain)
+
+	var b AdminDomainBlock
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/domain_blocks", params, &b, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// AdminUpdateDomainBlock updates the domain block specified by id. The domain
+// itself can't be changed. The severity is sent whenever Severity is set; an
+// empty Severity keeps the current one.
+func (c *Client) AdminUpdateDomainBlock(ctx context.Context, id ID, block *AdminDomainBlock) (*AdminDomainBlock, error) {
+	if block == nil {
+		return nil, errors.New("block can't be nil")
+	}
+	if id == ID("") {
+		return nil, errors.New("ID can't be empty")
+	}
+
+	var b AdminDomainBlock
+	err := c.doAPI(ctx, http.MethodPut, fmt.Sprintf("/api/v1/admin/domain_blocks/%s", url.PathEscape(string(id))), block.toValues(), &b, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// AdminDeleteDomainBlock lifts the domain block specified by id.
+func (c *Client) AdminDeleteDomainBlock(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/domain_blocks/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
@@ -0,0 +1,183 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminGetDomainBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/admin/domain_blocks":
+			fmt.Fprintln(w, `[{"id": "1", "domain": "spam.example", "severity": "suspend", "reject_media": true, "reject_reports": true, "private_comment": "spam", "public_comment": null, "obfuscate": true}, {"id": "2", "domain": "noisy.example", "severity": "silence"}]`)
+		case "/api/v1/admin/domain_blocks/2":
+			fmt.Fprintln(w, `{"id": "2", "domain": "noisy.example", "severity": "silence"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	blocks, err := client.AdminGetDomainBlocks(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("result should be two: %d", len(blocks))
+	}
+	if blocks[0].Severity != DomainBlockSeveritySuspend || !blocks[0].RejectMedia || !blocks[0].RejectReports || !blocks[0].Obfuscate {
+		t.Fatalf("unexpected block: %+v", blocks[0])
+	}
+	if blocks[0].PrivateComment != "spam" {
+		t.Fatalf("want %q but %q", "spam", blocks[0].PrivateComment)
+	}
+	_, err = client.AdminGetDomainBlock(context.Background(), "3")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminGetDomainBlock(context.Background(), "2")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Domain != "noisy.example" {
+		t.Fatalf("want %q but %q", "noisy.example", block.Domain)
+	}
+}
+
+func TestAdminCreateDomainBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/domain_blocks" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("severity") != "suspend" || r.PostFormValue("reject_media") != "true" || r.PostFormValue("obfuscate") != "false" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "domain": %q, "severity": "suspend", "reject_media": true, "public_comment": %q}`, r.PostFormValue("domain"), r.PostFormValue("public_comment"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminCreateDomainBlock(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateDomainBlock(context.Background(), &AdminDomainBlock{Severity: DomainBlockSeveritySuspend})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateDomainBlock(context.Background(), &AdminDomainBlock{Domain: "spam.example"})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminCreateDomainBlock(context.Background(), &AdminDomainBlock{
+		Domain:        "spam.example",
+		Severity:      DomainBlockSeveritySuspend,
+		RejectMedia:   true,
+		PublicComment: "Spam",
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Domain != "spam.example" {
+		t.Fatalf("want %q but %q", "spam.example", block.Domain)
+	}
+	if block.PublicComment != "Spam" {
+		t.Fatalf("want %q but %q", "Spam", block.PublicComment)
+	}
+}
+
+func TestAdminUpdateDomainBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/admin/domain_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("domain") != "" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		if _, ok := r.PostForm["severity"]; ok && r.PostFormValue("severity") == "" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "domain": "spam.example", "severity": %q, "reject_reports": %s}`, r.PostFormValue("severity"), r.PostFormValue("reject_reports"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminUpdateDomainBlock(context.Background(), "", &AdminDomainBlock{})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminUpdateDomainBlock(context.Background(), "1", nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminUpdateDomainBlock(context.Background(), "1", &AdminDomainBlock{
+		Domain:        "ignored.example",
+		Severity:      DomainBlockSeveritySilence,
+		RejectReports: true,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Severity != DomainBlockSeveritySilence {
+		t.Fatalf("want %q but %q", DomainBlockSeveritySilence, block.Severity)
+	}
+	if !block.RejectReports {
+		t.Fatalf("want %v but %v", true, block.RejectReports)
+	}
+
+	block, err = client.AdminUpdateDomainBlock(context.Background(), "1", &AdminDomainBlock{Severity: DomainBlockSeverityNoop})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Severity != DomainBlockSeverityNoop {
+		t.Fatalf("want %q but %q", DomainBlockSeverityNoop, block.Severity)
+	}
+	block, err = client.AdminUpdateDomainBlock(context.Background(), "1", &AdminDomainBlock{})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Severity != "" {
+		t.Fatalf("severity should not be sent: %q", block.Severity)
+	}
+}
+
+func TestAdminDeleteDomainBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/admin/domain_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	err := client.AdminDeleteDomainBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AdminDeleteDomainBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminEmailDomainBlock holds information for a blocked email domain.
+// History holds the daily sign-up attempts using the domain.
+type AdminEmailDomainBlock struct {
+	ID        ID        `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+	History   []History `json:"history"`
+}
+
+// AdminGetEmailDomainBlocks returns the blocked email domains.
+func (c *Client) AdminGetEmailDomainBlocks(ctx context.Context, pg *Pagination) ([]*AdminEmailDomainBlock, error) {
+	var blocks []*AdminEmailDomainBlock
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/email_domain_blocks", nil, &blocks, pg)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AdminGetEmailDomainBlock returns the email domain block specified by id.
+func (c *Client) AdminGetEmailDomainBlock(ctx context.Context, id ID) (*AdminEmailDomainBlock, error) {
+	var block AdminEmailDomainBlock
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/email_domain_blocks/%s", url.PathEscape(string(id))), nil, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminCreateEmailDomainBlock blocks sign-ups from email addresses of domain.
+func (c *Client) AdminCreateEmailDomainBlock(ctx context.Context, domain string) (*AdminEmailDomainBlock, error) {
+	if domain == "" {
+		return nil, errors.New("domain can't be empty")
+	}
+	params := url.Values{}
+	params.Set("domain", domain)
+
+	var block AdminEmailDomainBlock
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/email_domain_blocks", params, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminDeleteEmailDomainBlock lifts the email domain block specified by id.
+func (c *Client) AdminDeleteEmailDomainBlock(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/email_domain_blocks/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
@@ -0,0 +1,102 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminGetEmailDomainBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/admin/email_domain_blocks":
+			fmt.Fprintln(w, `[{"id": "1", "domain": "spam.example", "created_at": "2022-11-16T06:09:36.176Z", "history": [{"day": "1668556800", "accounts": "3", "uses": "5"}]}]`)
+		case "/api/v1/admin/email_domain_blocks/1":
+			fmt.Fprintln(w, `{"id": "1", "domain": "spam.example"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	blocks, err := client.AdminGetEmailDomainBlocks(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("result should be one: %d", len(blocks))
+	}
+	if blocks[0].Domain != "spam.example" {
+		t.Fatalf("want %q but %q", "spam.example", blocks[0].Domain)
+	}
+	if len(blocks[0].History) != 1 || blocks[0].History[0].Accounts != "3" || blocks[0].History[0].Uses != "5" {
+		t.Fatalf("unexpected history: %v", blocks[0].History)
+	}
+	_, err = client.AdminGetEmailDomainBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminGetEmailDomainBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.ID != "1" {
+		t.Fatalf("want %q but %q", "1", block.ID)
+	}
+}
+
+func TestAdminCreateEmailDomainBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/email_domain_blocks" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "domain": %q, "history": []}`, r.PostFormValue("domain"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminCreateEmailDomainBlock(context.Background(), "")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminCreateEmailDomainBlock(context.Background(), "spam.example")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Domain != "spam.example" {
+		t.Fatalf("want %q but %q", "spam.example", block.Domain)
+	}
+}
+
+func TestAdminDeleteEmailDomainBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/admin/email_domain_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	err := client.AdminDeleteEmailDomainBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AdminDeleteEmailDomainBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
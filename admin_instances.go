@@ -0,0 +1,218 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AdminInstance is the federation overview of a remote domain over a
+// period, built from the admin measures, domain blocks and domain allows.
+// Mastodon has no admin API for its instances page.
+type AdminInstance struct {
+	Domain string
+	// Accounts is the number of accounts of the domain known locally.
+	Accounts int64
+	// Statuses and MediaAttachments are the numbers received from the domain.
+	Statuses         int64
+	MediaAttachments int64
+	// Follows is the number of accounts of the domain followed by local
+	// accounts and Followers the number following local accounts.
+	Follows   int64
+	Followers int64
+	// Reports is the number of reports about accounts of the domain.
+	Reports int64
+	// Block is the domain block of the domain, holding its moderation notes.
+	Block *AdminDomainBlock
+	// Allow is set when the domain is explicitly allowed.
+	Allow *AdminDomainAllow
+}
+
+// AdminInstancesRequest holds the parameters for AdminGetInstances.
+type AdminInstancesRequest struct {
+	// StartAt and EndAt default to the last 30 days.
+	StartAt time.Time
+	EndAt   time.Time
+	// Limit is the number of most active servers to include, besides the
+	// blocked and allowed domains; defaults to 10.
+	Limit int64
+	// Interval is waited between the pages of domain blocks and allows,
+	// and between the measures requests of the domains. Zero means one
+	// second and negative values disable it.
+	Interval time.Duration
+}
+
+// AdminGetInstance returns the federation overview of domain.
+func (c *Client) AdminGetInstance(ctx context.Context, domain string, start, end time.Time) (*AdminInstance, error) {
+	if domain == "" {
+		return nil, errors.New("domain can't be empty")
+	}
+	instance := &AdminInstance{Domain: domain}
+	if err := c.adminInstanceMeasures(ctx, instance, start, end); err != nil {
+		return nil, err
+	}
+	err := walkPages(ctx, 0, &Pagination{Limit: 200}, func(pg *Pagination) (bool, error) {
+		blocks, err := c.AdminGetDomainBlocks(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, b := range blocks {
+			if b.Domain == domain {
+				instance.Block = b
+				return false, nil
+			}
+		}
+		return len(blocks) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = walkPages(ctx, 0, &Pagination{Limit: 200}, func(pg *Pagination) (bool, error) {
+		allows, err := c.AdminGetDomainAllows(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range allows {
+			if a.Domain == domain {
+				instance.Allow = a
+				return false, nil
+			}
+		}
+		return len(allows) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// AdminGetInstances returns the federation overview of the most active
+// servers and of all blocked and allowed domains, sorted by domain. It
+// makes one measures request per domain. A nil req uses the defaults.
+func (c *Client) AdminGetInstances(ctx context.Context, req *AdminInstancesRequest) ([]*AdminInstance, error) {
+	if req == nil {
+		req = &AdminInstancesRequest{}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	end := req.EndAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := req.StartAt
+	if start.IsZero() {
+		start = end.AddDate(0, 0, -30)
+	}
+	interval := pageInterval(req.Interval)
+
+	instances := map[string]*AdminInstance{}
+	instance := func(domain string) *AdminInstance {
+		if i, ok := instances[domain]; ok {
+			return i
+		}
+		i := &AdminInstance{Domain: domain}
+		instances[domain] = i
+		return i
+	}
+
+	dimensions, err := c.AdminGetDimensions(ctx, &AdminDimensionsRequest{
+		Keys:    []AdminDimensionKey{DimensionServers},
+		StartAt: start,
+		EndAt:   end,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dimensions {
+		for _, data := range d.Data {
+			instance(data.Key)
+		}
+	}
+	err = walkPages(ctx, interval, &Pagination{Limit: 200}, func(pg *Pagination) (bool, error) {
+		blocks, err := c.AdminGetDomainBlocks(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, b := range blocks {
+			instance(b.Domain).Block = b
+		}
+		return len(blocks) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = walkPages(ctx, interval, &Pagination{Limit: 200}, func(pg *Pagination) (bool, error) {
+		allows, err := c.AdminGetDomainAllows(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range allows {
+			instance(a.Domain).Allow = a
+		}
+		return len(allows) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]*AdminInstance, 0, len(instances))
+	for _, i := range instances {
+		r = append(r, i)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Domain < r[j].Domain })
+	for n, i := range r {
+		if n > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if err := c.adminInstanceMeasures(ctx, i, start, end); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (c *Client) adminInstanceMeasures(ctx context.Context, instance *AdminInstance, start, end time.Time) error {
+	measures, err := c.AdminGetMeasures(ctx, &AdminMeasuresRequest{
+		Keys: []AdminMeasureKey{
+			MeasureInstanceAccounts,
+			MeasureInstanceStatuses,
+			MeasureInstanceMediaAttachments,
+			MeasureInstanceFollows,
+			MeasureInstanceFollowers,
+			MeasureInstanceReports,
+		},
+		StartAt: start,
+		EndAt:   end,
+		Domain:  instance.Domain,
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range measures {
+		total, _ := strconv.ParseInt(m.Total, 10, 64)
+		switch AdminMeasureKey(m.Key) {
+		case MeasureInstanceAccounts:
+			instance.Accounts = total
+		case MeasureInstanceStatuses:
+			instance.Statuses = total
+		case MeasureInstanceMediaAttachments:
+			instance.MediaAttachments = total
+		case MeasureInstanceFollows:
+			instance.Follows = total
+		case MeasureInstanceFollowers:
+			instance.Followers = total
+		case MeasureInstanceReports:
+			instance.Reports = total
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminGetInstances(t *testing.T) {
+	var limit string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/admin/dimensions":
+			limit = r.PostFormValue("limit")
+			if r.PostFormValue("keys[]") != "servers" || limit != "5" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `[{"key": "servers", "data": [{"key": "busy.example", "value": "40"}, {"key": "spam.example", "value": "3"}]}]`)
+		case "/api/v1/admin/domain_blocks":
+			fmt.Fprintln(w, `[{"id": "1", "domain": "spam.example", "severity": "suspend", "private_comment": "spam waves"}]`)
+		case "/api/v1/admin/domain_allows":
+			fmt.Fprintln(w, `[{"id": "2", "domain": "friends.example"}]`)
+		case "/api/v1/admin/measures":
+			domain := r.PostFormValue("instance_follows[domain]")
+			if domain == "" || r.PostFormValue("start_at") != "2024-05-01" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `[{"key": "instance_accounts", "total": "%d"}, {"key": "instance_follows", "total": "7"}, {"key": "instance_followers", "total": "9"}, {"key": "instance_reports", "total": "1"}]`, len(domain))
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+	instances, err := client.AdminGetInstances(context.Background(), &AdminInstancesRequest{
+		StartAt:  start,
+		EndAt:    end,
+		Limit:    5,
+		Interval: -1,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(instances) != 3 {
+		t.Fatalf("result should be three: %d", len(instances))
+	}
+	busy, friends, spam := instances[0], instances[1], instances[2]
+	if busy.Domain != "busy.example" || busy.Accounts != int64(len("busy.example")) || busy.Follows != 7 || busy.Followers != 9 {
+		t.Fatalf("unexpected instance: %+v", busy)
+	}
+	if friends.Domain != "friends.example" || friends.Allow == nil || friends.Block != nil {
+		t.Fatalf("unexpected instance: %+v", friends)
+	}
+	if spam.Domain != "spam.example" || spam.Block == nil || spam.Block.PrivateComment != "spam waves" || spam.Reports != 1 {
+		t.Fatalf("unexpected instance: %+v", spam)
+	}
+
+	instance, err := client.AdminGetInstance(context.Background(), "spam.example", start, end)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if instance.Block == nil || instance.Allow != nil || instance.Followers != 9 {
+		t.Fatalf("unexpected instance: %+v", instance)
+	}
+	if _, err := client.AdminGetInstance(context.Background(), "", start, end); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+
+	client.AdminGetInstances(context.Background(), nil)
+	if limit != "10" {
+		t.Fatalf("want %q but %q", "10", limit)
+	}
+}
@@ -0,0 +1,112 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Convenience constants for AdminIPBlock.Severity
+const (
+	IPBlockSeveritySignUpRequiresApproval = "sign_up_requires_approval"
+	IPBlockSeveritySignUpBlock            = "sign_up_block"
+	IPBlockSeverityNoAccess               = "no_access"
+)
+
+// AdminIPBlock holds information for a blocked IP range.
+type AdminIPBlock struct {
+	ID        ID        `json:"id"`
+	IP        string    `json:"ip"`
+	Severity  string    `json:"severity"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AdminGetIPBlocks returns the blocked IP ranges.
+func (c *Client) AdminGetIPBlocks(ctx context.Context, pg *Pagination) ([]*AdminIPBlock, error) {
+	var blocks []*AdminIPBlock
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/ip_blocks", nil, &blocks, pg)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AdminGetIPBlock returns the IP block specified by id.
+func (c *Client) AdminGetIPBlock(ctx context.Context, id ID) (*AdminIPBlock, error) {
+	var block AdminIPBlock
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/ip_blocks/%s", url.PathEscape(string(id))), nil, &block, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// AdminCreateIPBlock blocks an IP range. IP is an address or a CIDR range.
+func (c *Client) AdminCreateIPBlock(ctx context.Context, block *AdminIPBlock) (*AdminIPBlock, error) {
+	if block == nil {
+		return nil, errors.New("block can't be nil")
+	}
+	if block.IP == "" {
+		return nil, errors.New("IP can't be empty")
+	}
+	if block.Severity == "" {
+		return nil, errors.New("severity can't be empty")
+	}
+	params := url.Values{}
+	params.Set("ip", block.IP)
+	params.Set("severity", block.Severity)
+	if block.Comment != "" {
+		params.Set("comment", block.Comment)
+	}
+	if !block.ExpiresAt.IsZero() {
+		diff := time.Until(block.ExpiresAt)
+		params.Set("expires_in", fmt.Sprintf("%.0f", diff.Seconds()))
+	}
+
+	var b AdminIPBlock
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/ip_blocks", params, &b, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// AdminUpdateIPBlock updates the IP block specified by id. A zero ExpiresAt
+// keeps the current expiry.
+func (c *Client) AdminUpdateIPBlock(ctx context.Context, id ID, block *AdminIPBlock) (*AdminIPBlock, error) {
+	if block == nil {
+		return nil, errors.New("block can't be nil")
+	}
+	if id == ID("") {
+		return nil, errors.New("ID can't be empty")
+	}
+	params := url.Values{}
+	if block.IP != "" {
+		params.Set("ip", block.IP)
+	}
+	if block.Severity != "" {
+		params.Set("severity", block.Severity)
+	}
+	params.Set("comment", block.Comment)
+	if !block.ExpiresAt.IsZero() {
+		diff := time.Until(block.ExpiresAt)
+		params.Set("expires_in", fmt.Sprintf("%.0f", diff.Seconds()))
+	}
+
+	var b AdminIPBlock
+	err := c.doAPI(ctx, http.MethodPut, fmt.Sprintf("/api/v1/admin/ip_blocks/%s", url.PathEscape(string(id))), params, &b, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// AdminDeleteIPBlock lifts the IP block specified by id.
+func (c *Client) AdminDeleteIPBlock(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/ip_blocks/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
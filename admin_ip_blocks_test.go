@@ -0,0 +1,154 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAdminGetIPBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/admin/ip_blocks":
+			fmt.Fprintln(w, `[{"id": "1", "ip": "8.8.8.8/32", "severity": "no_access", "comment": "", "created_at": "2022-11-16T07:22:00.501Z", "expires_at": null}]`)
+		case "/api/v1/admin/ip_blocks/1":
+			fmt.Fprintln(w, `{"id": "1", "ip": "8.8.8.8/32", "severity": "no_access", "expires_at": "2022-11-17T07:22:00.501Z"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	blocks, err := client.AdminGetIPBlocks(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("result should be one: %d", len(blocks))
+	}
+	if blocks[0].IP != "8.8.8.8/32" || blocks[0].Severity != IPBlockSeverityNoAccess {
+		t.Fatalf("unexpected block: %+v", blocks[0])
+	}
+	if !blocks[0].ExpiresAt.IsZero() {
+		t.Fatalf("expires_at should be zero: %v", blocks[0].ExpiresAt)
+	}
+	_, err = client.AdminGetIPBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminGetIPBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.ExpiresAt.IsZero() {
+		t.Fatal("expires_at should be set")
+	}
+}
+
+func TestAdminCreateIPBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/ip_blocks" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		expiresIn, err := strconv.Atoi(r.PostFormValue("expires_in"))
+		if err != nil || expiresIn < 3590 || expiresIn > 3600 {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "ip": %q, "severity": %q, "comment": %q}`, r.PostFormValue("ip"), r.PostFormValue("severity"), r.PostFormValue("comment"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminCreateIPBlock(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateIPBlock(context.Background(), &AdminIPBlock{Severity: IPBlockSeveritySignUpBlock})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminCreateIPBlock(context.Background(), &AdminIPBlock{IP: "192.0.2.0/24"})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminCreateIPBlock(context.Background(), &AdminIPBlock{
+		IP:        "192.0.2.0/24",
+		Severity:  IPBlockSeveritySignUpRequiresApproval,
+		Comment:   "abuse",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.IP != "192.0.2.0/24" || block.Severity != IPBlockSeveritySignUpRequiresApproval || block.Comment != "abuse" {
+		t.Fatalf("unexpected block: %+v", block)
+	}
+}
+
+func TestAdminUpdateIPBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/admin/ip_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		r.ParseForm()
+		if _, ok := r.PostForm["expires_in"]; ok {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "ip": "192.0.2.0/24", "severity": %q}`, r.PostFormValue("severity"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminUpdateIPBlock(context.Background(), "", &AdminIPBlock{})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	block, err := client.AdminUpdateIPBlock(context.Background(), "1", &AdminIPBlock{Severity: IPBlockSeverityNoAccess})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if block.Severity != IPBlockSeverityNoAccess {
+		t.Fatalf("want %q but %q", IPBlockSeverityNoAccess, block.Severity)
+	}
+}
+
+func TestAdminDeleteIPBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/admin/ip_blocks/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	err := client.AdminDeleteIPBlock(context.Background(), "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AdminDeleteIPBlock(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
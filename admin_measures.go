@@ -0,0 +1,179 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AdminMeasureKey is a key for AdminGetMeasures.
+type AdminMeasureKey string
+
+// Keys for AdminGetMeasures. The tag_ keys need AdminMeasuresRequest.TagID
+// and the instance_ keys need AdminMeasuresRequest.Domain.
+const (
+	MeasureActiveUsers              AdminMeasureKey = "active_users"
+	MeasureNewUsers                 AdminMeasureKey = "new_users"
+	MeasureInteractions             AdminMeasureKey = "interactions"
+	MeasureOpenedReports            AdminMeasureKey = "opened_reports"
+	MeasureResolvedReports          AdminMeasureKey = "resolved_reports"
+	MeasureTagAccounts              AdminMeasureKey = "tag_accounts"
+	MeasureTagUses                  AdminMeasureKey = "tag_uses"
+	MeasureTagServers               AdminMeasureKey = "tag_servers"
+	MeasureInstanceAccounts         AdminMeasureKey = "instance_accounts"
+	MeasureInstanceMediaAttachments AdminMeasureKey = "instance_media_attachments"
+	MeasureInstanceReports          AdminMeasureKey = "instance_reports"
+	MeasureInstanceStatuses         AdminMeasureKey = "instance_statuses"
+	MeasureInstanceFollows          AdminMeasureKey = "instance_follows"
+	MeasureInstanceFollowers        AdminMeasureKey = "instance_followers"
+)
+
+// AdminDimensionKey is a key for AdminGetDimensions.
+type AdminDimensionKey string
+
+// Keys for AdminGetDimensions. The tag_ keys need AdminDimensionsRequest.TagID
+// and the instance_ keys need AdminDimensionsRequest.Domain.
+const (
+	DimensionLanguages         AdminDimensionKey = "languages"
+	DimensionSources           AdminDimensionKey = "sources"
+	DimensionServers           AdminDimensionKey = "servers"
+	DimensionSpaceUsage        AdminDimensionKey = "space_usage"
+	DimensionSoftwareVersions  AdminDimensionKey = "software_versions"
+	DimensionTagServers        AdminDimensionKey = "tag_servers"
+	DimensionTagLanguages      AdminDimensionKey = "tag_languages"
+	DimensionInstanceAccounts  AdminDimensionKey = "instance_accounts"
+	DimensionInstanceLanguages AdminDimensionKey = "instance_languages"
+)
+
+// AdminMeasure holds a quantitative metric over a period of time.
+// Numbers are sent as strings by the server.
+type AdminMeasure struct {
+	Key           string             `json:"key"`
+	Unit          string             `json:"unit"`
+	Total         string             `json:"total"`
+	HumanValue    string             `json:"human_value"`
+	PreviousTotal string             `json:"previous_total"`
+	Data          []AdminMeasureData `json:"data"`
+}
+
+// AdminMeasureData holds the value of a measure for one day.
+type AdminMeasureData struct {
+	Date  time.Time `json:"date"`
+	Value string    `json:"value"`
+}
+
+// AdminDimension holds a qualitative metric over a period of time.
+type AdminDimension struct {
+	Key  string               `json:"key"`
+	Data []AdminDimensionData `json:"data"`
+}
+
+// AdminDimensionData holds one entry of a dimension.
+type AdminDimensionData struct {
+	Key        string `json:"key"`
+	HumanKey   string `json:"human_key"`
+	Value      string `json:"value"`
+	Unit       string `json:"unit"`
+	HumanValue string `json:"human_value"`
+}
+
+// AdminMeasuresRequest holds the parameters for AdminGetMeasures.
+type AdminMeasuresRequest struct {
+	Keys    []AdminMeasureKey
+	StartAt time.Time
+	EndAt   time.Time
+	TagID   ID
+	Domain  string
+}
+
+// AdminDimensionsRequest holds the parameters for AdminGetDimensions.
+type AdminDimensionsRequest struct {
+	Keys    []AdminDimensionKey
+	StartAt time.Time
+	EndAt   time.Time
+	// Limit is the maximum number of entries per dimension.
+	Limit  int64
+	TagID  ID
+	Domain string
+}
+
+func adminMetricValues(keys []string, start, end time.Time, tagID ID, domain string) (url.Values, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keys can't be empty")
+	}
+	if start.IsZero() || end.IsZero() {
+		return nil, errors.New("start and end can't be empty")
+	}
+	params := url.Values{}
+	params.Set("start_at", start.Format("2006-01-02"))
+	params.Set("end_at", end.Format("2006-01-02"))
+	for _, k := range keys {
+		params.Add("keys[]", k)
+		switch {
+		case strings.HasPrefix(k, "tag_"):
+			if tagID == "" {
+				return nil, fmt.Errorf("%s needs a tag ID", k)
+			}
+			params.Set(k+"[id]", string(tagID))
+		case strings.HasPrefix(k, "instance_"):
+			if domain == "" {
+				return nil, fmt.Errorf("%s needs a domain", k)
+			}
+			params.Set(k+"[domain]", domain)
+		}
+	}
+	return params, nil
+}
+
+// AdminGetMeasures returns quantitative metrics of the instance. A nil req is
+// an empty request, which fails for having no keys.
+func (c *Client) AdminGetMeasures(ctx context.Context, req *AdminMeasuresRequest) ([]*AdminMeasure, error) {
+	if req == nil {
+		req = &AdminMeasuresRequest{}
+	}
+	keys := make([]string, len(req.Keys))
+	for i, k := range req.Keys {
+		keys[i] = string(k)
+	}
+	params, err := adminMetricValues(keys, req.StartAt, req.EndAt, req.TagID, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var measures []*AdminMeasure
+	err = c.doAPI(ctx, http.MethodPost, "/api/v1/admin/measures", params, &measures, nil)
+	if err != nil {
+		return nil, err
+	}
+	return measures, nil
+}
+
+// AdminGetDimensions returns qualitative metrics of the instance. A nil req
+// is an empty request, which fails for having no keys.
+func (c *Client) AdminGetDimensions(ctx context.Context, req *AdminDimensionsRequest) ([]*AdminDimension, error) {
+	if req == nil {
+		req = &AdminDimensionsRequest{}
+	}
+	keys := make([]string, len(req.Keys))
+	for i, k := range req.Keys {
+		keys[i] = string(k)
+	}
+	params, err := adminMetricValues(keys, req.StartAt, req.EndAt, req.TagID, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if req.Limit > 0 {
+		params.Set("limit", fmt.Sprint(req.Limit))
+	}
+
+	var dimensions []*AdminDimension
+	err = c.doAPI(ctx, http.MethodPost, "/api/v1/admin/dimensions", params, &dimensions, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dimensions, nil
+}
@@ -0,0 +1,111 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminGetMeasures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/measures" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		r.ParseForm()
+		if keys := r.PostForm["keys[]"]; len(keys) != 2 || keys[0] != "active_users" || keys[1] != "tag_uses" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if r.PostFormValue("start_at") != "2022-09-14" || r.PostFormValue("end_at") != "2022-09-16" || r.PostFormValue("tag_uses[id]") != "18" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"key": "active_users", "unit": null, "total": "2", "previous_total": "0", "data": [{"date": "2022-09-14T00:00:00Z", "value": "2"}, {"date": "2022-09-15T00:00:00Z", "value": "0"}]}, {"key": "tag_uses", "total": "5", "data": []}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	start := time.Date(2022, 9, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 9, 16, 0, 0, 0, 0, time.UTC)
+	_, err := client.AdminGetMeasures(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminGetMeasures(context.Background(), &AdminMeasuresRequest{StartAt: start, EndAt: end})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	_, err = client.AdminGetMeasures(context.Background(), &AdminMeasuresRequest{
+		Keys:    []AdminMeasureKey{MeasureTagUses},
+		StartAt: start,
+		EndAt:   end,
+	})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	measures, err := client.AdminGetMeasures(context.Background(), &AdminMeasuresRequest{
+		Keys:    []AdminMeasureKey{MeasureActiveUsers, MeasureTagUses},
+		StartAt: start,
+		EndAt:   end,
+		TagID:   "18",
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(measures) != 2 {
+		t.Fatalf("result should be two: %d", len(measures))
+	}
+	if measures[0].Total != "2" || measures[0].PreviousTotal != "0" {
+		t.Fatalf("unexpected measure: %+v", measures[0])
+	}
+	if len(measures[0].Data) != 2 || !measures[0].Data[0].Date.Equal(start) || measures[0].Data[0].Value != "2" {
+		t.Fatalf("unexpected data: %+v", measures[0].Data)
+	}
+}
+
+func TestAdminGetDimensions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/dimensions" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("limit") != "2" || r.PostFormValue("instance_languages[domain]") != "mastodon.social" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"key": "instance_languages", "data": [{"key": "en", "human_key": "English", "value": "10"}, {"key": "ja", "human_key": "Japanese", "value": "3"}]}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.AdminGetDimensions(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	dimensions, err := client.AdminGetDimensions(context.Background(), &AdminDimensionsRequest{
+		Keys:    []AdminDimensionKey{DimensionInstanceLanguages},
+		StartAt: time.Now().Add(-7 * 24 * time.Hour),
+		EndAt:   time.Now(),
+		Limit:   2,
+		Domain:  "mastodon.social",
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(dimensions) != 1 || len(dimensions[0].Data) != 2 {
+		t.Fatalf("unexpected dimensions: %+v", dimensions)
+	}
+	if dimensions[0].Data[1].HumanKey != "Japanese" || dimensions[0].Data[1].Value != "3" {
+		t.Fatalf("unexpected data: %+v", dimensions[0].Data[1])
+	}
+}
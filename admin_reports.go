@@ -0,0 +1,97 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminReport holds admin-level information about a report.
+type AdminReport struct {
+	ID                   ID             `json:"id"`
+	ActionTaken          bool           `json:"action_taken"`
+	ActionTakenAt        *time.Time     `json:"action_taken_at"`
+	Category             ReportCategory `json:"category"`
+	Comment              string         `json:"comment"`
+	Forwarded            bool           `json:"forwarded"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	Account              *AdminAccount  `json:"account"`
+	TargetAccount        *AdminAccount  `json:"target_account"`
+	AssignedAccount      *AdminAccount  `json:"assigned_account"`
+	ActionTakenByAccount *AdminAccount  `json:"action_taken_by_account"`
+	Statuses             []*Status      `json:"statuses"`
+	Rules                []Rule         `json:"rules"`
+}
+
+// AdminReportsFilter holds the filters for AdminGetReports.
+type AdminReportsFilter struct {
+	// Resolved returns resolved reports instead of unresolved ones.
+	Resolved        bool
+	AccountID       ID
+	TargetAccountID ID
+}
+
+// AdminGetReports returns reports matching filter.
+func (c *Client) AdminGetReports(ctx context.Context, filter *AdminReportsFilter, pg *Pagination) ([]*AdminReport, error) {
+	params := url.Values{}
+	if filter != nil {
+		if filter.Resolved {
+			params.Set("resolved", "true")
+		}
+		if filter.AccountID != "" {
+			params.Set("account_id", string(filter.AccountID))
+		}
+		if filter.TargetAccountID != "" {
+			params.Set("target_account_id", string(filter.TargetAccountID))
+		}
+	}
+
+	var reports []*AdminReport
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/admin/reports", params, &reports, pg)
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// AdminGetReport returns the report specified by id.
+func (c *Client) AdminGetReport(ctx context.Context, id ID) (*AdminReport, error) {
+	var report AdminReport
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/reports/%s", url.PathEscape(string(id))), nil, &report, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AdminAssignReportToSelf claims the report specified by id for the current user.
+func (c *Client) AdminAssignReportToSelf(ctx context.Context, id ID) (*AdminReport, error) {
+	return c.adminReportAction(ctx, id, "assign_to_self")
+}
+
+// AdminUnassignReport unassigns the report specified by id.
+func (c *Client) AdminUnassignReport(ctx context.Context, id ID) (*AdminReport, error) {
+	return c.adminReportAction(ctx, id, "unassign")
+}
+
+// AdminResolveReport marks the report specified by id as resolved.
+func (c *Client) AdminResolveReport(ctx context.Context, id ID) (*AdminReport, error) {
+	return c.adminReportAction(ctx, id, "resolve")
+}
+
+// AdminReopenReport reopens the resolved report specified by id.
+func (c *Client) AdminReopenReport(ctx context.Context, id ID) (*AdminReport, error) {
+	return c.adminReportAction(ctx, id, "reopen")
+}
+
+func (c *Client) adminReportAction(ctx context.Context, id ID, action string) (*AdminReport, error) {
+	var report AdminReport
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/reports/%s/%s", url.PathEscape(string(id)), action), nil, &report, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
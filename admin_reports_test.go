@@ -0,0 +1,131 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminGetReports(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/reports" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("resolved") != "true" || r.URL.Query().Get("target_account_id") != "2" {
+			fmt.Fprintln(w, `[]`)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "1", "action_taken": true, "action_taken_at": "2022-09-09T21:19:23.085Z", "category": "violation", "comment": "spam", "forwarded": false, "account": {"id": "3", "username": "reporter"}, "target_account": {"id": "2", "username": "spammer"}, "assigned_account": null, "statuses": [{"id": "100", "content": "buy now"}], "rules": [{"id": "1", "text": "No spam"}]}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	reports, err := client.AdminGetReports(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("result should be zero: %d", len(reports))
+	}
+	reports, err = client.AdminGetReports(context.Background(), &AdminReportsFilter{Resolved: true, TargetAccountID: "2"}, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("result should be one: %d", len(reports))
+	}
+	rp := reports[0]
+	if rp.Category != "violation" {
+		t.Fatalf("want %q but %q", "violation", rp.Category)
+	}
+	if rp.ActionTakenAt == nil {
+		t.Fatal("action_taken_at should be set")
+	}
+	if rp.TargetAccount.Username != "spammer" {
+		t.Fatalf("want %q but %q", "spammer", rp.TargetAccount.Username)
+	}
+	if rp.AssignedAccount != nil {
+		t.Fatalf("want %v but %v", nil, rp.AssignedAccount)
+	}
+	if len(rp.Statuses) != 1 || rp.Statuses[0].ID != "100" {
+		t.Fatalf("unexpected statuses: %v", rp.Statuses)
+	}
+	if len(rp.Rules) != 1 || rp.Rules[0].Text != "No spam" {
+		t.Fatalf("unexpected rules: %v", rp.Rules)
+	}
+}
+
+func TestAdminReportActions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/reports/1":
+			fmt.Fprintln(w, `{"id": "1", "action_taken": false}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/reports/1/assign_to_self":
+			fmt.Fprintln(w, `{"id": "1", "assigned_account": {"id": "9"}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/reports/1/unassign":
+			fmt.Fprintln(w, `{"id": "1", "assigned_account": null}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/reports/1/resolve":
+			fmt.Fprintln(w, `{"id": "1", "action_taken": true}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/reports/1/reopen":
+			fmt.Fprintln(w, `{"id": "1", "action_taken": false}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	ctx := context.Background()
+	_, err := client.AdminGetReport(ctx, "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	rp, err := client.AdminGetReport(ctx, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rp.ID != "1" {
+		t.Fatalf("want %q but %q", "1", rp.ID)
+	}
+	rp, err = client.AdminAssignReportToSelf(ctx, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rp.AssignedAccount == nil || rp.AssignedAccount.ID != "9" {
+		t.Fatalf("unexpected assigned account: %v", rp.AssignedAccount)
+	}
+	rp, err = client.AdminUnassignReport(ctx, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rp.AssignedAccount != nil {
+		t.Fatalf("want %v but %v", nil, rp.AssignedAccount)
+	}
+	rp, err = client.AdminResolveReport(ctx, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !rp.ActionTaken {
+		t.Fatalf("want %v but %v", true, rp.ActionTaken)
+	}
+	rp, err = client.AdminReopenReport(ctx, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rp.ActionTaken {
+		t.Fatalf("want %v but %v", false, rp.ActionTaken)
+	}
+	_, err = client.AdminResolveReport(ctx, "2")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
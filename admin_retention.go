@@ -0,0 +1,53 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Frequencies of AdminGetRetention.
+const (
+	RetentionDay   = "day"
+	RetentionMonth = "month"
+)
+
+// AdminCohort holds the retention of the users who signed up in a period.
+type AdminCohort struct {
+	Period    time.Time              `json:"period"`
+	Frequency string                 `json:"frequency"`
+	Data      []AdminCohortRetention `json:"data"`
+}
+
+// AdminCohortRetention holds the retention of a cohort at a later period.
+type AdminCohortRetention struct {
+	Date time.Time `json:"date"`
+	// Rate is the fraction of the cohort still active, between 0 and 1.
+	Rate float64 `json:"rate"`
+	// Value is the number of users of the cohort still active.
+	Value string `json:"value"`
+}
+
+// AdminGetRetention returns user retention cohorts between start and end,
+// grouped by frequency, which is RetentionDay or RetentionMonth.
+func (c *Client) AdminGetRetention(ctx context.Context, start, end time.Time, frequency string) ([]*AdminCohort, error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, errors.New("start and end can't be empty")
+	}
+	if frequency != RetentionDay && frequency != RetentionMonth {
+		return nil, errors.New("frequency must be day or month")
+	}
+	params := url.Values{}
+	params.Set("start_at", start.Format("2006-01-02"))
+	params.Set("end_at", end.Format("2006-01-02"))
+	params.Set("frequency", frequency)
+
+	var cohorts []*AdminCohort
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/admin/retention", params, &cohorts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return cohorts, nil
+}
@@ -0,0 +1,46 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminGetRetention(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/retention" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.PostFormValue("frequency") != "month" || r.PostFormValue("start_at") != "2022-09-01" || r.PostFormValue("end_at") != "2022-10-31" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"period": "2022-09-01T00:00:00Z", "frequency": "month", "data": [{"date": "2022-09-01T00:00:00Z", "rate": 1.0, "value": "2"}, {"date": "2022-10-01T00:00:00Z", "rate": 0.5, "value": "1"}]}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	start := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)
+	_, err := client.AdminGetRetention(context.Background(), start, end, "week")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	cohorts, err := client.AdminGetRetention(context.Background(), start, end, RetentionMonth)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(cohorts) != 1 || !cohorts[0].Period.Equal(start) {
+		t.Fatalf("unexpected cohorts: %+v", cohorts)
+	}
+	if len(cohorts[0].Data) != 2 || cohorts[0].Data[1].Rate != 0.5 || cohorts[0].Data[1].Value != "1" {
+		t.Fatalf("unexpected data: %+v", cohorts[0].Data)
+	}
+}
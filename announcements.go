@@ -0,0 +1,53 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Announcement is an announcement set by the administrators of the
+// instance.
+type Announcement struct {
+	ID          ID                      `json:"id"`
+	Content     string                  `json:"content"`
+	StartsAt    *time.Time              `json:"starts_at"`
+	EndsAt      *time.Time              `json:"ends_at"`
+	AllDay      bool                    `json:"all_day"`
+	PublishedAt time.Time               `json:"published_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	Read        bool                    `json:"read"`
+	Mentions    []Mention               `json:"mentions"`
+	Statuses    []AnnouncementStatus    `json:"statuses"`
+	Tags        []Tag                   `json:"tags"`
+	Emojis      []Emoji                 `json:"emojis"`
+	Reactions   []*AnnouncementReaction `json:"reactions"`
+}
+
+// AnnouncementStatus is a status linked in an announcement.
+type AnnouncementStatus struct {
+	ID  ID     `json:"id"`
+	URL string `json:"url"`
+}
+
+// AnnouncementReaction is an emoji reaction to an announcement.
+type AnnouncementReaction struct {
+	Name      string `json:"name"`
+	Count     int64  `json:"count"`
+	Me        bool   `json:"me"`
+	URL       string `json:"url"`
+	StaticURL string `json:"static_url"`
+
+	// AnnouncementID is only set in streaming events.
+	AnnouncementID ID `json:"announcement_id"`
+}
+
+// GetAnnouncements returns the current announcements of the instance.
+func (c *Client) GetAnnouncements(ctx context.Context) ([]*Announcement, error) {
+	var announcements []*Announcement
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/announcements", nil, &announcements, nil)
+	if err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
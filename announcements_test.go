@@ -0,0 +1,35 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAnnouncements(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/announcements" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "8", "content": "<p>Maintenance tonight</p>", "all_day": false, "published_at": "2020-07-03T01:27:38.726Z", "reactions": [{"name": "bongoCat", "count": 9, "me": false}]}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	announcements, err := client.GetAnnouncements(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(announcements) != 1 {
+		t.Fatalf("result should be one: %d", len(announcements))
+	}
+	if announcements[0].ID != "8" {
+		t.Fatalf("want %q but %q", "8", announcements[0].ID)
+	}
+	if len(announcements[0].Reactions) != 1 || announcements[0].Reactions[0].Count != 9 {
+		t.Fatalf("want %d reactions but %v", 9, announcements[0].Reactions)
+	}
+}
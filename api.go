@@ -0,0 +1,95 @@
+package mastodon
+
+import (
+	"context"
+	"io"
+)
+
+// Statuses is the part of the API about statuses and their media,
+// implemented by *Client. Accept it instead of *Client to replace the API
+// with a fake in tests; a fake can embed the interface and implement only
+// the methods it needs.
+type Statuses interface {
+	GetStatus(ctx context.Context, id ID) (*Status, error)
+	GetStatusContext(ctx context.Context, id ID) (*Context, error)
+	GetStatusSource(ctx context.Context, id ID) (*Source, error)
+	GetStatusHistory(ctx context.Context, id ID) ([]*StatusHistory, error)
+	GetRebloggedBy(ctx context.Context, id ID, pg *Pagination) ([]*Account, error)
+	GetFavouritedBy(ctx context.Context, id ID, pg *Pagination) ([]*Account, error)
+	PostStatus(ctx context.Context, toot *Toot) (*Status, error)
+	PostStatusWithOptions(ctx context.Context, toot *Toot, opts *PostStatusOptions) (*Status, error)
+	UpdateStatus(ctx context.Context, toot *Toot, id ID) (*Status, error)
+	DeleteStatus(ctx context.Context, id ID) error
+	Reblog(ctx context.Context, id ID) (*Status, error)
+	Unreblog(ctx context.Context, id ID) (*Status, error)
+	Favourite(ctx context.Context, id ID) (*Status, error)
+	Unfavourite(ctx context.Context, id ID) (*Status, error)
+	Bookmark(ctx context.Context, id ID) (*Status, error)
+	Unbookmark(ctx context.Context, id ID) (*Status, error)
+	Pin(ctx context.Context, id ID) (*Status, error)
+	Unpin(ctx context.Context, id ID) (*Status, error)
+	TranslateStatus(ctx context.Context, id ID, lang string) (*Translation, error)
+	UploadMedia(ctx context.Context, file string) (*Attachment, error)
+	UploadMediaFromReader(ctx context.Context, reader io.Reader) (*Attachment, error)
+	UploadMediaFromMedia(ctx context.Context, media *Media) (*Attachment, error)
+}
+
+// Accounts is the part of the API about accounts and relationships,
+// implemented by *Client.
+type Accounts interface {
+	GetAccount(ctx context.Context, id ID) (*Account, error)
+	GetAccountCurrentUser(ctx context.Context) (*Account, error)
+	AccountUpdate(ctx context.Context, profile *Profile) (*Account, error)
+	GetAccountStatuses(ctx context.Context, id ID, pg *Pagination) ([]*Status, error)
+	GetAccountStatusesWithOptions(ctx context.Context, id ID, opts *AccountStatusesOptions, pg *Pagination) ([]*Status, error)
+	GetAccountFollowers(ctx context.Context, id ID, pg *Pagination) ([]*Account, error)
+	GetAccountFollowing(ctx context.Context, id ID, pg *Pagination) ([]*Account, error)
+	GetAccountRelationships(ctx context.Context, ids []string) ([]*Relationship, error)
+	AccountFollow(ctx context.Context, id ID) (*Relationship, error)
+	AccountUnfollow(ctx context.Context, id ID) (*Relationship, error)
+	AccountBlock(ctx context.Context, id ID) (*Relationship, error)
+	AccountUnblock(ctx context.Context, id ID) (*Relationship, error)
+	AccountMute(ctx context.Context, id ID) (*Relationship, error)
+	AccountUnmute(ctx context.Context, id ID) (*Relationship, error)
+	AccountsSearch(ctx context.Context, q string, limit int64) ([]*Account, error)
+	GetBlocks(ctx context.Context, pg *Pagination) ([]*Account, error)
+	GetMutes(ctx context.Context, pg *Pagination) ([]*Account, error)
+	GetFollowRequests(ctx context.Context, pg *Pagination) ([]*Account, error)
+	FollowRequestAuthorize(ctx context.Context, id ID) error
+	FollowRequestReject(ctx context.Context, id ID) error
+}
+
+// Timelines is the part of the API about timelines, implemented by *Client.
+type Timelines interface {
+	GetTimelineHome(ctx context.Context, pg *Pagination) ([]*Status, error)
+	GetTimelinePublic(ctx context.Context, isLocal bool, pg *Pagination) ([]*Status, error)
+	GetTimelinePublicWithOptions(ctx context.Context, opts *PublicTimelineOptions, pg *Pagination) ([]*Status, error)
+	GetTimelineHashtag(ctx context.Context, tag string, isLocal bool, pg *Pagination) ([]*Status, error)
+	GetTimelineList(ctx context.Context, id ID, pg *Pagination) ([]*Status, error)
+	GetTimelineLink(ctx context.Context, u string, pg *Pagination) ([]*Status, error)
+	GetTimelineDirect(ctx context.Context, pg *Pagination) ([]*Status, error)
+	GetFavourites(ctx context.Context, pg *Pagination) ([]*Status, error)
+	GetBookmarks(ctx context.Context, pg *Pagination) ([]*Status, error)
+}
+
+// Streams is the part of the API about streaming, implemented by *Client.
+type Streams interface {
+	Stream(ctx context.Context, spec StreamSpec, h Handler) error
+	StreamingUser(ctx context.Context) (chan Event, error)
+	StreamingUserNotification(ctx context.Context) (chan Event, error)
+	StreamingPublic(ctx context.Context, isLocal bool) (chan Event, error)
+	StreamingHashtag(ctx context.Context, tag string, isLocal bool) (chan Event, error)
+	StreamingList(ctx context.Context, id ID) (chan Event, error)
+	StreamingDirect(ctx context.Context) (chan Event, error)
+}
+
+// API is the part of the API covered by Statuses, Accounts, Timelines and
+// Streams, implemented by *Client.
+type API interface {
+	Statuses
+	Accounts
+	Timelines
+	Streams
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,41 @@
+package mastodon
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeStatuses replaces the API in tests, implementing only PostStatus.
+type fakeStatuses struct {
+	Statuses
+	posted []*Toot
+}
+
+func (f *fakeStatuses) PostStatus(ctx context.Context, toot *Toot) (*Status, error) {
+	f.posted = append(f.posted, toot)
+	return &Status{ID: "1", Content: toot.Status}, nil
+}
+
+func TestStatusesFake(t *testing.T) {
+	announce := func(api Statuses, text string) (ID, error) {
+		s, err := api.PostStatus(context.Background(), &Toot{Status: text, Visibility: VisibilityUnlisted})
+		if err != nil {
+			return "", err
+		}
+		return s.ID, nil
+	}
+
+	fake := &fakeStatuses{}
+	id, err := announce(fake, "hello")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if id != "1" || len(fake.posted) != 1 || fake.posted[0].Status != "hello" {
+		t.Fatalf("unexpected posts: %v", fake.posted)
+	}
+
+	var api API = NewClient(&Config{Server: "https://mastodon.example"})
+	if _, ok := api.(Statuses); !ok {
+		t.Fatal("Client should implement Statuses")
+	}
+}
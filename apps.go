@@ -30,6 +30,8 @@ type AppConfig struct {
 // Application is a mastodon application.
 type Application struct {
 	ID           ID     `json:"id"`
+	Name         string `json:"name"`
+	Website      string `json:"website"`
 	RedirectURI  string `json:"redirect_uri"`
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
@@ -78,19 +80,14 @@ func RegisterApp(ctx context.Context, appConfig *AppConfig) (*Application, error
 		return nil, err
 	}
 
-	u, err = url.Parse(appConfig.Server)
+	config := &Config{Server: appConfig.Server, ClientID: app.ClientID}
+	app.AuthURI, err = AuthorizeURL(config, &AuthorizeOptions{
+		RedirectURI: app.RedirectURI,
+		Scopes:      strings.Fields(appConfig.Scopes),
+	})
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "/oauth/authorize")
-	u.RawQuery = url.Values{
-		"scope":         {appConfig.Scopes},
-		"response_type": {"code"},
-		"redirect_uri":  {app.RedirectURI},
-		"client_id":     {app.ClientID},
-	}.Encode()
-
-	app.AuthURI = u.String()
 
 	return &app, nil
 }
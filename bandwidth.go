@@ -0,0 +1,66 @@
+package mastodon
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// lowBandwidthPageSize caps the page size in low bandwidth mode.
+	lowBandwidthPageSize = 10
+	// lowBandwidthCacheTTL is how long instance data is cached in low
+	// bandwidth mode.
+	lowBandwidthCacheTTL = 6 * time.Hour
+)
+
+type instanceCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// lowBandwidthCached reports whether the responses of uri are cached in
+// low bandwidth mode.
+func lowBandwidthCached(uri string) bool {
+	return uri == "/api/v1/instance" || uri == "/api/v2/instance" || strings.HasPrefix(uri, "/api/v1/instance/")
+}
+
+// pagination returns the pagination to send for pg, with a smaller page
+// size in low bandwidth mode.
+func (c *Client) pagination(pg *Pagination) *Pagination {
+	if !c.LowBandwidth || pg == nil {
+		return pg
+	}
+	p := *pg
+	if p.Limit <= 0 || p.Limit > lowBandwidthPageSize {
+		p.Limit = lowBandwidthPageSize
+	}
+	return &p
+}
+
+func (c *Client) cachedBody(uri string, now time.Time) ([]byte, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	r, ok := c.cache[uri]
+	if !ok || now.After(r.expires) {
+		return nil, false
+	}
+	return r.body, true
+}
+
+func (c *Client) cacheBody(uri string, body []byte, now time.Time) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if c.cache == nil {
+		c.cache = map[string]*instanceCacheEntry{}
+	}
+	c.cache[uri] = &instanceCacheEntry{body: body, expires: now.Add(lowBandwidthCacheTTL)}
+}
+
+// AvatarURL returns the URL of the avatar of a to display: the static
+// avatar in low bandwidth mode, else the possibly animated one.
+func (c *Client) AvatarURL(a *Account) string {
+	if c.LowBandwidth && a.AvatarStatic != "" {
+		return a.AvatarStatic
+	}
+	return a.Avatar
+}
@@ -0,0 +1,82 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLowBandwidthPageSize(t *testing.T) {
+	var limits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		fmt.Fprintln(w, `[]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.LowBandwidth = true
+	pg := &Pagination{Limit: 40}
+	_, err := client.GetTimelineHome(context.Background(), pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	_, err = client.GetTimelineHome(context.Background(), &Pagination{Limit: 5})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(limits) != 2 || limits[0] != "10" || limits[1] != "5" {
+		t.Fatalf("want [10 5] but %v", limits)
+	}
+	if pg.Limit != 40 {
+		t.Fatalf("pagination should not be changed: %d", pg.Limit)
+	}
+}
+
+func TestLowBandwidthInstanceCache(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"title": "mastodon"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	for i := 0; i < 2; i++ {
+		_, err := client.GetInstance(context.Background())
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("result should be two: %d", requests)
+	}
+
+	client.LowBandwidth = true
+	for i := 0; i < 2; i++ {
+		ins, err := client.GetInstance(context.Background())
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if ins.Title != "mastodon" {
+			t.Fatalf("want %q but %q", "mastodon", ins.Title)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("instance should be cached: %d requests", requests)
+	}
+}
+
+func TestAvatarURL(t *testing.T) {
+	a := &Account{Avatar: "https://example.com/a.gif", AvatarStatic: "https://example.com/a.png"}
+	client := NewClient(&Config{})
+	if u := client.AvatarURL(a); u != a.Avatar {
+		t.Fatalf("want %q but %q", a.Avatar, u)
+	}
+	client.LowBandwidth = true
+	if u := client.AvatarURL(a); u != a.AvatarStatic {
+		t.Fatalf("want %q but %q", a.AvatarStatic, u)
+	}
+}
@@ -0,0 +1,116 @@
+package mastodon
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const blurhashChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// DecodeBlurhash decodes a blurhash, as found in Attachment.Blurhash, into
+// an image of the given size.
+func DecodeBlurhash(hash string, width, height int) (image.Image, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("invalid blurhash size")
+	}
+	if len(hash) < 6 {
+		return nil, errors.New("invalid blurhash: too short")
+	}
+	sizeFlag, err := decodeBase83(hash[:1])
+	if err != nil {
+		return nil, err
+	}
+	nx, ny := sizeFlag%9+1, sizeFlag/9+1
+	if len(hash) != 4+2*nx*ny {
+		return nil, errors.New("invalid blurhash: bad length")
+	}
+	quantMax, err := decodeBase83(hash[1:2])
+	if err != nil {
+		return nil, err
+	}
+	maxValue := float64(quantMax+1) / 166
+
+	colors := make([][3]float64, nx*ny)
+	dc, err := decodeBase83(hash[2:6])
+	if err != nil {
+		return nil, err
+	}
+	colors[0] = [3]float64{srgbToLinear(dc >> 16), srgbToLinear(dc >> 8 & 255), srgbToLinear(dc & 255)}
+	for i := 1; i < len(colors); i++ {
+		v, err := decodeBase83(hash[4+i*2 : 6+i*2])
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = [3]float64{
+			signPow((float64(v/(19*19))-9)/9, 2) * maxValue,
+			signPow((float64(v/19%19)-9)/9, 2) * maxValue,
+			signPow((float64(v%19)-9)/9, 2) * maxValue,
+		}
+	}
+
+	cosX := make([]float64, width*nx)
+	for x := 0; x < width; x++ {
+		for i := 0; i < nx; i++ {
+			cosX[x*nx+i] = math.Cos(math.Pi * float64(x*i) / float64(width))
+		}
+	}
+	cosY := make([]float64, height*ny)
+	for y := 0; y < height; y++ {
+		for j := 0; j < ny; j++ {
+			cosY[y*ny+j] = math.Cos(math.Pi * float64(y*j) / float64(height))
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b float64
+			for j := 0; j < ny; j++ {
+				for i := 0; i < nx; i++ {
+					basis := cosX[x*nx+i] * cosY[y*ny+j]
+					c := colors[i+j*nx]
+					r += c[0] * basis
+					g += c[1] * basis
+					b += c[2] * basis
+				}
+			}
+			img.SetNRGBA(x, y, color.NRGBA{linearToSRGB(r), linearToSRGB(g), linearToSRGB(b), 255})
+		}
+	}
+	return img, nil
+}
+
+func decodeBase83(s string) (int, error) {
+	v := 0
+	for _, c := range s {
+		i := strings.IndexRune(blurhashChars, c)
+		if i < 0 {
+			return 0, errors.New("invalid blurhash: bad character")
+		}
+		v = v*83 + i
+	}
+	return v, nil
+}
+
+func srgbToLinear(v int) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(f float64) uint8 {
+	f = math.Max(0, math.Min(1, f))
+	if f <= 0.0031308 {
+		return uint8(f*12.92*255 + 0.5)
+	}
+	return uint8((1.055*math.Pow(f, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
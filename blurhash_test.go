@@ -0,0 +1,28 @@
+package mastodon
+
+import (
+	"testing"
+)
+
+func TestDecodeBlurhash(t *testing.T) {
+	img, err := DecodeBlurhash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 32, 24)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 24 {
+		t.Fatalf("want 32x24 but %dx%d", b.Dx(), b.Dy())
+	}
+	_, _, _, a := img.At(10, 10).RGBA()
+	if a != 0xffff {
+		t.Fatalf("want opaque pixel but alpha %d", a)
+	}
+
+	for _, hash := range []string{"", "LEHV6nWB2yk8", "LEHV6nWB2yk8pyo0adR*.7kCMdn!"} {
+		if _, err := DecodeBlurhash(hash, 32, 24); err == nil {
+			t.Fatalf("should be fail: %q", hash)
+		}
+	}
+	if _, err := DecodeBlurhash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 0, 24); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
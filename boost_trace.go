@@ -0,0 +1,102 @@
+package mastodon
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BoostTrace holds the result of BoostTracer.Trace.
+type BoostTrace struct {
+	// Origin is the original status at the start of the reblog chain.
+	Origin *Status
+	// Boosters holds the accounts that boosted Origin, as far as the
+	// instance knows about them.
+	Boosters []*Account
+	// Domains holds the number of boosters per instance domain.
+	Domains map[string]int
+	// Reach is the sum of the followers of the author and all boosters.
+	// Followers shared between accounts are counted more than once, so it
+	// is an upper bound of the accounts the status was delivered to.
+	Reach int64
+	// Complete reports whether all boosts counted by the instance were
+	// enumerated.
+	Complete bool
+}
+
+// BoostTracer traces how a status spread through boosts.
+type BoostTracer struct {
+	Client *Client
+	// MaxBoosters is the maximum number of boosters fetched; zero fetches
+	// all of them.
+	MaxBoosters int
+	// Interval is waited before fetching each further page of boosters.
+	// Zero means one second and negative values disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoints of the traces, named after the original
+	// status, so an interrupted trace resumes where it left off; nil
+	// disables resuming.
+	Jobs CheckpointStore
+}
+
+// Trace follows the reblog chain of status to the original status and
+// enumerates the accounts that boosted it.
+func (t *BoostTracer) Trace(ctx context.Context, status *Status) (*BoostTrace, error) {
+	origin := status
+	for origin.Reblog != nil {
+		origin = origin.Reblog
+	}
+	interval := pageInterval(t.Interval)
+
+	r := &BoostTrace{
+		Origin:  origin,
+		Domains: map[string]int{},
+		Reach:   origin.Account.FollowersCount,
+	}
+	seen := map[ID]bool{}
+	job := "boost-trace-" + string(origin.ID)
+	state := &struct {
+		Boosters *[]*Account     `json:"boosters"`
+		Domains  *map[string]int `json:"domains"`
+		Reach    *int64          `json:"reach"`
+		Seen     *map[ID]bool    `json:"seen"`
+	}{&r.Boosters, &r.Domains, &r.Reach, &seen}
+	err := walkJob(ctx, t.Jobs, job, state, interval, &Pagination{Limit: 80}, func(pg *Pagination) (bool, error) {
+		accounts, err := t.Client.GetRebloggedBy(ctx, origin.ID, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			if t.MaxBoosters > 0 && len(r.Boosters) >= t.MaxBoosters {
+				return false, nil
+			}
+			if seen[a.ID] {
+				continue
+			}
+			seen[a.ID] = true
+			r.Boosters = append(r.Boosters, a)
+			r.Domains[t.domain(a)]++
+			r.Reach += a.FollowersCount
+		}
+		return len(accounts) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := deleteJobs(ctx, t.Jobs, job); err != nil {
+		return nil, err
+	}
+	r.Complete = int64(len(r.Boosters)) >= origin.ReblogsCount
+	return r, nil
+}
+
+func (t *BoostTracer) domain(a *Account) string {
+	if i := strings.IndexByte(a.Acct, '@'); i >= 0 {
+		return a.Acct[i+1:]
+	}
+	if u, err := url.Parse(t.Client.Config.Server); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return ""
+}
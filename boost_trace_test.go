@@ -0,0 +1,106 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBoostTracer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/1/reblogged_by" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.FormValue("max_id") == "" {
+			w.Header().Set("Link", `<http://example.com/api/v1/statuses/1/reblogged_by?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3", "acct": "alice", "followers_count": 10}, {"id": "2", "acct": "bob@example.org", "followers_count": 5}]`)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "1", "acct": "carol@example.org", "followers_count": 1}]`)
+	}))
+	defer ts.Close()
+
+	tracer := &BoostTracer{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Interval: -1,
+	}
+	boost := &Status{
+		ID:     "9",
+		Reblog: &Status{ID: "1", ReblogsCount: 4, Account: Account{FollowersCount: 100}},
+	}
+	r, err := tracer.Trace(context.Background(), boost)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if r.Origin.ID != "1" {
+		t.Fatalf("want %q but %q", "1", r.Origin.ID)
+	}
+	if len(r.Boosters) != 3 {
+		t.Fatalf("result should be three: %d", len(r.Boosters))
+	}
+	if r.Reach != 116 {
+		t.Fatalf("want %v but %v", 116, r.Reach)
+	}
+	if r.Domains["example.org"] != 2 || len(r.Domains) != 2 {
+		t.Fatalf("unexpected domains: %v", r.Domains)
+	}
+	if r.Complete {
+		t.Fatal("trace should not be complete")
+	}
+
+	tracer.MaxBoosters = 1
+	r, err = tracer.Trace(context.Background(), boost)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(r.Boosters) != 1 {
+		t.Fatalf("result should be one: %d", len(r.Boosters))
+	}
+}
+
+func TestBoostTracerResume(t *testing.T) {
+	var firstPages int
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("max_id") == "" {
+			firstPages++
+			w.Header().Set("Link", `<http://example.com/api/v1/statuses/1/reblogged_by?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3", "acct": "alice", "followers_count": 10}, {"id": "2", "acct": "bob@example.org", "followers_count": 5}]`)
+			return
+		}
+		if fail {
+			fail = false
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "1", "acct": "carol@example.org", "followers_count": 1}]`)
+	}))
+	defer ts.Close()
+
+	jobs := &MemoryCheckpointStore{}
+	tracer := &BoostTracer{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Interval: -1,
+		Jobs:     jobs,
+	}
+	status := &Status{ID: "1", ReblogsCount: 3, Account: Account{FollowersCount: 100}}
+	if _, err := tracer.Trace(context.Background(), status); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	r, err := tracer.Trace(context.Background(), status)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if firstPages != 1 {
+		t.Fatalf("first page should be fetched once: %d", firstPages)
+	}
+	if len(r.Boosters) != 3 || r.Reach != 116 || r.Domains["example.org"] != 2 || !r.Complete {
+		t.Fatalf("unexpected trace: %+v", r)
+	}
+	if cp, _ := jobs.Load(context.Background(), "boost-trace-1"); cp != nil {
+		t.Fatalf("checkpoint should be deleted: %+v", cp)
+	}
+}
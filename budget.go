@@ -0,0 +1,168 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateBudget splits the rate limit of an account between several consumers
+// in the same process, so that background jobs can't use up the requests
+// needed by interactive ones.
+//
+// Every consumer is guaranteed a share of Limit proportional to its
+// priority. A consumer that used up its share may borrow the unused shares
+// of consumers with a lower priority, but never those of consumers with the
+// same or a higher priority.
+type RateBudget struct {
+	// Limit is the number of requests allowed per Window; defaults to 300.
+	Limit int
+	// Window defaults to five minutes, the rate limit period of Mastodon.
+	Window time.Duration
+
+	mu        sync.Mutex
+	consumers []*BudgetConsumer
+}
+
+// BudgetConsumer is a consumer registered with a RateBudget.
+type BudgetConsumer struct {
+	Name     string
+	Priority int
+
+	budget *RateBudget
+	used   []time.Time
+}
+
+// Register adds a consumer with priority, which must be positive.
+func (b *RateBudget) Register(name string, priority int) *BudgetConsumer {
+	if priority < 1 {
+		priority = 1
+	}
+	c := &BudgetConsumer{Name: name, Priority: priority, budget: b}
+	b.mu.Lock()
+	b.consumers = append(b.consumers, c)
+	b.mu.Unlock()
+	return c
+}
+
+func (b *RateBudget) limit() int {
+	if b.Limit <= 0 {
+		return 300
+	}
+	return b.Limit
+}
+
+func (b *RateBudget) window() time.Duration {
+	if b.Window <= 0 {
+		return 5 * time.Minute
+	}
+	return b.Window
+}
+
+// share returns the number of requests reserved for c. b.mu must be held.
+func (b *RateBudget) share(c *BudgetConsumer) int {
+	total := 0
+	for _, o := range b.consumers {
+		total += o.Priority
+	}
+	return b.limit() * c.Priority / total
+}
+
+// take records a request of c if the budget allows it. Otherwise it returns
+// the time at which the oldest request in the window expires.
+func (b *RateBudget) take(c *BudgetConsumer, now time.Time) (bool, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	since := now.Add(-b.window())
+	total := 0
+	var next time.Time
+	for _, o := range b.consumers {
+		i := 0
+		for i < len(o.used) && !o.used[i].After(since) {
+			i++
+		}
+		o.used = o.used[i:]
+		total += len(o.used)
+		if len(o.used) > 0 && (next.IsZero() || o.used[0].Before(next)) {
+			next = o.used[0]
+		}
+	}
+
+	ok := false
+	if total < b.limit() {
+		if len(c.used) < b.share(c) {
+			ok = true
+		} else {
+			reserved := 0
+			for _, o := range b.consumers {
+				if o == c || o.Priority < c.Priority {
+					continue
+				}
+				if unused := b.share(o) - len(o.used); unused > 0 {
+					reserved += unused
+				}
+			}
+			ok = total+reserved < b.limit()
+		}
+	}
+	if ok {
+		c.used = append(c.used, now)
+		return true, time.Time{}
+	}
+	if next.IsZero() {
+		next = now
+	}
+	return false, next.Add(b.window())
+}
+
+// Wait blocks until the consumer may send a request and records it.
+func (c *BudgetConsumer) Wait(ctx context.Context) error {
+	for {
+		ok, next := c.budget.take(c, time.Now())
+		if ok {
+			return nil
+		}
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Allow reports whether the consumer may send a request now and, if so,
+// records it. It doesn't block.
+func (c *BudgetConsumer) Allow() bool {
+	ok, _ := c.budget.take(c, time.Now())
+	return ok
+}
+
+// Transport returns an http.RoundTripper that waits for the budget of the
+// consumer before sending each request with next, or
+// http.DefaultTransport if next is nil. Set it as the Transport of the
+// Client used by the consumer:
+//
+//	client := mastodon.NewClient(config)
+//	client.Transport = archiver.Transport(nil)
+func (c *BudgetConsumer) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &budgetTransport{consumer: c, next: next}
+}
+
+type budgetTransport struct {
+	consumer *BudgetConsumer
+	next     http.RoundTripper
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.consumer.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,81 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateBudget(t *testing.T) {
+	b := &RateBudget{Limit: 10, Window: time.Hour}
+	user := b.Register("user", 3)
+	archiver := b.Register("archiver", 1)
+	poller := b.Register("poller", 1)
+
+	// The archiver gets its own share of two and may not borrow the unused
+	// share of the user.
+	n := 0
+	for archiver.Allow() {
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("want %v but %v", 2, n)
+	}
+
+	// The user gets its share of six and may borrow the share of the
+	// lower priority poller.
+	n = 0
+	for user.Allow() {
+		n++
+	}
+	if n != 8 {
+		t.Fatalf("want %v but %v", 8, n)
+	}
+	if poller.Allow() {
+		t.Fatal("budget should be used up")
+	}
+}
+
+func TestBudgetConsumerWait(t *testing.T) {
+	b := &RateBudget{Limit: 1, Window: 50 * time.Millisecond}
+	c := b.Register("user", 1)
+	if err := c.Wait(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	start := time.Now()
+	if err := c.Wait(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if time.Since(start) < 40*time.Millisecond {
+		t.Fatal("second request should wait for the window")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Wait(ctx); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestBudgetConsumerTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	b := &RateBudget{Limit: 1, Window: time.Hour}
+	client := NewClient(&Config{Server: ts.URL})
+	client.Transport = b.Register("user", 1).Transport(nil)
+
+	if _, err := client.GetAccount(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetAccount(ctx, "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
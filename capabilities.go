@@ -0,0 +1,171 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a version number of the form major.minor.patch.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var reVersion = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// ParseVersion parses the leading version number of s, ignoring suffixes
+// like "-beta.1" or "+glitch". Missing minor and patch numbers are zero.
+func ParseVersion(s string) (Version, error) {
+	m := reVersion.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	var n [3]int
+	for i, v := range m[1:] {
+		if v != "" {
+			n[i], _ = strconv.Atoi(v)
+		}
+	}
+	return Version{Major: n[0], Minor: n[1], Patch: n[2]}, nil
+}
+
+// AtLeast reports whether v is major.minor.patch or newer.
+func (v Version) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+	return v.Patch >= patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Server software reported by Capabilities.Software.
+const (
+	SoftwareMastodon   = "mastodon"
+	SoftwarePleroma    = "pleroma"
+	SoftwareAkkoma     = "akkoma"
+	SoftwareGoToSocial = "gotosocial"
+)
+
+// Capabilities tells which features an instance supports, from its version
+// and configuration.
+type Capabilities struct {
+	Info *InstanceInfo
+	// Version is the version of the Mastodon API the instance implements.
+	// It is zero for other software which doesn't tell it, like
+	// GoToSocial.
+	Version Version
+	// Software is the lowercased name of the server software, like
+	// "mastodon" or "gotosocial", and SoftwareVersion its own version.
+	// They are taken from version strings like
+	// "2.7.2 (compatible; Pleroma 2.5.0)", else from the source URL of the
+	// instance or its NodeInfo.
+	Software        string
+	SoftwareVersion Version
+}
+
+var reCompatible = regexp.MustCompile(`\(compatible; ([^\s;)]+)[ /]?([^\s;)]*)`)
+
+func newCapabilities(info *InstanceInfo) *Capabilities {
+	caps := &Capabilities{Info: info, Software: SoftwareMastodon}
+	caps.Version, _ = ParseVersion(info.Version)
+	caps.SoftwareVersion = caps.Version
+	if m := reCompatible.FindStringSubmatch(info.Version); m != nil {
+		caps.Software = strings.ToLower(m[1])
+		caps.SoftwareVersion, _ = ParseVersion(m[2])
+		return caps
+	}
+	source := strings.ToLower(info.SourceURL)
+	for _, software := range []string{SoftwareGoToSocial, SoftwareAkkoma, SoftwarePleroma} {
+		if strings.Contains(source, software) {
+			caps.setSoftware(software, info.Version)
+			break
+		}
+	}
+	return caps
+}
+
+// setSoftware sets the software of an instance whose version string is the
+// version of the software rather than of the Mastodon API.
+func (c *Capabilities) setSoftware(name, version string) {
+	c.Software = strings.ToLower(name)
+	c.SoftwareVersion, _ = ParseVersion(version)
+	if c.Software != SoftwareMastodon {
+		c.Version = Version{}
+	}
+}
+
+// Capabilities returns the capabilities of the instance from
+// GetInstanceInfo. The software of the instance is then also returned by
+// ServerSoftware.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	info, err := c.GetInstanceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caps := newCapabilities(info)
+	// The v1 API has no source URL, like older versions of GoToSocial: ask
+	// NodeInfo unless the version string already told the software.
+	if info.SourceURL == "" && !reCompatible.MatchString(info.Version) {
+		if nodeInfo, err := c.GetNodeInfo(ctx); err == nil && nodeInfo.Software.Name != "" {
+			caps.setSoftware(nodeInfo.Software.Name, nodeInfo.Software.Version)
+		}
+	}
+	c.softwareMu.Lock()
+	c.software = caps.Software
+	c.softwareMu.Unlock()
+	return caps, nil
+}
+
+// ServerSoftware returns the lowercased name of the server software, like
+// "mastodon" or "gotosocial", once detected by Capabilities, or "" before.
+func (c *Client) ServerSoftware() string {
+	c.softwareMu.Lock()
+	defer c.softwareMu.Unlock()
+	return c.software
+}
+
+// SupportsEditing reports whether statuses can be edited with UpdateStatus.
+func (c *Capabilities) SupportsEditing() bool {
+	switch c.Software {
+	case SoftwarePleroma:
+		return c.SoftwareVersion.AtLeast(2, 5, 0)
+	case SoftwareAkkoma:
+		return c.SoftwareVersion.AtLeast(3, 0, 0)
+	case SoftwareGoToSocial:
+		return c.SoftwareVersion.AtLeast(0, 18, 0)
+	}
+	return c.Version.AtLeast(3, 5, 0)
+}
+
+// SupportsTranslation reports whether statuses can be translated with
+// TranslateStatus. Only the v2 instance API tells whether translation is
+// enabled, so it is false for instances without it.
+func (c *Capabilities) SupportsTranslation() bool {
+	return c.Info.TranslationEnabled && c.Version.AtLeast(4, 0, 0)
+}
+
+// SupportsReactions reports whether statuses can get emoji reactions, an
+// extension of Pleroma and Akkoma. Mastodon only has reactions to
+// announcements.
+func (c *Capabilities) SupportsReactions() bool {
+	return c.Software == SoftwarePleroma || c.Software == SoftwareAkkoma
+}
+
+// MaxTootChars returns the maximum length of a status, or 500, the default
+// of Mastodon, when the instance doesn't publish it.
+func (c *Capabilities) MaxTootChars() int {
+	if c.Info.Limits.MaxCharacters > 0 {
+		return c.Info.Limits.MaxCharacters
+	}
+	return 500
+}
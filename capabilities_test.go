@@ -0,0 +1,153 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Version
+	}{
+		{"4.2.0", Version{4, 2, 0}},
+		{"4.3.0-beta.1", Version{4, 3, 0}},
+		{"3.5.3+glitch", Version{3, 5, 3}},
+		{"2.7.2 (compatible; Pleroma 2.5.0)", Version{2, 7, 2}},
+		{"v0.15", Version{0, 15, 0}},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.s)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if v != tt.want {
+			t.Fatalf("want %v but %v", tt.want, v)
+		}
+	}
+	if _, err := ParseVersion("unknown"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if !(Version{4, 0, 0}).AtLeast(3, 5, 0) || (Version{3, 4, 9}).AtLeast(3, 5, 0) || !(Version{3, 5, 0}).AtLeast(3, 5, 0) {
+		t.Fatal("AtLeast should compare major, minor and patch in order")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/instance":
+			fmt.Fprintln(w, `{"domain": "mastodon.social", "version": "4.2.0", "configuration": {"statuses": {"max_characters": 1000}, "translation": {"enabled": true}}}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if caps.Software != SoftwareMastodon {
+		t.Fatalf("want %q but %q", SoftwareMastodon, caps.Software)
+	}
+	if caps.Version != (Version{4, 2, 0}) {
+		t.Fatalf("want %v but %v", Version{4, 2, 0}, caps.Version)
+	}
+	if !caps.SupportsEditing() || !caps.SupportsTranslation() || caps.SupportsReactions() {
+		t.Fatalf("unexpected capabilities of Mastodon 4.2.0: %+v", caps)
+	}
+	if caps.MaxTootChars() != 1000 {
+		t.Fatalf("want %d but %d", 1000, caps.MaxTootChars())
+	}
+}
+
+func TestCapabilitiesFallback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "pleroma.example", "version": "2.7.2 (compatible; Pleroma 2.5.0)"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if caps.Software != SoftwarePleroma {
+		t.Fatalf("want %q but %q", SoftwarePleroma, caps.Software)
+	}
+	if caps.SoftwareVersion != (Version{2, 5, 0}) {
+		t.Fatalf("want %v but %v", Version{2, 5, 0}, caps.SoftwareVersion)
+	}
+	if !caps.SupportsEditing() || caps.SupportsTranslation() || !caps.SupportsReactions() {
+		t.Fatalf("unexpected capabilities of Pleroma 2.5.0: %+v", caps)
+	}
+	if caps.MaxTootChars() != 500 {
+		t.Fatalf("want %d but %d", 500, caps.MaxTootChars())
+	}
+
+	client = NewClient(&Config{Server: ts.URL + "/missing"})
+	if _, err := client.Capabilities(context.Background()); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestCapabilitiesGoToSocial(t *testing.T) {
+	var v2 bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/instance":
+			if v2 {
+				fmt.Fprintln(w, `{"domain": "gts.example", "version": "0.18.1+git-abc", "source_url": "https://github.com/superseriousbusiness/gotosocial"}`)
+				return
+			}
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "gts.example", "version": "0.16.0 git-abc", "urls": {"streaming_api": "wss://gts.example"}}`)
+			return
+		case "/.well-known/nodeinfo":
+			fmt.Fprintf(w, `{"links": [{"rel": "http://nodeinfo.diaspora.software/ns/schema/2.0", "href": "/nodeinfo/2.0"}]}`)
+			return
+		case "/nodeinfo/2.0":
+			fmt.Fprintln(w, `{"version": "2.0", "software": {"name": "gotosocial", "version": "0.16.0 git-abc"}}`)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	if s := client.ServerSoftware(); s != "" {
+		t.Fatalf("want %q but %q", "", s)
+	}
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if s := client.ServerSoftware(); s != SoftwareGoToSocial {
+		t.Fatalf("want %q but %q", SoftwareGoToSocial, s)
+	}
+	if caps.Version != (Version{}) || caps.SoftwareVersion != (Version{0, 16, 0}) {
+		t.Fatalf("unexpected versions: %v %v", caps.Version, caps.SoftwareVersion)
+	}
+	if caps.SupportsEditing() || caps.SupportsTranslation() || caps.SupportsReactions() {
+		t.Fatalf("unexpected capabilities of GoToSocial 0.16.0: %+v", caps)
+	}
+
+	v2 = true
+	caps, err = client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if caps.Software != SoftwareGoToSocial || caps.SoftwareVersion != (Version{0, 18, 1}) || !caps.SupportsEditing() {
+		t.Fatalf("unexpected capabilities of GoToSocial 0.18.1: %+v", caps)
+	}
+}
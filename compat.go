@@ -2,13 +2,24 @@ package mastodon
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 )
 
+// ID is the identifier of an entity. Mastodon IDs are strings of digits,
+// while other servers send numbers or other strings, like the FlakeIDs of
+// Pleroma and Akkoma.
 type ID string
 
+// UnmarshalJSON accepts the ID as a string or as an integer, and leaves it
+// empty when null.
 func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
 	if len(data) > 0 && data[0] == '"' && data[len(data)-1] == '"' {
 		var s string
 		if err := json.Unmarshal(data, &s); err != nil {
@@ -17,14 +28,29 @@ func (id *ID) UnmarshalJSON(data []byte) error {
 		*id = ID(s)
 		return nil
 	}
-	var n int64
+	var n json.Number
 	if err := json.Unmarshal(data, &n); err != nil {
 		return err
 	}
-	*id = ID(fmt.Sprint(n))
+	if strings.ContainsAny(string(n), ".eE") {
+		return fmt.Errorf("invalid ID %s", data)
+	}
+	*id = ID(n)
 	return nil
 }
 
+// IsZero reports whether id is empty, as for a missing ID.
+func (id ID) IsZero() bool {
+	return id == ""
+}
+
+// Int64 returns the value of a numeric ID. ok is false for other IDs, like
+// FlakeIDs.
+func (id ID) Int64() (n int64, ok bool) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	return n, err == nil
+}
+
 type Sbool bool
 
 func (s *Sbool) UnmarshalJSON(data []byte) error {
@@ -47,3 +73,33 @@ func (s *Sbool) UnmarshalJSON(data []byte) error {
 	*s = Sbool(b)
 	return nil
 }
+
+// Compare returns -1 if id is older than other, 1 if it is newer and 0 if
+// they are equal. Mastodon IDs are decimal numbers and Pleroma and Akkoma
+// IDs are base62 FlakeIDs, whose digits sort in ASCII order, so a shorter
+// ID is older and IDs of the same length compare as strings.
+func (id ID) Compare(other ID) int {
+	switch {
+	case len(id) < len(other):
+		return -1
+	case len(id) > len(other):
+		return 1
+	case id < other:
+		return -1
+	case id > other:
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether id sorts before other, that is whether it is older.
+func (id ID) Less(other ID) bool {
+	return id.Compare(other) < 0
+}
+
+// notFound reports whether err is an APIError for a missing endpoint or
+// resource, as returned by servers not implementing an endpoint.
+func notFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
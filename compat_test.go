@@ -0,0 +1,113 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func TestIDUnmarshalJSON(t *testing.T) {
+	var v struct {
+		A, B, C, D ID
+	}
+	err := json.Unmarshal([]byte(`{"A": "109348227365470381", "B": 109348227365470381, "C": "AbCdEf0123456789xy", "D": null}`), &v)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if v.A != "109348227365470381" || v.B != v.A || v.C != "AbCdEf0123456789xy" || !v.D.IsZero() {
+		t.Fatalf("unexpected IDs: %+v", v)
+	}
+	if err := json.Unmarshal([]byte(`{"A": 1.5}`), &v); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"A": true}`), &v); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestIDInt64(t *testing.T) {
+	if n, ok := ID("109348227365470381").Int64(); !ok || n != 109348227365470381 {
+		t.Fatalf("want %d but %d", int64(109348227365470381), n)
+	}
+	if _, ok := ID("AbCdEf0123456789xy").Int64(); ok {
+		t.Fatal("FlakeID should not be numeric")
+	}
+	if _, ok := ID("").Int64(); ok {
+		t.Fatal("empty ID should not be numeric")
+	}
+}
+
+func TestIDCompare(t *testing.T) {
+	tests := []struct {
+		a, b ID
+		want int
+	}{
+		{"9", "10", -1},
+		{"10", "9", 1},
+		{"110", "110", 0},
+		{"AbC", "Abc", -1},
+		{"", "1", -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Fatalf("want %d but %d for %q and %q", tt.want, got, tt.a, tt.b)
+		}
+	}
+}
+
+func TestIDLess(t *testing.T) {
+	ids := []ID{"110", "99", "109348227365470381", "9"}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+	if want := []ID{"9", "99", "110", "109348227365470381"}; !equalIDs(ids, want) {
+		t.Fatalf("want %v but %v", want, ids)
+	}
+
+	flakes := []ID{"AbCdEf0123456789xy", "AbCdEf0123456789XY", "0bCdEf0123456789xy"}
+	sort.Slice(flakes, func(i, j int) bool { return flakes[i].Less(flakes[j]) })
+	if want := []ID{"0bCdEf0123456789xy", "AbCdEf0123456789XY", "AbCdEf0123456789xy"}; !equalIDs(flakes, want) {
+		t.Fatalf("want %v but %v", want, flakes)
+	}
+}
+
+func equalIDs(a, b []ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPleromaExtensions(t *testing.T) {
+	var s Status
+	err := json.Unmarshal([]byte(`{
+		"id": "AbCdEf0123456789xy",
+		"account": {"id": "A1b2C3", "acct": "foo", "pleroma": {"is_admin": true}},
+		"pleroma": {"local": true, "emoji_reactions": [{"name": "👍", "count": 2, "me": false}]}
+	}`), &s)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if s.ID != "AbCdEf0123456789xy" || s.Account.ID != "A1b2C3" {
+		t.Fatalf("unexpected IDs: %q %q", s.ID, s.Account.ID)
+	}
+	var pleroma struct {
+		Local          bool `json:"local"`
+		EmojiReactions []struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `json:"emoji_reactions"`
+	}
+	if err := json.Unmarshal(s.Pleroma, &pleroma); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !pleroma.Local || len(pleroma.EmojiReactions) != 1 || pleroma.EmojiReactions[0].Count != 2 {
+		t.Fatalf("unexpected pleroma object: %s", s.Pleroma)
+	}
+	if string(s.Account.Pleroma) != `{"is_admin": true}` {
+		t.Fatalf("want %q but %q", `{"is_admin": true}`, s.Account.Pleroma)
+	}
+}
@@ -0,0 +1,342 @@
+package mastodon
+
+import "fmt"
+
+// NotificationType is the type of a Notification.
+type NotificationType string
+
+// Notification types.
+const (
+	NotificationTypeMention              NotificationType = "mention"
+	NotificationTypeStatus               NotificationType = "status"
+	NotificationTypeReblog               NotificationType = "reblog"
+	NotificationTypeFollow               NotificationType = "follow"
+	NotificationTypeFollowRequest        NotificationType = "follow_request"
+	NotificationTypeFavourite            NotificationType = "favourite"
+	NotificationTypePoll                 NotificationType = "poll"
+	NotificationTypeUpdate               NotificationType = "update"
+	NotificationTypeAdminSignUp          NotificationType = "admin.sign_up"
+	NotificationTypeAdminReport          NotificationType = "admin.report"
+	NotificationTypeSeveredRelationships NotificationType = "severed_relationships"
+	NotificationTypeModerationWarning    NotificationType = "moderation_warning"
+)
+
+func (t NotificationType) String() string { return string(t) }
+
+// Valid reports whether t is a known notification type.
+func (t NotificationType) Valid() bool {
+	switch t {
+	case NotificationTypeMention, NotificationTypeStatus, NotificationTypeReblog,
+		NotificationTypeFollow, NotificationTypeFollowRequest, NotificationTypeFavourite,
+		NotificationTypePoll, NotificationTypeUpdate, NotificationTypeAdminSignUp,
+		NotificationTypeAdminReport, NotificationTypeSeveredRelationships,
+		NotificationTypeModerationWarning:
+		return true
+	}
+	return false
+}
+
+// ParseNotificationType returns the notification type s, or an error if it
+// isn't known.
+func ParseNotificationType(s string) (NotificationType, error) {
+	t := NotificationType(s)
+	if !t.Valid() {
+		return "", fmt.Errorf("unknown notification type %q", s)
+	}
+	return t, nil
+}
+
+// AttachmentType is the type of an Attachment.
+type AttachmentType string
+
+// Attachment types.
+const (
+	AttachmentTypeUnknown AttachmentType = "unknown"
+	AttachmentTypeImage   AttachmentType = "image"
+	AttachmentTypeGifv    AttachmentType = "gifv"
+	AttachmentTypeVideo   AttachmentType = "video"
+	AttachmentTypeAudio   AttachmentType = "audio"
+)
+
+func (t AttachmentType) String() string { return string(t) }
+
+// Valid reports whether t is a known attachment type.
+func (t AttachmentType) Valid() bool {
+	switch t {
+	case AttachmentTypeUnknown, AttachmentTypeImage, AttachmentTypeGifv,
+		AttachmentTypeVideo, AttachmentTypeAudio:
+		return true
+	}
+	return false
+}
+
+// ParseAttachmentType returns the attachment type s, or an error if it
+// isn't known.
+func ParseAttachmentType(s string) (AttachmentType, error) {
+	t := AttachmentType(s)
+	if !t.Valid() {
+		return "", fmt.Errorf("unknown attachment type %q", s)
+	}
+	return t, nil
+}
+
+// ReportCategory is the category of a Report.
+type ReportCategory string
+
+// Report categories.
+const (
+	ReportCategorySpam      ReportCategory = "spam"
+	ReportCategoryLegal     ReportCategory = "legal"
+	ReportCategoryViolation ReportCategory = "violation"
+	ReportCategoryOther     ReportCategory = "other"
+)
+
+func (c ReportCategory) String() string { return string(c) }
+
+// Valid reports whether c is a known report category.
+func (c ReportCategory) Valid() bool {
+	switch c {
+	case ReportCategorySpam, ReportCategoryLegal, ReportCategoryViolation, ReportCategoryOther:
+		return true
+	}
+	return false
+}
+
+// ParseReportCategory returns the report category s, or an error if it
+// isn't known.
+func ParseReportCategory(s string) (ReportCategory, error) {
+	c := ReportCategory(s)
+	if !c.Valid() {
+		return "", fmt.Errorf("unknown report category %q", s)
+	}
+	return c, nil
+}
+
+// FilterContext is where a filter applies.
+type FilterContext string
+
+// Filter contexts.
+const (
+	FilterContextHome          FilterContext = "home"
+	FilterContextNotifications FilterContext = "notifications"
+	FilterContextPublic        FilterContext = "public"
+	FilterContextThread        FilterContext = "thread"
+	FilterContextAccount       FilterContext = "account"
+)
+
+func (c FilterContext) String() string { return string(c) }
+
+// Valid reports whether c is a known filter context.
+func (c FilterContext) Valid() bool {
+	switch c {
+	case FilterContextHome, FilterContextNotifications, FilterContextPublic,
+		FilterContextThread, FilterContextAccount:
+		return true
+	}
+	return false
+}
+
+// ParseFilterContext returns the filter context s, or an error if it isn't
+// known.
+func ParseFilterContext(s string) (FilterContext, error) {
+	c := FilterContext(s)
+	if !c.Valid() {
+		return "", fmt.Errorf("unknown filter context %q", s)
+	}
+	return c, nil
+}
+
+// FilterAction is what happens to statuses matching a filter.
+type FilterAction string
+
+// Filter actions.
+const (
+	FilterActionWarn FilterAction = "warn"
+	FilterActionHide FilterAction = "hide"
+	FilterActionBlur FilterAction = "blur"
+)
+
+func (a FilterAction) String() string { return string(a) }
+
+// Valid reports whether a is a known filter action.
+func (a FilterAction) Valid() bool {
+	switch a {
+	case FilterActionWarn, FilterActionHide, FilterActionBlur:
+		return true
+	}
+	return false
+}
+
+// ParseFilterAction returns the filter action s, or an error if it isn't
+// known.
+func ParseFilterAction(s string) (FilterAction, error) {
+	a := FilterAction(s)
+	if !a.Valid() {
+		return "", fmt.Errorf("unknown filter action %q", s)
+	}
+	return a, nil
+}
+
+// RepliesPolicy is which replies a list shows.
+type RepliesPolicy string
+
+// Replies policies.
+const (
+	RepliesPolicyFollowed RepliesPolicy = "followed"
+	RepliesPolicyList     RepliesPolicy = "list"
+	RepliesPolicyNone     RepliesPolicy = "none"
+)
+
+func (p RepliesPolicy) String() string { return string(p) }
+
+// Valid reports whether p is a known replies policy.
+func (p RepliesPolicy) Valid() bool {
+	switch p {
+	case RepliesPolicyFollowed, RepliesPolicyList, RepliesPolicyNone:
+		return true
+	}
+	return false
+}
+
+// ParseRepliesPolicy returns the replies policy s, or an error if it isn't
+// known.
+func ParseRepliesPolicy(s string) (RepliesPolicy, error) {
+	p := RepliesPolicy(s)
+	if !p.Valid() {
+		return "", fmt.Errorf("unknown replies policy %q", s)
+	}
+	return p, nil
+}
+
+// CardType is the type of a preview Card.
+type CardType string
+
+// Preview card types.
+const (
+	CardTypeLink  CardType = "link"
+	CardTypePhoto CardType = "photo"
+	CardTypeVideo CardType = "video"
+	CardTypeRich  CardType = "rich"
+)
+
+func (t CardType) String() string { return string(t) }
+
+// Valid reports whether t is a known preview card type.
+func (t CardType) Valid() bool {
+	switch t {
+	case CardTypeLink, CardTypePhoto, CardTypeVideo, CardTypeRich:
+		return true
+	}
+	return false
+}
+
+// ParseCardType returns the preview card type s, or an error if it isn't
+// known.
+func ParseCardType(s string) (CardType, error) {
+	t := CardType(s)
+	if !t.Valid() {
+		return "", fmt.Errorf("unknown card type %q", s)
+	}
+	return t, nil
+}
+
+// NotificationPolicyAction is what a NotificationPolicy does with the
+// notifications of a kind of account.
+type NotificationPolicyAction string
+
+// Notification policy actions.
+const (
+	NotificationPolicyAccept NotificationPolicyAction = "accept"
+	NotificationPolicyFilter NotificationPolicyAction = "filter"
+	NotificationPolicyDrop   NotificationPolicyAction = "drop"
+)
+
+func (a NotificationPolicyAction) String() string { return string(a) }
+
+// Valid reports whether a is a known notification policy action.
+func (a NotificationPolicyAction) Valid() bool {
+	switch a {
+	case NotificationPolicyAccept, NotificationPolicyFilter, NotificationPolicyDrop:
+		return true
+	}
+	return false
+}
+
+// ParseNotificationPolicyAction returns the notification policy action s,
+// or an error if it isn't known.
+func ParseNotificationPolicyAction(s string) (NotificationPolicyAction, error) {
+	a := NotificationPolicyAction(s)
+	if !a.Valid() {
+		return "", fmt.Errorf("unknown notification policy action %q", s)
+	}
+	return a, nil
+}
+
+// PushPolicy restricts the accounts whose notifications are pushed to a
+// push subscription.
+type PushPolicy string
+
+// Push subscription policies.
+const (
+	PushPolicyAll      PushPolicy = "all"
+	PushPolicyFollowed PushPolicy = "followed"
+	PushPolicyFollower PushPolicy = "follower"
+	PushPolicyNone     PushPolicy = "none"
+)
+
+func (p PushPolicy) String() string { return string(p) }
+
+// Valid reports whether p is a known push subscription policy.
+func (p PushPolicy) Valid() bool {
+	switch p {
+	case PushPolicyAll, PushPolicyFollowed, PushPolicyFollower, PushPolicyNone:
+		return true
+	}
+	return false
+}
+
+// ParsePushPolicy returns the push subscription policy s, or an error if it
+// isn't known.
+func ParsePushPolicy(s string) (PushPolicy, error) {
+	p := PushPolicy(s)
+	if !p.Valid() {
+		return "", fmt.Errorf("unknown push policy %q", s)
+	}
+	return p, nil
+}
+
+// Visibility is who can see a status.
+type Visibility string
+
+// Visibilities of statuses.
+const (
+	VisibilityPublic   Visibility = "public"
+	VisibilityUnlisted Visibility = "unlisted"
+	VisibilityPrivate  Visibility = "private"
+	VisibilityDirect   Visibility = "direct"
+
+	// Deprecated: Use VisibilityPrivate.
+	VisibilityFollowersOnly = VisibilityPrivate
+	// Deprecated: Use VisibilityDirect.
+	VisibilityDirectMessage = VisibilityDirect
+)
+
+func (v Visibility) String() string { return string(v) }
+
+// Valid reports whether v is a known visibility.
+func (v Visibility) Valid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate, VisibilityDirect:
+		return true
+	}
+	return false
+}
+
+// ParseVisibility returns the visibility s, or an error if it isn't known.
+func ParseVisibility(s string) (Visibility, error) {
+	v := Visibility(s)
+	if !v.Valid() {
+		return "", fmt.Errorf("unknown visibility %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,97 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseEnums(t *testing.T) {
+	if tp, err := ParseNotificationType("admin.sign_up"); err != nil || tp != NotificationTypeAdminSignUp {
+		t.Fatalf("want %q but %q: %v", NotificationTypeAdminSignUp, tp, err)
+	}
+	if _, err := ParseNotificationType("mentions"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if tp, err := ParseAttachmentType("gifv"); err != nil || tp != AttachmentTypeGifv {
+		t.Fatalf("want %q but %q: %v", AttachmentTypeGifv, tp, err)
+	}
+	if _, err := ParseAttachmentType("gif"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if c, err := ParseReportCategory("legal"); err != nil || c != ReportCategoryLegal {
+		t.Fatalf("want %q but %q: %v", ReportCategoryLegal, c, err)
+	}
+	if _, err := ParseReportCategory(""); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if c, err := ParseFilterContext("thread"); err != nil || c != FilterContextThread {
+		t.Fatalf("want %q but %q: %v", FilterContextThread, c, err)
+	}
+	if _, err := ParseFilterContext("threads"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if a, err := ParseFilterAction("hide"); err != nil || a != FilterActionHide {
+		t.Fatalf("want %q but %q: %v", FilterActionHide, a, err)
+	}
+	if _, err := ParseFilterAction("drop"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if p, err := ParseRepliesPolicy("followed"); err != nil || p != RepliesPolicyFollowed {
+		t.Fatalf("want %q but %q: %v", RepliesPolicyFollowed, p, err)
+	}
+	if _, err := ParseRepliesPolicy("all"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if tp, err := ParseCardType("rich"); err != nil || tp != CardTypeRich {
+		t.Fatalf("want %q but %q: %v", CardTypeRich, tp, err)
+	}
+	if _, err := ParseCardType("image"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if v, err := ParseVisibility("private"); err != nil || v != VisibilityPrivate {
+		t.Fatalf("want %q but %q: %v", VisibilityPrivate, v, err)
+	}
+	if _, err := ParseVisibility("followers"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if a, err := ParseNotificationPolicyAction("drop"); err != nil || a != NotificationPolicyDrop {
+		t.Fatalf("want %q but %q: %v", NotificationPolicyDrop, a, err)
+	}
+	if _, err := ParseNotificationPolicyAction("hide"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if p, err := ParsePushPolicy("follower"); err != nil || p != PushPolicyFollower {
+		t.Fatalf("want %q but %q: %v", PushPolicyFollower, p, err)
+	}
+	if _, err := ParsePushPolicy("followers"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestEnumString(t *testing.T) {
+	if s := NotificationTypeFollowRequest.String(); s != "follow_request" {
+		t.Fatalf("want %q but %q", "follow_request", s)
+	}
+	if s := FilterContextNotifications.String(); s != "notifications" {
+		t.Fatalf("want %q but %q", "notifications", s)
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	var n Notification
+	err := json.Unmarshal([]byte(`{"type": "favourite"}`), &n)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if n.Type != NotificationTypeFavourite {
+		t.Fatalf("want %q but %q", NotificationTypeFavourite, n.Type)
+	}
+	// Unknown values from newer servers are kept.
+	err = json.Unmarshal([]byte(`{"type": "quote"}`), &n)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if n.Type != "quote" || n.Type.Valid() {
+		t.Fatalf("want invalid %q but %q", "quote", n.Type)
+	}
+}
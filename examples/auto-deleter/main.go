@@ -0,0 +1,66 @@
+// Command auto-deleter deletes your own statuses older than a given age.
+//
+// Pinned statuses and statuses with at least -keep-favourites favourites are
+// kept.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+func main() {
+	age := flag.Duration("age", 30*24*time.Hour, "delete statuses older than this")
+	keepFavourites := flag.Int64("keep-favourites", 10, "keep statuses with at least this many favourites")
+	dryRun := flag.Bool("dry-run", false, "only print the statuses that would be deleted")
+	flag.Parse()
+
+	c := mastodon.NewClient(&mastodon.Config{
+		Server:      os.Getenv("MASTODON_SERVER"),
+		AccessToken: os.Getenv("MASTODON_ACCESS_TOKEN"),
+	})
+	ctx := context.Background()
+
+	me, err := c.GetAccountCurrentUser(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cutoff := time.Now().Add(-*age)
+	var pg mastodon.Pagination
+	for {
+		statuses, err := c.GetAccountStatuses(ctx, me.ID, &pg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			if s.CreatedAt.After(cutoff) {
+				continue
+			}
+			if pinned, ok := s.Pinned.(bool); ok && pinned {
+				continue
+			}
+			if s.FavouritesCount >= *keepFavourites {
+				continue
+			}
+			if *dryRun {
+				log.Printf("would delete %s (%s)", s.URL, s.CreatedAt.Format(time.RFC3339))
+				continue
+			}
+			if err := c.DeleteStatus(ctx, s.ID); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("deleted %s", s.URL)
+		}
+		if len(statuses) == 0 || pg.MaxID == "" {
+			break
+		}
+		pg.SinceID = ""
+		pg.MinID = ""
+	}
+}
@@ -0,0 +1,61 @@
+// Command notification-digest prints a summary of your notifications grouped
+// by type.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+func main() {
+	since := flag.Duration("since", 24*time.Hour, "summarize notifications newer than this")
+	flag.Parse()
+
+	c := mastodon.NewClient(&mastodon.Config{
+		Server:      os.Getenv("MASTODON_SERVER"),
+		AccessToken: os.Getenv("MASTODON_ACCESS_TOKEN"),
+	})
+
+	cutoff := time.Now().Add(-*since)
+	byType := map[string][]*mastodon.Notification{}
+	var pg mastodon.Pagination
+walk:
+	for {
+		notifications, err := c.GetNotifications(context.Background(), &pg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, n := range notifications {
+			if n.CreatedAt.Before(cutoff) {
+				break walk
+			}
+			byType[n.Type.String()] = append(byType[n.Type.String()], n)
+		}
+		if len(notifications) == 0 || pg.MaxID == "" {
+			break
+		}
+		pg.SinceID = ""
+		pg.MinID = ""
+	}
+
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	fmt.Printf("Notifications since %s\n", cutoff.Format(time.RFC1123))
+	for _, t := range types {
+		fmt.Printf("\n%s (%d)\n", t, len(byType[t]))
+		for _, n := range byType[t] {
+			fmt.Printf("  @%s\n", n.Account.Acct)
+		}
+	}
+}
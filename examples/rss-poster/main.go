@@ -0,0 +1,102 @@
+// Command rss-poster posts new items of an RSS feed.
+//
+// The links of items that were already posted are kept in a state file so
+// that running the command periodically only posts each item once.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+type rss struct {
+	Channel struct {
+		Items []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func main() {
+	feed := flag.String("feed", "", "URL of the RSS feed")
+	state := flag.String("state", "rss-poster.state", "file with the links that were already posted")
+	visibility := flag.String("visibility", mastodon.VisibilityUnlisted.String(), "visibility of the posted statuses")
+	flag.Parse()
+	v, err := mastodon.ParseVisibility(*visibility)
+	if *feed == "" || err != nil {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	seen, err := readState(*state)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := http.Get(*feed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var doc rss
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		log.Fatal(err)
+	}
+
+	c := mastodon.NewClient(&mastodon.Config{
+		Server:      os.Getenv("MASTODON_SERVER"),
+		AccessToken: os.Getenv("MASTODON_ACCESS_TOKEN"),
+	})
+
+	f, err := os.OpenFile(*state, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	// Feeds list the newest item first; post in chronological order.
+	items := doc.Channel.Items
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if item.Link == "" || seen[item.Link] {
+			continue
+		}
+		_, err := c.PostStatus(context.Background(), &mastodon.Toot{
+			Status:     fmt.Sprintf("%s\n\n%s", item.Title, item.Link),
+			Visibility: v,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := fmt.Fprintln(f, item.Link); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("posted %s", item.Link)
+	}
+}
+
+func readState(name string) (map[string]bool, error) {
+	seen := map[string]bool{}
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return seen, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		seen[s.Text()] = true
+	}
+	return seen, s.Err()
+}
@@ -0,0 +1,57 @@
+// Command welcome-bot sends a direct message to every new follower.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+func main() {
+	message := flag.String("message", "Thanks for following! 👋", "message sent to new followers")
+	flag.Parse()
+
+	c := mastodon.NewClient(&mastodon.Config{
+		Server:      os.Getenv("MASTODON_SERVER"),
+		AccessToken: os.Getenv("MASTODON_ACCESS_TOKEN"),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	q, err := c.StreamingUser(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for e := range q {
+		switch event := e.(type) {
+		case *mastodon.NotificationEvent:
+			if event.Notification.Type != mastodon.NotificationTypeFollow {
+				continue
+			}
+			acct := event.Notification.Account.Acct
+			_, err := c.PostStatus(ctx, &mastodon.Toot{
+				Status:     fmt.Sprintf("@%s %s", acct, *message),
+				Visibility: mastodon.VisibilityDirectMessage,
+			})
+			if err != nil {
+				log.Printf("welcome %s: %v", acct, err)
+				continue
+			}
+			log.Printf("welcomed %s", acct)
+		case *mastodon.ErrorEvent:
+			log.Println(event)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exporter writes the data of the current user as CSV files in the layouts
+// of the Mastodon settings export, so they can be imported again through
+// the web interface of any instance.
+//
+// When Jobs is set, the paginated exports save a checkpoint after every
+// page and resume from it when run again, so a large export can survive a
+// restart. The header is only written when an export starts, so pass a
+// writer that appends to the output of the interrupted run. The checkpoint
+// is deleted once an export completes, so the next run exports everything
+// again.
+type Exporter struct {
+	Client *Client
+	// Domain is the domain appended to local accounts. It defaults to the
+	// URI of the instance.
+	Domain string
+	// Interval is waited between the pages of an export, so exporting
+	// thousands of accounts doesn't exhaust the rate limit. Zero means one
+	// second and negative values disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoints of the exports; nil disables resuming.
+	Jobs CheckpointStore
+}
+
+// ExportFollowing writes the accounts followed by the current user with the
+// columns "Account address", "Show boosts", "Notify on new posts" and
+// "Languages".
+func (e *Exporter) ExportFollowing(ctx context.Context, w io.Writer) error {
+	me, err := e.Client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	header := []string{"Account address", "Show boosts", "Notify on new posts", "Languages"}
+	return e.export(ctx, "export-following", w, header, func(pg *Pagination, cw *csv.Writer) (bool, error) {
+		accounts, err := e.Client.GetAccountFollowing(ctx, me.ID, pg)
+		if err != nil {
+			return false, err
+		}
+		rels, err := e.relationships(ctx, accounts)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			addr, err := e.address(ctx, a)
+			if err != nil {
+				return false, err
+			}
+			showReblogs, notify, languages := true, false, ""
+			if rel, ok := rels[a.ID]; ok {
+				showReblogs = rel.ShowingReblogs
+				notify = rel.Notifying
+				languages = strings.Join(rel.Languages, ", ")
+			}
+			cw.Write([]string{addr, strconv.FormatBool(showReblogs), strconv.FormatBool(notify), languages})
+		}
+		return len(accounts) > 0, nil
+	})
+}
+
+// ExportLists writes one row per list member with the list title and the
+// account address, without a header.
+func (e *Exporter) ExportLists(ctx context.Context, w io.Writer) error {
+	lists, err := e.Client.GetLists(ctx)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	for _, l := range lists {
+		accounts, err := e.Client.GetListAccounts(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range accounts {
+			addr, err := e.address(ctx, a)
+			if err != nil {
+				return err
+			}
+			cw.Write([]string{l.Title, addr})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportMutes writes the accounts muted by the current user with the
+// columns "Account address" and "Hide notifications".
+func (e *Exporter) ExportMutes(ctx context.Context, w io.Writer) error {
+	header := []string{"Account address", "Hide notifications"}
+	return e.export(ctx, "export-mutes", w, header, func(pg *Pagination, cw *csv.Writer) (bool, error) {
+		accounts, err := e.Client.GetMutes(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		rels, err := e.relationships(ctx, accounts)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			addr, err := e.address(ctx, a)
+			if err != nil {
+				return false, err
+			}
+			hide := true
+			if rel, ok := rels[a.ID]; ok {
+				hide = rel.MutingNotifications
+			}
+			cw.Write([]string{addr, strconv.FormatBool(hide)})
+		}
+		return len(accounts) > 0, nil
+	})
+}
+
+// ExportBlocks writes the addresses of the accounts blocked by the current
+// user, one per line, without a header.
+func (e *Exporter) ExportBlocks(ctx context.Context, w io.Writer) error {
+	return e.export(ctx, "export-blocks", w, nil, func(pg *Pagination, cw *csv.Writer) (bool, error) {
+		accounts, err := e.Client.GetBlocks(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			addr, err := e.address(ctx, a)
+			if err != nil {
+				return false, err
+			}
+			cw.Write([]string{addr})
+		}
+		return len(accounts) > 0, nil
+	})
+}
+
+// ExportBookmarks writes the URIs of the statuses bookmarked by the current
+// user, one per line, without a header.
+func (e *Exporter) ExportBookmarks(ctx context.Context, w io.Writer) error {
+	return e.export(ctx, "export-bookmarks", w, nil, func(pg *Pagination, cw *csv.Writer) (bool, error) {
+		statuses, err := e.Client.GetBookmarks(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range statuses {
+			cw.Write([]string{s.URI})
+		}
+		return len(statuses) > 0, nil
+	})
+}
+
+// export walks the pages with writePage, flushing the CSV after every page
+// so that the output matches the saved checkpoint.
+func (e *Exporter) export(ctx context.Context, name string, w io.Writer, header []string, writePage func(pg *Pagination, cw *csv.Writer) (bool, error)) error {
+	cw := csv.NewWriter(w)
+	fetch := func(pg *Pagination) (bool, error) {
+		more, err := writePage(pg, cw)
+		if err != nil {
+			return false, err
+		}
+		cw.Flush()
+		return more, cw.Error()
+	}
+
+	interval := pageInterval(e.Interval)
+	pg := &Pagination{Limit: 80}
+	if e.Jobs == nil {
+		if header != nil {
+			cw.Write(header)
+		}
+		if err := walkPages(ctx, interval, pg, fetch); err != nil {
+			return err
+		}
+	} else {
+		job := &Job{Name: name, Store: e.Jobs, Interval: interval}
+		cp, err := e.Jobs.Load(ctx, name)
+		if err != nil {
+			return err
+		}
+		if cp != nil && cp.Done {
+			// Left by a run that finished before it could delete it.
+			if err := job.Reset(ctx); err != nil {
+				return err
+			}
+			cp = nil
+		}
+		if cp == nil && header != nil {
+			cw.Write(header)
+		}
+		if err := job.Walk(ctx, pg, fetch); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if e.Jobs != nil {
+		// The export is complete, so the next run starts over.
+		return e.Jobs.Delete(ctx, name)
+	}
+	return nil
+}
+
+func (e *Exporter) relationships(ctx context.Context, accounts []*Account) (map[ID]*Relationship, error) {
+	rels := map[ID]*Relationship{}
+	for i := 0; i < len(accounts); i += 40 {
+		end := i + 40
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+		ids := make([]string, 0, end-i)
+		for _, a := range accounts[i:end] {
+			ids = append(ids, string(a.ID))
+		}
+		rs, err := e.Client.GetAccountRelationships(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			rels[r.ID] = r
+		}
+	}
+	return rels, nil
+}
+
+// address returns the full address of the account, user@domain.
+func (e *Exporter) address(ctx context.Context, a *Account) (string, error) {
+	if strings.Contains(a.Acct, "@") {
+		return a.Acct, nil
+	}
+	if e.Domain == "" {
+		instance, err := e.Client.GetInstance(ctx)
+		if err != nil {
+			return "", err
+		}
+		e.Domain = instance.URI
+	}
+	return a.Acct + "@" + e.Domain, nil
+}
@@ -0,0 +1,143 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExporter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "example.com"}`)
+		case "/api/v1/accounts/verify_credentials":
+			fmt.Fprintln(w, `{"id": "1"}`)
+		case "/api/v1/accounts/1/following":
+			fmt.Fprintln(w, `[{"id": "2", "acct": "alice"}, {"id": "3", "acct": "bob@example.org"}]`)
+		case "/api/v1/accounts/relationships":
+			fmt.Fprintln(w, `[{"id": "2", "showing_reblogs": false, "notifying": true, "languages": ["en", "de"], "muting_notifications": false}, {"id": "3", "showing_reblogs": true}]`)
+		case "/api/v1/lists":
+			fmt.Fprintln(w, `[{"id": "7", "title": "Friends, family"}]`)
+		case "/api/v1/lists/7/accounts":
+			fmt.Fprintln(w, `[{"id": "2", "acct": "alice"}]`)
+		case "/api/v1/mutes":
+			fmt.Fprintln(w, `[{"id": "2", "acct": "alice"}]`)
+		case "/api/v1/blocks":
+			fmt.Fprintln(w, `[{"id": "3", "acct": "bob@example.org"}]`)
+		case "/api/v1/bookmarks":
+			fmt.Fprintln(w, `[{"id": "9", "uri": "https://example.org/users/bob/statuses/9"}]`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	e := &Exporter{Client: NewClient(&Config{Server: ts.URL})}
+	tests := []struct {
+		name   string
+		export func(context.Context, *bytes.Buffer) error
+		want   string
+	}{
+		{"following", func(ctx context.Context, b *bytes.Buffer) error { return e.ExportFollowing(ctx, b) },
+			"Account address,Show boosts,Notify on new posts,Languages\nalice@example.com,false,true,\"en, de\"\nbob@example.org,true,false,\n"},
+		{"lists", func(ctx context.Context, b *bytes.Buffer) error { return e.ExportLists(ctx, b) },
+			"\"Friends, family\",alice@example.com\n"},
+		{"mutes", func(ctx context.Context, b *bytes.Buffer) error { return e.ExportMutes(ctx, b) },
+			"Account address,Hide notifications\nalice@example.com,false\n"},
+		{"blocks", func(ctx context.Context, b *bytes.Buffer) error { return e.ExportBlocks(ctx, b) },
+			"bob@example.org\n"},
+		{"bookmarks", func(ctx context.Context, b *bytes.Buffer) error { return e.ExportBookmarks(ctx, b) },
+			"https://example.org/users/bob/statuses/9\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.export(context.Background(), &buf); err != nil {
+			t.Fatalf("%s: should not be fail: %v", tt.name, err)
+		}
+		if buf.String() != tt.want {
+			t.Fatalf("%s: want %q but %q", tt.name, tt.want, buf.String())
+		}
+	}
+}
+
+func TestExporterResume(t *testing.T) {
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/blocks" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		switch r.FormValue("max_id") {
+		case "":
+			w.Header().Set("Link", `<http://example.com/api/v1/blocks?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3", "acct": "alice@example.org"}]`)
+		case "2":
+			if fail {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "1", "acct": "bob@example.org"}]`)
+		}
+	}))
+	defer ts.Close()
+
+	e := &Exporter{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Interval: -1,
+		Jobs:     &MemoryCheckpointStore{},
+	}
+	var buf bytes.Buffer
+	if err := e.ExportBlocks(context.Background(), &buf); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	fail = false
+	if err := e.ExportBlocks(context.Background(), &buf); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := "alice@example.org\nbob@example.org\n"
+	if buf.String() != want {
+		t.Fatalf("want %q but %q", want, buf.String())
+	}
+}
+
+func TestExporterRunTwice(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/mutes":
+			fmt.Fprintln(w, `[{"id": "2", "acct": "alice@example.org"}]`)
+		case "/api/v1/accounts/relationships":
+			fmt.Fprintln(w, `[{"id": "2", "muting_notifications": true}]`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	store := &FileCheckpointStore{Dir: t.TempDir()}
+	e := &Exporter{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Interval: -1,
+		Jobs:     store,
+	}
+	want := "Account address,Hide notifications\nalice@example.org,true\n"
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		if err := e.ExportMutes(context.Background(), &buf); err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if buf.String() != want {
+			t.Fatalf("run %d: want %q but %q", i+1, want, buf.String())
+		}
+	}
+	cp, err := store.Load(context.Background(), "export-mutes")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if cp != nil {
+		t.Fatalf("checkpoint should be deleted: %+v", cp)
+	}
+}
@@ -0,0 +1,93 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// decode decodes the response body data into res, and fills the Extra
+// fields of the results when c.ExtraFields is set.
+func (c *Client) decode(data []byte, res interface{}) error {
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	if c.ExtraFields {
+		fillExtra(data, reflect.ValueOf(res))
+	}
+	return nil
+}
+
+var rawMapType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// fillExtra sets the Extra fields of the structs of v, decoded from data,
+// to the members of their JSON objects they have no field for.
+func fillExtra(data json.RawMessage, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			fillExtra(items[i], v.Index(i))
+		}
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) != nil {
+			return
+		}
+		known := map[string]bool{}
+		fillStructExtra(members, known, v)
+		extra := v.FieldByName("Extra")
+		if !extra.IsValid() || extra.Type() != rawMapType || !extra.CanSet() {
+			return
+		}
+		m := map[string]json.RawMessage{}
+		for name, value := range members {
+			if !known[name] {
+				m[name] = value
+			}
+		}
+		if len(m) > 0 {
+			extra.Set(reflect.ValueOf(m))
+		}
+	}
+}
+
+// fillStructExtra fills the Extra fields of the fields of the struct v from
+// the members of its JSON object, and marks them known.
+func fillStructExtra(members map[string]json.RawMessage, known map[string]bool, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			fillStructExtra(members, known, v.Field(i))
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		for key, value := range members {
+			// encoding/json matches names case-insensitively.
+			if strings.EqualFold(key, name) {
+				known[key] = true
+				fillExtra(value, v.Field(i))
+			}
+		}
+	}
+}
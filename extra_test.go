@@ -0,0 +1,60 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtraFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/timelines/home":
+			fmt.Fprintln(w, `[{
+				"id": "1", "content": "hi", "local_only": true,
+				"account": {"id": "2", "acct": "foo", "Bot": true, "other_settings": {"x": 1}},
+				"reblog": {"id": "3", "quote": {"id": "4"}}
+			}]`)
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "glitch.example", "max_toot_chars": 5000}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	statuses, err := client.GetTimelineHome(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if statuses[0].Extra != nil {
+		t.Fatalf("want no extra fields but %v", statuses[0].Extra)
+	}
+
+	client.ExtraFields = true
+	statuses, err = client.GetTimelineHome(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	s := statuses[0]
+	if s.Content != "hi" || len(s.Extra) != 1 || string(s.Extra["local_only"]) != "true" {
+		t.Fatalf("unexpected status extra fields: %v", s.Extra)
+	}
+	if !s.Account.Bot || len(s.Account.Extra) != 1 || string(s.Account.Extra["other_settings"]) != `{"x": 1}` {
+		t.Fatalf("unexpected account extra fields: %v", s.Account.Extra)
+	}
+	if len(s.Reblog.Extra) != 1 || string(s.Reblog.Extra["quote"]) != `{"id": "4"}` {
+		t.Fatalf("unexpected reblog extra fields: %v", s.Reblog.Extra)
+	}
+
+	instance, err := client.GetInstance(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if string(instance.Extra["max_toot_chars"]) != "5000" {
+		t.Fatalf("want %q but %q", "5000", instance.Extra["max_toot_chars"])
+	}
+}
@@ -0,0 +1,138 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// FeaturedTag holds information for a hashtag featured on a profile.
+type FeaturedTag struct {
+	ID   ID     `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// StatusesCount is sent as a number or a string depending on the
+	// server version.
+	StatusesCount json.Number `json:"statuses_count"`
+	LastStatusAt  Date        `json:"last_status_at"`
+}
+
+// GetFeaturedTags returns the hashtags featured on the profile of the
+// current user.
+func (c *Client) GetFeaturedTags(ctx context.Context) ([]*FeaturedTag, error) {
+	var tags []*FeaturedTag
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/featured_tags", nil, &tags, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FeatureTag features the hashtag name on the profile of the current user.
+func (c *Client) FeatureTag(ctx context.Context, name string) (*FeaturedTag, error) {
+	params := url.Values{}
+	params.Set("name", name)
+
+	var tag FeaturedTag
+	err := c.doAPI(ctx, http.MethodPost, "/api/v1/featured_tags", params, &tag, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// UnfeatureTag stops featuring the featured tag of id.
+func (c *Client) UnfeatureTag(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/featured_tags/%s", url.PathEscape(string(id))), nil, nil, nil)
+}
+
+// LimitError is returned when an operation would exceed a limit of the
+// instance.
+type LimitError struct {
+	// Limit is the name of the limit, like "pinned statuses".
+	Limit   string
+	Max     int
+	Current int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("mastodon: %s limit reached: %d of %d in use", e.Limit, e.Current, e.Max)
+}
+
+// ProfileFeatures pins statuses and features hashtags after checking the
+// limits of the instance, returning a *LimitError instead of letting the
+// server answer with an unexplained 422.
+//
+// The limits are read once from the v2 instance configuration; a failed
+// read is retried by the next call. Limits the instance doesn't publish are
+// not checked.
+type ProfileFeatures struct {
+	Client *Client
+
+	mu                sync.Mutex
+	loaded            bool
+	maxPinnedStatuses int
+	maxFeaturedTags   int
+}
+
+func (p *ProfileFeatures) limits(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return nil
+	}
+	instance, err := p.Client.GetInstanceV2(ctx)
+	if err != nil {
+		return err
+	}
+	p.maxPinnedStatuses = instance.Configuration.Accounts.MaxPinnedStatuses
+	p.maxFeaturedTags = instance.Configuration.Accounts.MaxFeaturedTags
+	p.loaded = true
+	return nil
+}
+
+// Pin pins the status of id.
+func (p *ProfileFeatures) Pin(ctx context.Context, id ID) (*Status, error) {
+	if err := p.limits(ctx); err != nil {
+		return nil, err
+	}
+	if p.maxPinnedStatuses > 0 {
+		me, err := p.Client.GetAccountCurrentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pinned, err := p.Client.GetAccountPinnedStatuses(ctx, me.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range pinned {
+			if s.ID == id {
+				return s, nil
+			}
+		}
+		if len(pinned) >= p.maxPinnedStatuses {
+			return nil, &LimitError{Limit: "pinned statuses", Max: p.maxPinnedStatuses, Current: len(pinned)}
+		}
+	}
+	return p.Client.Pin(ctx, id)
+}
+
+// FeatureTag features the hashtag name.
+func (p *ProfileFeatures) FeatureTag(ctx context.Context, name string) (*FeaturedTag, error) {
+	if err := p.limits(ctx); err != nil {
+		return nil, err
+	}
+	if p.maxFeaturedTags > 0 {
+		tags, err := p.Client.GetFeaturedTags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) >= p.maxFeaturedTags {
+			return nil, &LimitError{Limit: "featured tags", Max: p.maxFeaturedTags, Current: len(tags)}
+		}
+	}
+	return p.Client.FeatureTag(ctx, name)
+}
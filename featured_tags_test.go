@@ -0,0 +1,116 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFeaturedTags(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/featured_tags":
+			fmt.Fprintln(w, `[{"id": "1", "name": "golang", "statuses_count": "12", "last_status_at": "2022-09-14"}, {"id": "2", "name": "mastodon", "statuses_count": 3}]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/featured_tags":
+			fmt.Fprintf(w, `{"id": "3", "name": "%s"}`, r.PostFormValue("name"))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/featured_tags/1":
+			fmt.Fprintln(w, `{}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	tags, err := client.GetFeaturedTags(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tags) != 2 || tags[0].StatusesCount != "12" || tags[1].StatusesCount != "3" || tags[0].LastStatusAt.String() != "2022-09-14" {
+		t.Fatalf("unexpected tags: %+v %+v", tags[0], tags[1])
+	}
+	tag, err := client.FeatureTag(context.Background(), "gopher")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if tag.Name != "gopher" {
+		t.Fatalf("want %q but %q", "gopher", tag.Name)
+	}
+	if err := client.UnfeatureTag(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := client.UnfeatureTag(context.Background(), "2"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestProfileFeatures(t *testing.T) {
+	pins := 0
+	instanceDown := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/instance":
+			if instanceDown {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, `{"configuration": {"accounts": {"max_featured_tags": 2, "max_pinned_statuses": 2}}}`)
+		case "/api/v1/accounts/verify_credentials":
+			fmt.Fprintln(w, `{"id": "1"}`)
+		case "/api/v1/accounts/1/statuses":
+			if r.FormValue("pinned") != "true" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "10"}, {"id": "11"}]`)
+		case "/api/v1/statuses/12/pin":
+			pins++
+			fmt.Fprintln(w, `{"id": "12", "pinned": true}`)
+		case "/api/v1/featured_tags":
+			if r.Method == http.MethodPost {
+				fmt.Fprintln(w, `{"id": "2", "name": "golang"}`)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "1", "name": "mastodon"}]`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	p := &ProfileFeatures{Client: NewClient(&Config{Server: ts.URL})}
+	_, err := p.Pin(context.Background(), "12")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	// The limits are read again after a failure.
+	instanceDown = false
+	_, err = p.Pin(context.Background(), "12")
+	var le *LimitError
+	if !errors.As(err, &le) {
+		t.Fatalf("want LimitError but %v", err)
+	}
+	if le.Max != 2 || le.Current != 2 || le.Error() != "mastodon: pinned statuses limit reached: 2 of 2 in use" {
+		t.Fatalf("unexpected error: %v", le)
+	}
+	if pins != 0 {
+		t.Fatalf("status should not be pinned: %d", pins)
+	}
+	s, err := p.Pin(context.Background(), "11")
+	if err != nil || s.ID != "11" {
+		t.Fatalf("already pinned status should be returned: %v", err)
+	}
+	tag, err := p.FeatureTag(context.Background(), "golang")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if tag.Name != "golang" {
+		t.Fatalf("want %q but %q", "golang", tag.Name)
+	}
+}
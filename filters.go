@@ -11,21 +11,21 @@ import (
 
 // Filter is metadata for a filter of users.
 type Filter struct {
-	ID           ID        `json:"id"`
-	Phrase       string    `json:"phrase"`
-	Context      []string  `json:"context"`
-	WholeWord    bool      `json:"whole_word"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	Irreversible bool      `json:"irreversible"`
+	ID           ID              `json:"id"`
+	Phrase       string          `json:"phrase"`
+	Context      []FilterContext `json:"context"`
+	WholeWord    bool            `json:"whole_word"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	Irreversible bool            `json:"irreversible"`
 }
 
 type FilterResult struct {
 	Filter struct {
-		ID           string    `json:"id"`
-		Title        string    `json:"title"`
-		Context      []string  `json:"context"`
-		ExpiresAt    time.Time `json:"expires_at"`
-		FilterAction string    `json:"filter_action"`
+		ID           string          `json:"id"`
+		Title        string          `json:"title"`
+		Context      []FilterContext `json:"context"`
+		ExpiresAt    time.Time       `json:"expires_at"`
+		FilterAction FilterAction    `json:"filter_action"`
 	} `json:"filter"`
 	KeywordMatches []string `json:"keyword_matches"`
 	StatusMatches  []string `json:"status_matches"`
@@ -65,7 +65,7 @@ func (c *Client) CreateFilter(ctx context.Context, filter *Filter) (*Filter, err
 	params := url.Values{}
 	params.Set("phrase", filter.Phrase)
 	for _, c := range filter.Context {
-		params.Add("context[]", c)
+		params.Add("context[]", string(c))
 	}
 	if filter.WholeWord {
 		params.Add("whole_word", "true")
@@ -103,7 +103,7 @@ func (c *Client) UpdateFilter(ctx context.Context, id ID, filter *Filter) (*Filt
 	params := url.Values{}
 	params.Set("phrase", filter.Phrase)
 	for _, c := range filter.Context {
-		params.Add("context[]", c)
+		params.Add("context[]", string(c))
 	}
 	if filter.WholeWord {
 		params.Add("whole_word", "true")
@@ -31,7 +31,7 @@ func TestGetFilters(t *testing.T) {
 		{
 			ID:           ID("6191"),
 			Phrase:       "rust",
-			Context:      []string{"home"},
+			Context:      []FilterContext{"home"},
 			WholeWord:    true,
 			ExpiresAt:    d,
 			Irreversible: false,
@@ -39,7 +39,7 @@ func TestGetFilters(t *testing.T) {
 		{
 			ID:           ID("5580"),
 			Phrase:       "@twitter.com",
-			Context:      []string{"notifications", "home", "thread", "public"},
+			Context:      []FilterContext{"notifications", "home", "thread", "public"},
 			WholeWord:    false,
 			ExpiresAt:    time.Time{},
 			Irreversible: true,
@@ -60,9 +60,7 @@ func TestGetFilters(t *testing.T) {
 		if filters[i].Phrase != f.Phrase {
 			t.Fatalf("want %q but %q", f.Phrase, filters[i].Phrase)
 		}
-		sort.Strings(filters[i].Context)
-		sort.Strings(f.Context)
-		if strings.Join(filters[i].Context, ", ") != strings.Join(f.Context, ", ") {
+		if joinFilterContexts(filters[i].Context) != joinFilterContexts(f.Context) {
 			t.Fatalf("want %q but %q", f.Context, filters[i].Context)
 		}
 		if filters[i].ExpiresAt != f.ExpiresAt {
@@ -104,7 +102,7 @@ func TestGetFilter(t *testing.T) {
 	tf := Filter{
 		ID:           ID("1"),
 		Phrase:       "rust",
-		Context:      []string{"home"},
+		Context:      []FilterContext{"home"},
 		WholeWord:    true,
 		ExpiresAt:    d,
 		Irreversible: false,
@@ -119,9 +117,7 @@ func TestGetFilter(t *testing.T) {
 	if filter.Phrase != tf.Phrase {
 		t.Fatalf("want %q but %q", tf.Phrase, filter.Phrase)
 	}
-	sort.Strings(filter.Context)
-	sort.Strings(tf.Context)
-	if strings.Join(filter.Context, ", ") != strings.Join(tf.Context, ", ") {
+	if joinFilterContexts(filter.Context) != joinFilterContexts(tf.Context) {
 		t.Fatalf("want %q but %q", tf.Context, filter.Context)
 	}
 	if filter.ExpiresAt != tf.ExpiresAt {
@@ -161,7 +157,7 @@ func TestCreateFilter(t *testing.T) {
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
-	_, err = client.CreateFilter(context.Background(), &Filter{Context: []string{"home"}})
+	_, err = client.CreateFilter(context.Background(), &Filter{Context: []FilterContext{"home"}})
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
@@ -169,7 +165,7 @@ func TestCreateFilter(t *testing.T) {
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
-	_, err = client.CreateFilter(context.Background(), &Filter{Phrase: "Test", Context: []string{"home"}})
+	_, err = client.CreateFilter(context.Background(), &Filter{Phrase: "Test", Context: []FilterContext{"home"}})
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
@@ -182,7 +178,7 @@ func TestCreateFilter(t *testing.T) {
 		{
 			ID:           ID("1"),
 			Phrase:       "rust",
-			Context:      []string{"home"},
+			Context:      []FilterContext{"home"},
 			WholeWord:    true,
 			ExpiresAt:    d,
 			Irreversible: true,
@@ -190,7 +186,7 @@ func TestCreateFilter(t *testing.T) {
 		{
 			ID:           ID("2"),
 			Phrase:       "@twitter.com",
-			Context:      []string{"notifications", "home", "thread", "public"},
+			Context:      []FilterContext{"notifications", "home", "thread", "public"},
 			WholeWord:    false,
 			ExpiresAt:    time.Time{},
 			Irreversible: false,
@@ -207,9 +203,7 @@ func TestCreateFilter(t *testing.T) {
 		if filter.Phrase != f.Phrase {
 			t.Fatalf("want %q but %q", f.Phrase, filter.Phrase)
 		}
-		sort.Strings(filter.Context)
-		sort.Strings(f.Context)
-		if strings.Join(filter.Context, ", ") != strings.Join(f.Context, ", ") {
+		if joinFilterContexts(filter.Context) != joinFilterContexts(f.Context) {
 			t.Fatalf("want %q but %q", f.Context, filter.Context)
 		}
 		if filter.ExpiresAt != f.ExpiresAt {
@@ -261,7 +255,7 @@ func TestUpdateFilter(t *testing.T) {
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
-	_, err = client.UpdateFilter(context.Background(), ID("3"), &Filter{Phrase: "rust", Context: []string{"home"}})
+	_, err = client.UpdateFilter(context.Background(), ID("3"), &Filter{Phrase: "rust", Context: []FilterContext{"home"}})
 	if err == nil {
 		t.Fatalf("should be fail: %v", err)
 	}
@@ -274,7 +268,7 @@ func TestUpdateFilter(t *testing.T) {
 		{
 			ID:           ID("1"),
 			Phrase:       "rust",
-			Context:      []string{"home"},
+			Context:      []FilterContext{"home"},
 			WholeWord:    true,
 			ExpiresAt:    d,
 			Irreversible: true,
@@ -282,7 +276,7 @@ func TestUpdateFilter(t *testing.T) {
 		{
 			ID:           ID("2"),
 			Phrase:       "@twitter.com",
-			Context:      []string{"notifications", "home", "thread", "public"},
+			Context:      []FilterContext{"notifications", "home", "thread", "public"},
 			WholeWord:    false,
 			ExpiresAt:    time.Time{},
 			Irreversible: false,
@@ -299,9 +293,7 @@ func TestUpdateFilter(t *testing.T) {
 		if filter.Phrase != f.Phrase {
 			t.Fatalf("want %q but %q", f.Phrase, filter.Phrase)
 		}
-		sort.Strings(filter.Context)
-		sort.Strings(f.Context)
-		if strings.Join(filter.Context, ", ") != strings.Join(f.Context, ", ") {
+		if joinFilterContexts(filter.Context) != joinFilterContexts(f.Context) {
 			t.Fatalf("want %q but %q", f.Context, filter.Context)
 		}
 		if filter.ExpiresAt != f.ExpiresAt {
@@ -340,3 +332,12 @@ func TestDeleteFilter(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func joinFilterContexts(contexts []FilterContext) string {
+	s := make([]string, len(contexts))
+	for i, c := range contexts {
+		s[i] = string(c)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
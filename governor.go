@@ -0,0 +1,188 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Governor throttles the replies and direct messages a bot sends, so that
+// it is not flagged as spam. Messages queued for the same recipient within
+// Window are sent as a single status, and at most MaxPerRecipient statuses
+// are sent to a recipient per Period.
+type Governor struct {
+	Client *Client
+	// Window defaults to one minute.
+	Window time.Duration
+	// MaxPerRecipient defaults to 5.
+	MaxPerRecipient int
+	// Period defaults to one hour.
+	Period time.Duration
+	// Separator joins batched messages; defaults to a blank line.
+	Separator string
+
+	mu      sync.Mutex
+	pending map[string]*governorBatch
+	sent    map[string][]time.Time
+}
+
+type governorBatch struct {
+	acct  string
+	first time.Time
+	toot  Toot
+	texts []string
+}
+
+// RecipientLimitError is returned by Governor.Queue when a recipient got
+// MaxPerRecipient statuses in the last Period.
+type RecipientLimitError struct {
+	Acct string
+	// RetryAt is when a new status may be sent to Acct.
+	RetryAt time.Time
+}
+
+func (e *RecipientLimitError) Error() string {
+	return fmt.Sprintf("mastodon: too many messages to %s, retry at %s", e.Acct, e.RetryAt.Format(time.RFC3339))
+}
+
+func (g *Governor) window() time.Duration {
+	if g.Window <= 0 {
+		return time.Minute
+	}
+	return g.Window
+}
+
+func (g *Governor) maxPerRecipient() int {
+	if g.MaxPerRecipient <= 0 {
+		return 5
+	}
+	return g.MaxPerRecipient
+}
+
+func (g *Governor) period() time.Duration {
+	if g.Period <= 0 {
+		return time.Hour
+	}
+	return g.Period
+}
+
+// Queue queues a message to the account acct. The mention of acct is added
+// when sending, so toot.Status should not contain it. The first queued toot
+// of a batch gives its visibility, spoiler text and language; replies are
+// made to the last InReplyToID.
+func (g *Governor) Queue(acct string, toot *Toot) error {
+	return g.queue(acct, toot, time.Now())
+}
+
+func (g *Governor) queue(acct string, toot *Toot, now time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pending == nil {
+		g.pending = map[string]*governorBatch{}
+		g.sent = map[string][]time.Time{}
+	}
+
+	if b, ok := g.pending[acct]; ok {
+		b.texts = append(b.texts, toot.Status)
+		if toot.InReplyToID != "" {
+			b.toot.InReplyToID = toot.InReplyToID
+		}
+		return nil
+	}
+
+	sent := g.sent[acct]
+	since := now.Add(-g.period())
+	for len(sent) > 0 && !sent[0].After(since) {
+		sent = sent[1:]
+	}
+	g.sent[acct] = sent
+	if len(sent) >= g.maxPerRecipient() {
+		return &RecipientLimitError{Acct: acct, RetryAt: sent[0].Add(g.period())}
+	}
+	g.pending[acct] = &governorBatch{acct: acct, first: now, toot: *toot, texts: []string{toot.Status}}
+	return nil
+}
+
+// Flush sends the batches whose window has elapsed and returns the posted
+// statuses. Batches that could not be sent stay queued.
+func (g *Governor) Flush(ctx context.Context) ([]*Status, error) {
+	return g.flush(ctx, time.Now(), false)
+}
+
+// FlushAll sends all queued batches, for example before exiting.
+func (g *Governor) FlushAll(ctx context.Context) ([]*Status, error) {
+	return g.flush(ctx, time.Now(), true)
+}
+
+func (g *Governor) flush(ctx context.Context, now time.Time, all bool) ([]*Status, error) {
+	g.mu.Lock()
+	var due []*governorBatch
+	for acct, b := range g.pending {
+		if all || !now.Before(b.first.Add(g.window())) {
+			due = append(due, b)
+			delete(g.pending, acct)
+		}
+	}
+	g.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].first.Before(due[j].first) })
+
+	sep := g.Separator
+	if sep == "" {
+		sep = "\n\n"
+	}
+	var statuses []*Status
+	for i, b := range due {
+		toot := b.toot
+		toot.Status = "@" + b.acct + " " + strings.Join(b.texts, sep)
+		status, err := g.Client.PostStatus(ctx, &toot)
+		if err != nil {
+			g.requeue(due[i:])
+			return statuses, err
+		}
+		statuses = append(statuses, status)
+		g.mu.Lock()
+		g.sent[b.acct] = append(g.sent[b.acct], now)
+		g.mu.Unlock()
+	}
+	return statuses, nil
+}
+
+// requeue puts back batches that could not be sent, before the messages
+// queued in the meantime.
+func (g *Governor) requeue(batches []*governorBatch) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, b := range batches {
+		if p, ok := g.pending[b.acct]; ok {
+			p.first = b.first
+			p.texts = append(b.texts, p.texts...)
+			continue
+		}
+		g.pending[b.acct] = b
+	}
+}
+
+// Run flushes the batches as their window elapses until ctx is done or
+// sending fails. Messages still queued when it returns can be sent with
+// FlushAll.
+func (g *Governor) Run(ctx context.Context) error {
+	tick := g.window() / 4
+	if tick <= 0 {
+		tick = g.window()
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := g.Flush(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
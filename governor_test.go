@@ -0,0 +1,86 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGovernor(t *testing.T) {
+	var posted []string
+	var replies []string
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses" || fail {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		posted = append(posted, r.FormValue("status"))
+		replies = append(replies, r.FormValue("in_reply_to_id"))
+		fmt.Fprintf(w, `{"id": "%d", "visibility": %q}`, len(posted), r.FormValue("visibility"))
+	}))
+	defer ts.Close()
+
+	g := &Governor{
+		Client:          NewClient(&Config{Server: ts.URL, AccessToken: "zoo"}),
+		Window:          time.Minute,
+		MaxPerRecipient: 1,
+		Period:          time.Hour,
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	if err := g.queue("foo@example.com", &Toot{Status: "hello", Visibility: "direct", InReplyToID: "1"}, now); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := g.queue("foo@example.com", &Toot{Status: "again", InReplyToID: "2"}, now.Add(30*time.Second)); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := g.queue("bar", &Toot{Status: "hi"}, now.Add(45*time.Second)); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	statuses, err := g.flush(ctx, now.Add(59*time.Second), false)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(statuses) != 0 {
+		t.Fatalf("result should be empty: %d", len(statuses))
+	}
+	statuses, err = g.flush(ctx, now.Add(time.Minute), false)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Visibility != "direct" {
+		t.Fatalf("want one direct status but %v", statuses)
+	}
+	if want := "@foo@example.com hello\n\nagain"; posted[0] != want {
+		t.Fatalf("want %q but %q", want, posted[0])
+	}
+	if replies[0] != "2" {
+		t.Fatalf("want %q but %q", "2", replies[0])
+	}
+
+	err = g.queue("foo@example.com", &Toot{Status: "more"}, now.Add(2*time.Minute))
+	if e, ok := err.(*RecipientLimitError); !ok || !e.RetryAt.Equal(now.Add(time.Minute+time.Hour)) {
+		t.Fatalf("want RecipientLimitError but %v", err)
+	}
+	if err := g.queue("foo@example.com", &Toot{Status: "later"}, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	fail = true
+	if _, err := g.flush(ctx, now.Add(2*time.Hour), true); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	fail = false
+	statuses, err = g.FlushAll(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(statuses) != 2 || posted[1] != "@bar hi" || posted[2] != "@foo@example.com later" {
+		t.Fatalf("unexpected statuses: %q", posted)
+	}
+}
@@ -0,0 +1,128 @@
+package mastodon
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TagCount holds the number of statuses a hashtag appeared in.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// AuthorCount holds the number of statuses an account posted.
+type AuthorCount struct {
+	Account *Account
+	Count   int
+}
+
+// HashtagAnalysis holds the result of HashtagAnalyzer.Analyze.
+type HashtagAnalysis struct {
+	Tag string
+	// Statuses is the number of statuses sampled.
+	Statuses int
+	// CoTags holds the other hashtags used together with Tag, most used first.
+	CoTags []TagCount
+	// TopAuthors holds the accounts that posted most of the sampled
+	// statuses, most active first.
+	TopAuthors []AuthorCount
+}
+
+// HashtagAnalyzer samples a hashtag timeline to find related hashtags and
+// the most active authors.
+type HashtagAnalyzer struct {
+	Client *Client
+	// Local restricts the sample to statuses of the instance.
+	Local bool
+	// MaxStatuses is the number of statuses sampled; defaults to 200.
+	MaxStatuses int
+	// Interval paces the timeline requests of the sample. Zero means one
+	// second and negative values disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoints of the samples, named after the hashtag,
+	// so an interrupted sample resumes where it left off; nil disables
+	// resuming.
+	Jobs CheckpointStore
+}
+
+// Analyze samples the timeline of tag.
+func (a *HashtagAnalyzer) Analyze(ctx context.Context, tag string) (*HashtagAnalysis, error) {
+	tag = strings.TrimPrefix(tag, "#")
+	max := a.MaxStatuses
+	if max <= 0 {
+		max = 200
+	}
+	interval := pageInterval(a.Interval)
+
+	r := &HashtagAnalysis{Tag: tag}
+	tags := map[string]int{}
+	authors := map[ID]*AuthorCount{}
+	job := "hashtag-analysis-" + strings.ToLower(tag)
+	if a.Local {
+		job += "-local"
+	}
+	state := &struct {
+		Statuses *int                 `json:"statuses"`
+		Tags     *map[string]int      `json:"tags"`
+		Authors  *map[ID]*AuthorCount `json:"authors"`
+	}{&r.Statuses, &tags, &authors}
+	err := walkJob(ctx, a.Jobs, job, state, interval, &Pagination{Limit: 40}, func(pg *Pagination) (bool, error) {
+		statuses, err := a.Client.GetTimelineHashtag(ctx, tag, a.Local, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range statuses {
+			if r.Statuses >= max {
+				return false, nil
+			}
+			r.Statuses++
+
+			seen := map[string]bool{}
+			for _, t := range s.Tags {
+				name := strings.ToLower(t.Name)
+				if name == strings.ToLower(tag) || seen[name] {
+					continue
+				}
+				seen[name] = true
+				tags[name]++
+			}
+
+			acct := s.Account
+			if ac, ok := authors[acct.ID]; ok {
+				ac.Count++
+			} else {
+				authors[acct.ID] = &AuthorCount{Account: &acct, Count: 1}
+			}
+		}
+		return len(statuses) > 0 && r.Statuses < max, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := deleteJobs(ctx, a.Jobs, job); err != nil {
+		return nil, err
+	}
+
+	for name, n := range tags {
+		r.CoTags = append(r.CoTags, TagCount{Name: name, Count: n})
+	}
+	sort.Slice(r.CoTags, func(i, j int) bool {
+		if r.CoTags[i].Count != r.CoTags[j].Count {
+			return r.CoTags[i].Count > r.CoTags[j].Count
+		}
+		return r.CoTags[i].Name < r.CoTags[j].Name
+	})
+	for _, ac := range authors {
+		r.TopAuthors = append(r.TopAuthors, *ac)
+	}
+	sort.Slice(r.TopAuthors, func(i, j int) bool {
+		if r.TopAuthors[i].Count != r.TopAuthors[j].Count {
+			return r.TopAuthors[i].Count > r.TopAuthors[j].Count
+		}
+		return r.TopAuthors[i].Account.Acct < r.TopAuthors[j].Account.Acct
+	})
+	return r, nil
+}
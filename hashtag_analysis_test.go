@@ -0,0 +1,80 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHashtagAnalyzer(t *testing.T) {
+	var requests int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/tag/golang" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		requests++
+		switch r.URL.Query().Get("max_id") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/timelines/tag/golang?max_id=3>; rel="next", <%s/api/v1/timelines/tag/golang?min_id=5>; rel="prev"`, ts.URL, ts.URL))
+			fmt.Fprintln(w, `[
+				{"id": "5", "account": {"id": "1", "acct": "alice"}, "tags": [{"name": "golang"}, {"name": "Rust"}, {"name": "rust"}]},
+				{"id": "4", "account": {"id": "2", "acct": "bob"}, "tags": [{"name": "golang"}, {"name": "fediverse"}]},
+				{"id": "3", "account": {"id": "1", "acct": "alice"}, "tags": [{"name": "GoLang"}, {"name": "rust"}]}
+			]`)
+		case "3":
+			if r.URL.Query().Get("min_id") != "" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `[
+				{"id": "2", "account": {"id": "3", "acct": "carol"}, "tags": [{"name": "golang"}, {"name": "fediverse"}]},
+				{"id": "1", "account": {"id": "1", "acct": "alice"}, "tags": [{"name": "golang"}, {"name": "wasm"}]}
+			]`)
+		default:
+			fmt.Fprintln(w, `[]`)
+		}
+	}))
+	defer ts.Close()
+
+	a := &HashtagAnalyzer{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Interval: time.Millisecond,
+	}
+	r, err := a.Analyze(context.Background(), "#golang")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("want %d requests but %d", 2, requests)
+	}
+	if r.Statuses != 5 {
+		t.Fatalf("want %d but %d", 5, r.Statuses)
+	}
+	want := []TagCount{{"fediverse", 2}, {"rust", 2}, {"wasm", 1}}
+	if len(r.CoTags) != len(want) {
+		t.Fatalf("want %v but %v", want, r.CoTags)
+	}
+	for i := range want {
+		if r.CoTags[i] != want[i] {
+			t.Fatalf("want %v but %v", want, r.CoTags)
+		}
+	}
+	if r.TopAuthors[0].Account.Acct != "alice" || r.TopAuthors[0].Count != 3 {
+		t.Fatalf("unexpected top author: %v %d", r.TopAuthors[0].Account.Acct, r.TopAuthors[0].Count)
+	}
+
+	requests = 0
+	a.MaxStatuses = 2
+	r, err = a.Analyze(context.Background(), "golang")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if requests != 1 || r.Statuses != 2 {
+		t.Fatalf("want 1 request and 2 statuses but %d and %d", requests, r.Statuses)
+	}
+}
@@ -3,7 +3,6 @@ package mastodon
 import (
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -40,16 +39,47 @@ func Base64Encode(file *os.File) (string, error) {
 // String is a helper function to get the pointer value of a string.
 func String(v string) *string { return &v }
 
+// APIError is returned when the server answers a request with an error
+// status. Use errors.As to inspect it.
+type APIError struct {
+	prefix string
+	// Status is the status line of the response, like "404 Not Found".
+	Status     string
+	StatusCode int
+	// Message and Description are the error and error_description fields
+	// of the response body, if any.
+	Message     string
+	Description string
+	Header      http.Header
+	// RateLimit is the rate limit state reported with the error, if any.
+	RateLimit *RateLimit
+}
+
+func (e *APIError) Error() string {
+	errMsg := fmt.Sprintf("%s: %s", e.prefix, e.Status)
+	if e.Message != "" {
+		errMsg = fmt.Sprintf("%s: %s", errMsg, e.Message)
+	}
+	if e.Description != "" {
+		errMsg = fmt.Sprintf("%s: %s", errMsg, e.Description)
+	}
+	return errMsg
+}
+
 func parseAPIError(prefix string, resp *http.Response) error {
-	errMsg := fmt.Sprintf("%s: %s", prefix, resp.Status)
 	var e struct {
-		Error string `json:"error"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
 	}
-
 	json.NewDecoder(resp.Body).Decode(&e)
-	if e.Error != "" {
-		errMsg = fmt.Sprintf("%s: %s", errMsg, e.Error)
-	}
 
-	return errors.New(errMsg)
+	return &APIError{
+		prefix:      prefix,
+		Status:      resp.Status,
+		StatusCode:  resp.StatusCode,
+		Message:     e.Error,
+		Description: e.ErrorDescription,
+		Header:      resp.Header,
+		RateLimit:   parseRateLimit(resp.Header),
+	}
 }
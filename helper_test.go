@@ -1,8 +1,12 @@
 package mastodon
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -86,4 +90,45 @@ func TestParseAPIError(t *testing.T) {
 	if err.Error() != want {
 		t.Fatalf("want %q but %q", want, err.Error())
 	}
+
+	// With OAuth error.
+	r = ioutil.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"The provided authorization grant is invalid"}`))
+	header := http.Header{"X-Ratelimit-Limit": {"300"}, "X-Ratelimit-Remaining": {"0"}}
+	err = parseAPIError("bad authorization", &http.Response{Status: "400 Bad Request", StatusCode: http.StatusBadRequest, Header: header, Body: r})
+	want = "bad authorization: 400 Bad Request: invalid_grant: The provided authorization grant is invalid"
+	if err.Error() != want {
+		t.Fatalf("want %q but %q", want, err.Error())
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want *APIError but %T", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "invalid_grant" {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+	if apiErr.RateLimit == nil || apiErr.RateLimit.Remaining != 0 {
+		t.Fatalf("unexpected rate limit: %v", apiErr.RateLimit)
+	}
+}
+
+func TestAPIErrorFromClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprintln(w, `{"error": "Validation failed: Text can't be blank"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	_, err := client.PostStatus(context.Background(), &Toot{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want *APIError but %T", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("want %d but %d", http.StatusUnprocessableEntity, apiErr.StatusCode)
+	}
+	if apiErr.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("want %q but %q", "application/json", apiErr.Header.Get("Content-Type"))
+	}
 }
@@ -0,0 +1,38 @@
+package mastodon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// NewIdempotencyKey returns a random key for PostStatusOptions. Reuse the
+// key when retrying to post the same status.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+type headerContextKey struct{}
+
+// withHeader returns a copy of ctx with which doAPI sets the header key to
+// value.
+func withHeader(ctx context.Context, key, value string) context.Context {
+	h := http.Header{}
+	if parent, ok := ctx.Value(headerContextKey{}).(http.Header); ok {
+		h = parent.Clone()
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, headerContextKey{}, h)
+}
+
+func setContextHeaders(ctx context.Context, h http.Header) {
+	ch, _ := ctx.Value(headerContextKey{}).(http.Header)
+	for k, v := range ch {
+		h[k] = v
+	}
+}
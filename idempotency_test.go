@@ -0,0 +1,57 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPostStatusIdempotencyKey(t *testing.T) {
+	var keys []string
+	canErr := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if canErr {
+			canErr = false
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `{"content": "foo"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, ServerErrors: true}
+	_, err := client.PostStatus(context.Background(), &Toot{Status: "foo"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("result should be two: %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("retry should send the same key: %q %q", keys[0], keys[1])
+	}
+
+	keys = nil
+	_, err = client.PostStatusWithOptions(context.Background(), &Toot{Status: "foo"}, &PostStatusOptions{IdempotencyKey: "bar"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "bar" {
+		t.Fatalf("want %q but %q", "bar", keys)
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	a, b := NewIdempotencyKey(), NewIdempotencyKey()
+	if len(a) != 32 {
+		t.Fatalf("want %d but %d", 32, len(a))
+	}
+	if a == b {
+		t.Fatalf("keys should be different: %q", a)
+	}
+}
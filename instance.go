@@ -2,7 +2,9 @@ package mastodon
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 )
 
 // Instance holds information for a mastodon instance.
@@ -18,16 +20,90 @@ type Instance struct {
 	Languages      []string          `json:"languages"`
 	ContactAccount *Account          `json:"contact_account"`
 	Configuration  *InstanceConfig   `json:"configuration"`
+	// Extra holds the members of the JSON object without a field, like
+	// the additions of other servers, when Client.ExtraFields is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// InstanceConfigMap holds the raw values of a section of the configuration
+// of an instance, including the keys the typed configs don't have.
 type InstanceConfigMap map[string]interface{}
 
-// InstanceConfig holds configuration accessible for clients.
+// InstanceConfig holds configuration accessible for clients. Sections the
+// instance didn't publish are nil.
 type InstanceConfig struct {
-	Accounts         *InstanceConfigMap     `json:"accounts"`
-	Statuses         *InstanceConfigMap     `json:"statuses"`
-	MediaAttachments map[string]interface{} `json:"media_attachments"`
-	Polls            *InstanceConfigMap     `json:"polls"`
+	Accounts         *AccountsConfig         `json:"accounts"`
+	Statuses         *StatusesConfig         `json:"statuses"`
+	MediaAttachments *MediaAttachmentsConfig `json:"media_attachments"`
+	Polls            *PollsConfig            `json:"polls"`
+}
+
+// AccountsConfig holds the limits of accounts.
+type AccountsConfig struct {
+	MaxFeaturedTags   int `json:"max_featured_tags"`
+	MaxPinnedStatuses int `json:"max_pinned_statuses"`
+	// Raw holds all the values of the section.
+	Raw InstanceConfigMap `json:"-"`
+}
+
+// StatusesConfig holds the limits of statuses.
+type StatusesConfig struct {
+	MaxCharacters            int `json:"max_characters"`
+	MaxMediaAttachments      int `json:"max_media_attachments"`
+	CharactersReservedPerURL int `json:"characters_reserved_per_url"`
+	// Raw holds all the values of the section.
+	Raw InstanceConfigMap `json:"-"`
+}
+
+// MediaAttachmentsConfig holds the accepted types and limits of media.
+type MediaAttachmentsConfig struct {
+	SupportedMimeTypes  []string `json:"supported_mime_types"`
+	ImageSizeLimit      int      `json:"image_size_limit"`
+	ImageMatrixLimit    int      `json:"image_matrix_limit"`
+	VideoSizeLimit      int      `json:"video_size_limit"`
+	VideoFrameRateLimit int      `json:"video_frame_rate_limit"`
+	VideoMatrixLimit    int      `json:"video_matrix_limit"`
+	// Raw holds all the values of the section.
+	Raw InstanceConfigMap `json:"-"`
+}
+
+// PollsConfig holds the limits of polls. Expirations are in seconds.
+type PollsConfig struct {
+	MaxOptions             int `json:"max_options"`
+	MaxCharactersPerOption int `json:"max_characters_per_option"`
+	MinExpiration          int `json:"min_expiration"`
+	MaxExpiration          int `json:"max_expiration"`
+	// Raw holds all the values of the section.
+	Raw InstanceConfigMap `json:"-"`
+}
+
+func (c *AccountsConfig) UnmarshalJSON(data []byte) error {
+	type config AccountsConfig
+	return unmarshalConfig(data, (*config)(c), &c.Raw)
+}
+
+func (c *StatusesConfig) UnmarshalJSON(data []byte) error {
+	type config StatusesConfig
+	return unmarshalConfig(data, (*config)(c), &c.Raw)
+}
+
+func (c *MediaAttachmentsConfig) UnmarshalJSON(data []byte) error {
+	type config MediaAttachmentsConfig
+	return unmarshalConfig(data, (*config)(c), &c.Raw)
+}
+
+func (c *PollsConfig) UnmarshalJSON(data []byte) error {
+	type config PollsConfig
+	return unmarshalConfig(data, (*config)(c), &c.Raw)
+}
+
+// unmarshalConfig decodes a section of the configuration into the typed
+// config v and into raw.
+func unmarshalConfig(data []byte, v interface{}, raw *InstanceConfigMap) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, raw)
 }
 
 // InstanceStats holds information for mastodon instance stats.
@@ -62,29 +138,11 @@ type InstanceV2 struct {
 		Urls struct {
 			Streaming string `json:"streaming"`
 		} `json:"urls"`
-		Accounts struct {
-			MaxFeaturedTags int `json:"max_featured_tags"`
-		} `json:"accounts"`
-		Statuses struct {
-			MaxCharacters            int `json:"max_characters"`
-			MaxMediaAttachments      int `json:"max_media_attachments"`
-			CharactersReservedPerURL int `json:"characters_reserved_per_url"`
-		} `json:"statuses"`
-		MediaAttachments struct {
-			SupportedMimeTypes  []string `json:"supported_mime_types"`
-			ImageSizeLimit      int      `json:"image_size_limit"`
-			ImageMatrixLimit    int      `json:"image_matrix_limit"`
-			VideoSizeLimit      int      `json:"video_size_limit"`
-			VideoFrameRateLimit int      `json:"video_frame_rate_limit"`
-			VideoMatrixLimit    int      `json:"video_matrix_limit"`
-		} `json:"media_attachments"`
-		Polls struct {
-			MaxOptions             int `json:"max_options"`
-			MaxCharactersPerOption int `json:"max_characters_per_option"`
-			MinExpiration          int `json:"min_expiration"`
-			MaxExpiration          int `json:"max_expiration"`
-		} `json:"polls"`
-		Translation struct {
+		Accounts         AccountsConfig         `json:"accounts"`
+		Statuses         StatusesConfig         `json:"statuses"`
+		MediaAttachments MediaAttachmentsConfig `json:"media_attachments"`
+		Polls            PollsConfig            `json:"polls"`
+		Translation      struct {
 			Enabled bool `json:"enabled"`
 		} `json:"translation"`
 	} `json:"configuration"`
@@ -98,6 +156,9 @@ type InstanceV2 struct {
 		Account *Account
 	} `json:"contact"`
 	Rules []Rule `json:"rules"`
+	// Extra holds the members of the JSON object without a field, like
+	// the additions of other servers, when Client.ExtraFields is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Rule struct {
@@ -156,3 +217,61 @@ func (c *Client) GetInstancePeers(ctx context.Context) ([]string, error) {
 	}
 	return peers, nil
 }
+
+// GetCustomEmojis returns the custom emojis of the instance.
+func (c *Client) GetCustomEmojis(ctx context.Context) ([]*Emoji, error) {
+	var emojis []*Emoji
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/custom_emojis", nil, &emojis, nil)
+	if err != nil {
+		return nil, err
+	}
+	return emojis, nil
+}
+
+// GetInstanceTranslationLanguages returns the languages the instance can
+// translate between, as a map of source language to target languages.
+func (c *Client) GetInstanceTranslationLanguages(ctx context.Context) (map[string][]string, error) {
+	var languages map[string][]string
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/instance/translation_languages", nil, &languages, nil)
+	if err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
+// PrivacyPolicy holds the privacy policy of a mastodon instance.
+type PrivacyPolicy struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Content   string    `json:"content"`
+}
+
+// GetInstancePrivacyPolicy returns the privacy policy of the instance.
+func (c *Client) GetInstancePrivacyPolicy(ctx context.Context) (*PrivacyPolicy, error) {
+	var policy PrivacyPolicy
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/instance/privacy_policy", nil, &policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// TermsOfService holds the terms of service of a mastodon instance.
+//
+// EffectiveDate and SucceededBy are dates in YYYY-MM-DD format. SucceededBy
+// is empty unless newer terms have already been published.
+type TermsOfService struct {
+	EffectiveDate string `json:"effective_date"`
+	Effective     bool   `json:"effective"`
+	Content       string `json:"content"`
+	SucceededBy   string `json:"succeeded_by"`
+}
+
+// GetInstanceTermsOfService returns the terms of service of the instance.
+func (c *Client) GetInstanceTermsOfService(ctx context.Context) (*TermsOfService, error) {
+	var terms TermsOfService
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/instance/terms_of_service", nil, &terms, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &terms, nil
+}
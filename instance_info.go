@@ -0,0 +1,133 @@
+package mastodon
+
+import (
+	"context"
+	"strings"
+)
+
+// InstanceInfo holds the information of an instance with the same field
+// names whichever version of the instance API it came from. Use
+// GetInstanceInfo, Instance.Info or InstanceV2.Info to get one.
+type InstanceInfo struct {
+	// Domain is the domain of the instance, such as "mastodon.social".
+	Domain      string
+	Title       string
+	Description string
+	Version     string
+	// SourceURL is the URL of the source code of the server software; it
+	// is only published by the v2 API.
+	SourceURL string
+	Languages []string
+	// StreamingURL is the base URL of the streaming API; empty when the
+	// instance didn't publish one.
+	StreamingURL   string
+	ContactEmail   string
+	ContactAccount *Account
+	// Rules are only published by the v2 API.
+	Rules  []Rule
+	Limits InstanceLimits
+	// TranslationEnabled tells whether statuses can be translated; it is
+	// only published by the v2 API.
+	TranslationEnabled bool
+}
+
+// GetInstanceInfo returns the information of the instance from the v2
+// instance API, or from the v1 API when the instance doesn't implement it.
+func (c *Client) GetInstanceInfo(ctx context.Context) (*InstanceInfo, error) {
+	if instance, err := c.GetInstanceV2(ctx); err == nil {
+		return instance.Info(), nil
+	}
+	instance, err := c.GetInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return instance.Info(), nil
+}
+
+// InstanceLimits holds the limits of an instance. Zero means the limit is
+// unknown.
+type InstanceLimits struct {
+	MaxCharacters            int
+	MaxMediaAttachments      int
+	CharactersReservedPerURL int
+	MaxFeaturedTags          int
+	MaxPinnedStatuses        int
+	MaxPollOptions           int
+	MaxPollOptionCharacters  int
+	// MinPollExpiration and MaxPollExpiration are in seconds.
+	MinPollExpiration  int
+	MaxPollExpiration  int
+	SupportedMimeTypes []string
+	ImageSizeLimit     int
+	VideoSizeLimit     int
+}
+
+// Info returns the information of the instance.
+func (c *Instance) Info() *InstanceInfo {
+	info := &InstanceInfo{
+		Domain:         strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(c.URI, "https://"), "http://"), "/"),
+		Title:          c.Title,
+		Description:    c.Description,
+		Version:        c.Version,
+		Languages:      c.Languages,
+		StreamingURL:   c.URLs["streaming_api"],
+		ContactEmail:   c.EMail,
+		ContactAccount: c.ContactAccount,
+	}
+	if cfg := c.Configuration; cfg != nil {
+		l := &info.Limits
+		if a := cfg.Accounts; a != nil {
+			l.MaxFeaturedTags = a.MaxFeaturedTags
+			l.MaxPinnedStatuses = a.MaxPinnedStatuses
+		}
+		if s := cfg.Statuses; s != nil {
+			l.MaxCharacters = s.MaxCharacters
+			l.MaxMediaAttachments = s.MaxMediaAttachments
+			l.CharactersReservedPerURL = s.CharactersReservedPerURL
+		}
+		if p := cfg.Polls; p != nil {
+			l.MaxPollOptions = p.MaxOptions
+			l.MaxPollOptionCharacters = p.MaxCharactersPerOption
+			l.MinPollExpiration = p.MinExpiration
+			l.MaxPollExpiration = p.MaxExpiration
+		}
+		if m := cfg.MediaAttachments; m != nil {
+			l.ImageSizeLimit = m.ImageSizeLimit
+			l.VideoSizeLimit = m.VideoSizeLimit
+			l.SupportedMimeTypes = m.SupportedMimeTypes
+		}
+	}
+	return info
+}
+
+// Info returns the information of the instance.
+func (c *InstanceV2) Info() *InstanceInfo {
+	cfg := &c.Configuration
+	return &InstanceInfo{
+		Domain:             c.Domain,
+		Title:              c.Title,
+		Description:        c.Description,
+		Version:            c.Version,
+		SourceURL:          c.SourceURL,
+		Languages:          c.Languages,
+		StreamingURL:       cfg.Urls.Streaming,
+		ContactEmail:       c.Contact.Email,
+		ContactAccount:     c.Contact.Account,
+		Rules:              c.Rules,
+		TranslationEnabled: cfg.Translation.Enabled,
+		Limits: InstanceLimits{
+			MaxCharacters:            cfg.Statuses.MaxCharacters,
+			MaxMediaAttachments:      cfg.Statuses.MaxMediaAttachments,
+			CharactersReservedPerURL: cfg.Statuses.CharactersReservedPerURL,
+			MaxFeaturedTags:          cfg.Accounts.MaxFeaturedTags,
+			MaxPinnedStatuses:        cfg.Accounts.MaxPinnedStatuses,
+			MaxPollOptions:           cfg.Polls.MaxOptions,
+			MaxPollOptionCharacters:  cfg.Polls.MaxCharactersPerOption,
+			MinPollExpiration:        cfg.Polls.MinExpiration,
+			MaxPollExpiration:        cfg.Polls.MaxExpiration,
+			SupportedMimeTypes:       cfg.MediaAttachments.SupportedMimeTypes,
+			ImageSizeLimit:           cfg.MediaAttachments.ImageSizeLimit,
+			VideoSizeLimit:           cfg.MediaAttachments.VideoSizeLimit,
+		},
+	}
+}
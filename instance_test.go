@@ -2,9 +2,11 @@ package mastodon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -84,6 +86,13 @@ func TestGetInstanceV2(t *testing.T) {
 	if ins.Domain != "mastodon.social" {
 		t.Fatalf("want %q but %q", "mastodon.social", ins.Domain)
 	}
+	cfg := ins.Configuration
+	if cfg.Polls.MaxOptions != 4 || cfg.MediaAttachments.VideoFrameRateLimit != 60 || len(cfg.MediaAttachments.SupportedMimeTypes) != 2 {
+		t.Fatalf("unexpected configuration: %+v", cfg)
+	}
+	if cfg.Statuses.Raw["characters_reserved_per_url"] != float64(23) {
+		t.Fatalf("want %v but %v", 23, cfg.Statuses.Raw["characters_reserved_per_url"])
+	}
 }
 
 func TestGetInstanceMore(t *testing.T) {
@@ -94,7 +103,7 @@ func TestGetInstanceMore(t *testing.T) {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
-		fmt.Fprintln(w, `{"title": "mastodon", "uri": "http://mstdn.example.com", "description": "test mastodon", "email": "mstdn@mstdn.example.com", "version": "0.0.1", "urls":{"foo":"http://stream1.example.com", "bar": "http://stream2.example.com"}, "thumbnail": "http://mstdn.example.com/logo.png", "configuration":{"accounts": {"max_featured_tags": 10}, "statuses": {"max_characters": 500}}, "stats":{"user_count":1, "status_count":2, "domain_count":3}}}`)
+		fmt.Fprintln(w, `{"title": "mastodon", "uri": "http://mstdn.example.com", "description": "test mastodon", "email": "mstdn@mstdn.example.com", "version": "0.0.1", "urls":{"foo":"http://stream1.example.com", "bar": "http://stream2.example.com"}, "thumbnail": "http://mstdn.example.com/logo.png", "configuration":{"accounts": {"max_featured_tags": 10}, "statuses": {"max_characters": 500, "max_toot_chars_extra": 7}}, "stats":{"user_count":1, "status_count":2, "domain_count":3}}}`)
 	}))
 	defer ts.Close()
 
@@ -154,7 +163,16 @@ func TestGetInstanceMore(t *testing.T) {
 		t.Error("expected accounts to be non nil")
 	}
 	if cfg.Statuses == nil {
-		t.Error("expected statuses to be non nil")
+		t.Fatal("expected statuses to be non nil")
+	}
+	if cfg.Accounts.MaxFeaturedTags != 10 || cfg.Statuses.MaxCharacters != 500 {
+		t.Fatalf("want %d and %d but %+v and %+v", 10, 500, cfg.Accounts, cfg.Statuses)
+	}
+	if cfg.Statuses.Raw["max_toot_chars_extra"] != float64(7) {
+		t.Fatalf("want %v but %v", 7, cfg.Statuses.Raw["max_toot_chars_extra"])
+	}
+	if cfg.Polls != nil || cfg.MediaAttachments != nil {
+		t.Fatalf("expected polls and media attachments to be nil")
 	}
 
 }
@@ -220,3 +238,202 @@ func TestGetInstancePeers(t *testing.T) {
 		t.Fatalf("want %q but %q", "mstdn.jp", peers[1])
 	}
 }
+
+func TestGetInstanceTranslationLanguages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instance/translation_languages" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"en":["de","es","ja"],"ja":["en"]}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server: ts.URL,
+	})
+	languages, err := client.GetInstanceTranslationLanguages(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(languages) != 2 {
+		t.Fatalf("result should be two: %d", len(languages))
+	}
+	if len(languages["en"]) != 3 || languages["en"][1] != "es" {
+		t.Fatalf("want %v but %v", []string{"de", "es", "ja"}, languages["en"])
+	}
+	if len(languages["ja"]) != 1 || languages["ja"][0] != "en" {
+		t.Fatalf("want %v but %v", []string{"en"}, languages["ja"])
+	}
+}
+
+func TestGetInstancePrivacyPolicy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instance/privacy_policy" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"updated_at":"2022-10-07T07:21:36.000Z","content":"<p>privacy</p>"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server: ts.URL,
+	})
+	policy, err := client.GetInstancePrivacyPolicy(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if policy.Content != "<p>privacy</p>" {
+		t.Fatalf("want %q but %q", "<p>privacy</p>", policy.Content)
+	}
+	if want := time.Date(2022, 10, 7, 7, 21, 36, 0, time.UTC); !policy.UpdatedAt.Equal(want) {
+		t.Fatalf("want %v but %v", want, policy.UpdatedAt)
+	}
+}
+
+func TestGetInstanceTermsOfService(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instance/terms_of_service" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"effective_date":"2025-04-15","effective":true,"content":"<p>terms</p>","succeeded_by":null}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server: ts.URL,
+	})
+	terms, err := client.GetInstanceTermsOfService(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if terms.EffectiveDate != "2025-04-15" {
+		t.Fatalf("want %q but %q", "2025-04-15", terms.EffectiveDate)
+	}
+	if !terms.Effective {
+		t.Fatalf("want %v but %v", true, terms.Effective)
+	}
+	if terms.Content != "<p>terms</p>" {
+		t.Fatalf("want %q but %q", "<p>terms</p>", terms.Content)
+	}
+	if terms.SucceededBy != "" {
+		t.Fatalf("want %q but %q", "", terms.SucceededBy)
+	}
+}
+
+func TestInstanceInfo(t *testing.T) {
+	var v1 Instance
+	err := json.Unmarshal([]byte(`{
+		"uri": "mastodon.example",
+		"title": "Example",
+		"version": "4.0.2",
+		"email": "admin@mastodon.example",
+		"urls": {"streaming_api": "wss://streaming.mastodon.example"},
+		"contact_account": {"acct": "admin"},
+		"configuration": {
+			"accounts": {"max_featured_tags": 10},
+			"statuses": {"max_characters": 500, "max_media_attachments": 4, "characters_reserved_per_url": 23},
+			"media_attachments": {"supported_mime_types": ["image/png", "video/mp4"], "image_size_limit": 10485760},
+			"polls": {"max_options": 4, "max_characters_per_option": 50, "min_expiration": 300, "max_expiration": 2629746}
+		}
+	}`), &v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v2 InstanceV2
+	err = json.Unmarshal([]byte(`{
+		"domain": "mastodon.example",
+		"title": "Example",
+		"version": "4.0.2",
+		"configuration": {
+			"urls": {"streaming": "wss://streaming.mastodon.example"},
+			"accounts": {"max_featured_tags": 10},
+			"statuses": {"max_characters": 500, "max_media_attachments": 4, "characters_reserved_per_url": 23},
+			"media_attachments": {"supported_mime_types": ["image/png", "video/mp4"], "image_size_limit": 10485760},
+			"polls": {"max_options": 4, "max_characters_per_option": 50, "min_expiration": 300, "max_expiration": 2629746}
+		},
+		"contact": {"email": "admin@mastodon.example", "account": {"acct": "admin"}},
+		"rules": [{"id": "1", "text": "Be nice"}]
+	}`), &v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info1, info2 := v1.Info(), v2.Info()
+	if len(info2.Rules) != 1 {
+		t.Fatalf("result should be one: %d", len(info2.Rules))
+	}
+	info2.Rules = nil
+	if !reflect.DeepEqual(info1, info2) {
+		t.Fatalf("want %+v but %+v", info2, info1)
+	}
+	if info1.Domain != "mastodon.example" || info1.StreamingURL != "wss://streaming.mastodon.example" || info1.ContactAccount.Acct != "admin" {
+		t.Fatalf("unexpected info: %+v", info1)
+	}
+	if info1.Limits.MaxCharacters != 500 || info1.Limits.MaxPollExpiration != 2629746 || len(info1.Limits.SupportedMimeTypes) != 2 {
+		t.Fatalf("unexpected limits: %+v", info1.Limits)
+	}
+}
+
+func TestGetInstanceInfo(t *testing.T) {
+	v2 := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2/instance" && v2:
+			fmt.Fprintln(w, `{"domain": "mastodon.example", "version": "4.2.0", "source_url": "https://github.com/mastodon/mastodon", "configuration": {"urls": {"streaming": "wss://streaming.mastodon.example"}, "statuses": {"max_characters": 500}, "translation": {"enabled": true}}, "rules": [{"id": "1", "text": "Be nice"}]}`)
+		case r.URL.Path == "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "https://old.example/", "version": "3.5.3", "urls": {"streaming_api": "wss://old.example"}, "configuration": {"statuses": {"max_characters": 1000}}}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	info, err := client.GetInstanceInfo(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if info.Domain != "mastodon.example" || info.StreamingURL != "wss://streaming.mastodon.example" || len(info.Rules) != 1 || !info.TranslationEnabled {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	v2 = false
+	info, err = client.GetInstanceInfo(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if info.Domain != "old.example" || info.Version != "3.5.3" || info.Limits.MaxCharacters != 1000 || info.SourceURL != "" {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	client = NewClient(&Config{Server: ts.URL + "/missing"})
+	if _, err := client.GetInstanceInfo(context.Background()); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestGetCustomEmojis(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/custom_emojis" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `[{"shortcode": "blobcat", "url": "https://example.com/blobcat.png", "static_url": "https://example.com/blobcat.png", "visible_in_picker": true, "category": "blobs"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	emojis, err := client.GetCustomEmojis(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(emojis) != 1 {
+		t.Fatalf("result should be one: %d", len(emojis))
+	}
+	if emojis[0].ShortCode != "blobcat" || emojis[0].Category != "blobs" {
+		t.Fatalf("want %q but %q", "blobcat", emojis[0].ShortCode)
+	}
+}
@@ -0,0 +1,274 @@
+//go:build integration
+// +build integration
+
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The integration tests run against a real Mastodon instance. By default a
+// disposable instance is started with docker compose from
+// testdata/integration/docker-compose.yml and removed afterwards:
+//
+//	go test -tags integration -run Integration ./...
+//
+// To use an instance that is already running, set
+// MASTODON_INTEGRATION_SERVER and MASTODON_INTEGRATION_TOKEN (an access token
+// with "read write follow" scopes). MASTODON_INTEGRATION_STREAMING overrides
+// the streaming server URL and MASTODON_INTEGRATION_KEEP leaves the compose
+// stack running after the tests finish.
+
+const (
+	integrationComposeFile = "testdata/integration/docker-compose.yml"
+	integrationUsername    = "gomastodon"
+	integrationEmail       = "gomastodon@localhost"
+)
+
+var integration struct {
+	server    string
+	streaming string
+	token     string
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runIntegration(m))
+}
+
+func runIntegration(m *testing.M) int {
+	integration.server = os.Getenv("MASTODON_INTEGRATION_SERVER")
+	integration.streaming = os.Getenv("MASTODON_INTEGRATION_STREAMING")
+	integration.token = os.Getenv("MASTODON_INTEGRATION_TOKEN")
+
+	if integration.server == "" {
+		integration.server = "http://localhost:3000"
+		if integration.streaming == "" {
+			integration.streaming = "http://localhost:4000"
+		}
+		// The stack is torn down even if it fails to come up, since up -d
+		// may have started some of its containers.
+		if os.Getenv("MASTODON_INTEGRATION_KEEP") == "" {
+			defer compose("down", "-v")
+		}
+		if err := composeUp(); err != nil {
+			fmt.Fprintf(os.Stderr, "integration: %v\n", err)
+			return 1
+		}
+	}
+	if integration.streaming == "" {
+		integration.streaming = integration.server
+	}
+	if integration.token == "" {
+		fmt.Fprintln(os.Stderr, "integration: MASTODON_INTEGRATION_TOKEN is required")
+		return 1
+	}
+
+	return m.Run()
+}
+
+func compose(args ...string) ([]byte, error) {
+	cmd := exec.Command("docker", append([]string{"compose", "-f", integrationComposeFile}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose %s: %v: %s", strings.Join(args, " "), err, stderr.String())
+	}
+	return out, nil
+}
+
+func composeUp() error {
+	if _, err := compose("up", "-d"); err != nil {
+		return err
+	}
+	if err := waitForInstance(integration.server, 10*time.Minute); err != nil {
+		return err
+	}
+
+	_, err := compose("exec", "-T", "web", "bin/tootctl", "accounts", "create", integrationUsername,
+		"--email", integrationEmail, "--confirmed", "--approve", "--role", "Owner")
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(`u = User.find_by!(email: %q)
+app = Doorkeeper::Application.create!(name: "go-mastodon-integration", redirect_uri: "urn:ietf:wg:oauth:2.0:oob", scopes: "read write follow")
+puts Doorkeeper::AccessToken.create!(application_id: app.id, resource_owner_id: u.id, scopes: "read write follow").token`, integrationEmail)
+	out, err := compose("exec", "-T", "web", "bin/rails", "runner", script)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	integration.token = strings.TrimSpace(lines[len(lines)-1])
+	return nil
+}
+
+func waitForInstance(server string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(server + "/api/v1/instance")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		time.Sleep(5 * time.Second)
+	}
+	return fmt.Errorf("instance %s not ready after %v", server, timeout)
+}
+
+func integrationClient(t *testing.T) *Client {
+	t.Helper()
+	return NewClient(&Config{
+		Server:      integration.server,
+		AccessToken: integration.token,
+	})
+}
+
+func integrationPost(t *testing.T, c *Client, toot *Toot) *Status {
+	t.Helper()
+	status, err := c.PostStatus(context.Background(), toot)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.DeleteStatus(context.Background(), status.ID); err != nil {
+			t.Errorf("cleanup of status %s failed: %v", status.ID, err)
+		}
+	})
+	return status
+}
+
+func TestIntegrationAuth(t *testing.T) {
+	ctx := context.Background()
+	app, err := RegisterApp(ctx, &AppConfig{
+		Server:     integration.server,
+		ClientName: "go-mastodon-integration-auth",
+		Scopes:     "read write follow",
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	client := NewClient(&Config{
+		Server:       integration.server,
+		ClientID:     app.ClientID,
+		ClientSecret: app.ClientSecret,
+	})
+	if err := client.AuthenticateApp(ctx); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	verification, err := client.VerifyAppCredentials(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if verification.Name != "go-mastodon-integration-auth" {
+		t.Fatalf("want %q but %q", "go-mastodon-integration-auth", verification.Name)
+	}
+
+	account, err := integrationClient(t).GetAccountCurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if account.Username != integrationUsername && os.Getenv("MASTODON_INTEGRATION_SERVER") == "" {
+		t.Fatalf("want %q but %q", integrationUsername, account.Username)
+	}
+}
+
+func TestIntegrationStatus(t *testing.T) {
+	ctx := context.Background()
+	client := integrationClient(t)
+
+	status := integrationPost(t, client, &Toot{
+		Status:     "go-mastodon integration status",
+		Visibility: VisibilityUnlisted,
+	})
+	got, err := client.GetStatus(ctx, status.ID)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !strings.Contains(got.Content, "go-mastodon integration status") {
+		t.Fatalf("unexpected content: %q", got.Content)
+	}
+	if got.Visibility != VisibilityUnlisted {
+		t.Fatalf("want %q but %q", VisibilityUnlisted, got.Visibility)
+	}
+
+	got, err = client.Favourite(ctx, status.ID)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if got.FavouritesCount != 1 {
+		t.Fatalf("want %d but %d", 1, got.FavouritesCount)
+	}
+}
+
+func TestIntegrationMedia(t *testing.T) {
+	ctx := context.Background()
+	client := integrationClient(t)
+
+	attachment, err := client.UploadMedia(ctx, "testdata/logo.png")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if attachment.Type != "image" {
+		t.Fatalf("want %q but %q", "image", attachment.Type)
+	}
+
+	status := integrationPost(t, client, &Toot{
+		Status:     "go-mastodon integration media",
+		MediaIDs:   []ID{attachment.ID},
+		Visibility: VisibilityUnlisted,
+	})
+	if len(status.MediaAttachments) != 1 {
+		t.Fatalf("result should be one: %d", len(status.MediaAttachments))
+	}
+	if status.MediaAttachments[0].ID != attachment.ID {
+		t.Fatalf("want %q but %q", attachment.ID, status.MediaAttachments[0].ID)
+	}
+}
+
+func TestIntegrationStreaming(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	streamer := NewClient(&Config{
+		Server:      integration.streaming,
+		AccessToken: integration.token,
+	})
+	q, err := streamer.StreamingUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// Give the streaming server a moment to register the subscription.
+	time.Sleep(2 * time.Second)
+	status := integrationPost(t, integrationClient(t), &Toot{
+		Status:     "go-mastodon integration streaming",
+		Visibility: VisibilityPublic,
+	})
+
+	for e := range q {
+		switch event := e.(type) {
+		case *UpdateEvent:
+			if event.Status.ID == status.ID {
+				cancel()
+				return
+			}
+		case *ErrorEvent:
+			if ctx.Err() != nil {
+				t.Fatalf("no update event for status %s: %v", status.ID, event)
+			}
+		}
+	}
+	t.Fatalf("stream closed before update event for status %s", status.ID)
+}
@@ -0,0 +1,133 @@
+//go:build go1.23
+// +build go1.23
+
+package mastodon
+
+import (
+	"context"
+	"iter"
+)
+
+// Pages returns an iterator over the pages returned by fetch, starting from
+// pg. It follows the next page until the server returns an empty page or no
+// next page, ctx is done or the loop breaks. An error is yielded once and
+// ends the iteration. Rate limited requests are retried by the client.
+//
+//	for accounts, err := range mastodon.Pages(ctx, nil, func(ctx context.Context, pg *mastodon.Pagination) ([]*mastodon.Account, error) {
+//		return c.GetAccountFollowers(ctx, id, pg)
+//	}) {
+//		...
+//	}
+func Pages[T any](ctx context.Context, pg *Pagination, fetch func(ctx context.Context, pg *Pagination) ([]T, error)) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		var p Pagination
+		if pg != nil {
+			p = *pg
+		}
+		stopped := false
+		err := walkPages(ctx, 0, &p, func(p *Pagination) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			items, err := fetch(ctx, p)
+			if err != nil {
+				return false, err
+			}
+			if !yield(items, nil) {
+				stopped = true
+				return false, nil
+			}
+			return len(items) > 0, nil
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// Items is like Pages but yields the items of the pages one by one.
+func Items[T any](ctx context.Context, pg *Pagination, fetch func(ctx context.Context, pg *Pagination) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for items, err := range Pages(ctx, pg, fetch) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IterateAccountStatuses returns an iterator over the statuses of an account.
+func (c *Client) IterateAccountStatuses(ctx context.Context, id ID) iter.Seq2[*Status, error] {
+	return Items(ctx, nil, func(ctx context.Context, pg *Pagination) ([]*Status, error) {
+		return c.GetAccountStatuses(ctx, id, pg)
+	})
+}
+
+// IterateFollowers returns an iterator over the followers of an account.
+func (c *Client) IterateFollowers(ctx context.Context, id ID) iter.Seq2[*Account, error] {
+	return Items(ctx, &Pagination{Limit: 80}, func(ctx context.Context, pg *Pagination) ([]*Account, error) {
+		return c.GetAccountFollowers(ctx, id, pg)
+	})
+}
+
+// IterateFollowing returns an iterator over the accounts followed by an
+// account.
+func (c *Client) IterateFollowing(ctx context.Context, id ID) iter.Seq2[*Account, error] {
+	return Items(ctx, &Pagination{Limit: 80}, func(ctx context.Context, pg *Pagination) ([]*Account, error) {
+		return c.GetAccountFollowing(ctx, id, pg)
+	})
+}
+
+// IterateBlocks returns an iterator over the accounts blocked by the current
+// user.
+func (c *Client) IterateBlocks(ctx context.Context) iter.Seq2[*Account, error] {
+	return Items(ctx, &Pagination{Limit: 80}, func(ctx context.Context, pg *Pagination) ([]*Account, error) {
+		return c.GetBlocks(ctx, pg)
+	})
+}
+
+// IterateMutes returns an iterator over the accounts muted by the current
+// user.
+func (c *Client) IterateMutes(ctx context.Context) iter.Seq2[*Account, error] {
+	return Items(ctx, &Pagination{Limit: 80}, func(ctx context.Context, pg *Pagination) ([]*Account, error) {
+		return c.GetMutes(ctx, pg)
+	})
+}
+
+// IterateFavourites returns an iterator over the favourites of the current
+// user.
+func (c *Client) IterateFavourites(ctx context.Context) iter.Seq2[*Status, error] {
+	return Items(ctx, &Pagination{Limit: 40}, func(ctx context.Context, pg *Pagination) ([]*Status, error) {
+		return c.GetFavourites(ctx, pg)
+	})
+}
+
+// IterateBookmarks returns an iterator over the bookmarks of the current
+// user.
+func (c *Client) IterateBookmarks(ctx context.Context) iter.Seq2[*Status, error] {
+	return Items(ctx, &Pagination{Limit: 40}, func(ctx context.Context, pg *Pagination) ([]*Status, error) {
+		return c.GetBookmarks(ctx, pg)
+	})
+}
+
+// IterateNotifications returns an iterator over the notifications of the
+// current user.
+func (c *Client) IterateNotifications(ctx context.Context) iter.Seq2[*Notification, error] {
+	return Items(ctx, &Pagination{Limit: 40}, func(ctx context.Context, pg *Pagination) ([]*Notification, error) {
+		return c.GetNotifications(ctx, pg)
+	})
+}
+
+// IterateTimelineList returns an iterator over the statuses of the list id.
+func (c *Client) IterateTimelineList(ctx context.Context, id ID) iter.Seq2[*Status, error] {
+	return Items(ctx, &Pagination{Limit: 40}, func(ctx context.Context, pg *Pagination) ([]*Status, error) {
+		return c.GetTimelineList(ctx, id, pg)
+	})
+}
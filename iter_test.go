@@ -0,0 +1,112 @@
+//go:build go1.23
+// +build go1.23
+
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIterateFollowers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/1/followers" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("limit") != "80" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Query().Get("max_id") {
+		case "":
+			w.Header().Set("Link", `<http://example.com/api/v1/accounts/1/followers?max_id=3>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "5"}, {"id": "4"}]`)
+		case "3":
+			w.Header().Set("Link", `<http://example.com/api/v1/accounts/1/followers?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3"}]`)
+		default:
+			fmt.Fprintln(w, `[]`)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	var ids []ID
+	for account, err := range client.IterateFollowers(context.Background(), "1") {
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		ids = append(ids, account.ID)
+	}
+	if fmt.Sprint(ids) != "[5 4 3]" {
+		t.Fatalf("want %q but %q", "[5 4 3]", fmt.Sprint(ids))
+	}
+
+	ids = nil
+	for account, err := range client.IterateFollowers(context.Background(), "1") {
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		ids = append(ids, account.ID)
+		break
+	}
+	if len(ids) != 1 {
+		t.Fatalf("result should be one: %d", len(ids))
+	}
+
+	var errs int
+	for _, err := range client.IterateFollowers(context.Background(), "2") {
+		if err == nil {
+			t.Fatalf("should be fail: %v", err)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Fatalf("result should be one: %d", errs)
+	}
+}
+
+func TestPagesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range Pages(ctx, nil, func(ctx context.Context, pg *Pagination) ([]int, error) {
+		t.Fatal("fetch should not be called")
+		return nil, nil
+	}) {
+		if err != context.Canceled {
+			t.Fatalf("want %v but %v", context.Canceled, err)
+		}
+	}
+}
+
+func TestIterateTimelineList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/list/7" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("max_id") == "" {
+			w.Header().Set("Link", `<http://example.com/api/v1/timelines/list/7?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3"}, {"id": "2"}]`)
+			return
+		}
+		fmt.Fprintln(w, `[]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	var ids []ID
+	for status, err := range client.IterateTimelineList(context.Background(), "7") {
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		ids = append(ids, status.ID)
+	}
+	if fmt.Sprint(ids) != "[3 2]" {
+		t.Fatalf("want %q but %q", "[3 2]", fmt.Sprint(ids))
+	}
+}
@@ -0,0 +1,229 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint holds the progress of a Job.
+type Checkpoint struct {
+	// Pagination is the next page to fetch.
+	Pagination Pagination `json:"pagination"`
+	// State is the JSON encoded Job.State.
+	State json.RawMessage `json:"state,omitempty"`
+	// Done reports whether the job has finished.
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore persists the checkpoints of jobs by name.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the job, or nil if there is none.
+	Load(ctx context.Context, job string) (*Checkpoint, error)
+	Save(ctx context.Context, job string, cp *Checkpoint) error
+	Delete(ctx context.Context, job string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore that keeps checkpoints in
+// memory, so jobs can be resumed after an error but not after a restart.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, job string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.checkpoints[job]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, job string, cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoints == nil {
+		s.checkpoints = map[string]Checkpoint{}
+	}
+	s.checkpoints[job] = *cp
+	return nil
+}
+
+// Delete implements CheckpointStore.
+func (s *MemoryCheckpointStore) Delete(ctx context.Context, job string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, job)
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that keeps every checkpoint in a
+// JSON file in Dir.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s *FileCheckpointStore) path(job string) string {
+	return filepath.Join(s.Dir, url.PathEscape(job)+".json")
+}
+
+// Load implements CheckpointStore.
+func (s *FileCheckpointStore) Load(ctx context.Context, job string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(s.path(job))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically, so a
+// crash while saving leaves the previous checkpoint intact.
+func (s *FileCheckpointStore) Save(ctx context.Context, job string, cp *Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), s.path(job))
+}
+
+// Delete implements CheckpointStore.
+func (s *FileCheckpointStore) Delete(ctx context.Context, job string) error {
+	err := os.Remove(s.path(job))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Job is a paginated walk that saves a checkpoint after every page, so it
+// can resume where it left off after an error or a restart.
+//
+// A page is checkpointed after fetch returns, so a page that was being
+// processed when the process stopped is fetched again on resume.
+type Job struct {
+	Name  string
+	Store CheckpointStore
+	// State is saved with every checkpoint and restored on resume. It must
+	// be a pointer to a value that can be encoded as JSON, or nil.
+	State interface{}
+	// Interval is the delay between page requests.
+	Interval time.Duration
+}
+
+// Resuming reports whether the job has an unfinished checkpoint.
+func (j *Job) Resuming(ctx context.Context) (bool, error) {
+	cp, err := j.Store.Load(ctx, j.Name)
+	if err != nil {
+		return false, err
+	}
+	return cp != nil && !cp.Done, nil
+}
+
+// Reset deletes the checkpoint of the job, so it starts over.
+func (j *Job) Reset(ctx context.Context) error {
+	return j.Store.Delete(ctx, j.Name)
+}
+
+// Walk calls fetch with successive pages, like a paginated walk, starting
+// from the checkpoint of the job if there is one, or from pg otherwise.
+// fetch reports whether there are more pages to read. If the job has already
+// finished, Walk only restores State; call Reset to run it again.
+func (j *Job) Walk(ctx context.Context, pg *Pagination, fetch func(pg *Pagination) (bool, error)) error {
+	cp, err := j.Store.Load(ctx, j.Name)
+	if err != nil {
+		return err
+	}
+	if pg == nil {
+		pg = &Pagination{}
+	}
+	if cp != nil {
+		if j.State != nil && len(cp.State) > 0 {
+			if err := json.Unmarshal(cp.State, j.State); err != nil {
+				return err
+			}
+		}
+		if cp.Done {
+			return nil
+		}
+		*pg = cp.Pagination
+	}
+
+	return walkPages(ctx, j.Interval, pg, func(pg *Pagination) (bool, error) {
+		prev := pg.MaxID
+		more, err := fetch(pg)
+		if err != nil {
+			return false, err
+		}
+		cp := &Checkpoint{
+			Pagination: Pagination{MaxID: pg.MaxID, Limit: pg.Limit},
+			Done:       !more || pg.MaxID == "" || pg.MaxID == prev,
+			UpdatedAt:  time.Now(),
+		}
+		if j.State != nil {
+			if cp.State, err = json.Marshal(j.State); err != nil {
+				return false, err
+			}
+		}
+		if err := j.Store.Save(ctx, j.Name, cp); err != nil {
+			return false, err
+		}
+		return more, nil
+	})
+}
+
+// walkJob walks the pages like walkPages, or, when store is set, as the Job
+// name saving state with every checkpoint, so the helpers reading many
+// pages can resume after a restart. Call deleteJobs once the result of the
+// walk is no longer needed, so the next run starts over.
+func walkJob(ctx context.Context, store CheckpointStore, name string, state interface{}, interval time.Duration, pg *Pagination, fetch func(pg *Pagination) (bool, error)) error {
+	if store == nil {
+		return walkPages(ctx, interval, pg, fetch)
+	}
+	job := &Job{Name: name, Store: store, State: state, Interval: interval}
+	return job.Walk(ctx, pg, fetch)
+}
+
+// deleteJobs deletes the checkpoints of the jobs names from store, if set.
+func deleteJobs(ctx context.Context, store CheckpointStore, names ...string) error {
+	if store == nil {
+		return nil
+	}
+	for _, name := range names {
+		if err := store.Delete(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func testJobResume(t *testing.T, store CheckpointStore) {
+	pages := map[ID]ID{"": "30", "30": "20", "20": "10"}
+	var fetched []ID
+	fail := ID("20")
+	fetch := func(pg *Pagination) (bool, error) {
+		if pg.MaxID == fail {
+			return false, errors.New("fail")
+		}
+		fetched = append(fetched, pg.MaxID)
+		pg.MaxID = pages[pg.MaxID]
+		return true, nil
+	}
+
+	var count int
+	job := &Job{Name: "test/job", Store: store, State: &count, Interval: -1}
+	counting := func(pg *Pagination) (bool, error) {
+		more, err := fetch(pg)
+		if err == nil {
+			count++
+		}
+		return more, err
+	}
+	if err := job.Walk(context.Background(), nil, counting); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if resuming, err := job.Resuming(context.Background()); err != nil || !resuming {
+		t.Fatalf("job should be resuming: %v", err)
+	}
+
+	// A new process restores the state and continues after the last page.
+	fail = ""
+	count = 0
+	job = &Job{Name: "test/job", Store: store, State: &count, Interval: -1}
+	if err := job.Walk(context.Background(), nil, counting); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(fetched) != 4 || fetched[2] != "20" || fetched[3] != "10" {
+		t.Fatalf("unexpected pages: %v", fetched)
+	}
+	if count != 4 {
+		t.Fatalf("want %v but %v", 4, count)
+	}
+
+	// A finished job only restores its state until it is reset.
+	count = 0
+	if err := job.Walk(context.Background(), nil, counting); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(fetched) != 4 {
+		t.Fatalf("job should not run again: %v", fetched)
+	}
+	if count != 4 {
+		t.Fatalf("want %v but %v", 4, count)
+	}
+	if err := job.Reset(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if cp, err := store.Load(context.Background(), "test/job"); err != nil || cp != nil {
+		t.Fatalf("checkpoint should be deleted: %v %v", cp, err)
+	}
+}
+
+func TestJobMemoryStore(t *testing.T) {
+	testJobResume(t, &MemoryCheckpointStore{})
+}
+
+func TestJobFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mastodon-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testJobResume(t, &FileCheckpointStore{Dir: dir})
+}
@@ -0,0 +1,16 @@
+package mastodon
+
+import "testing"
+
+func TestValidLanguage(t *testing.T) {
+	for _, code := range []string{"en", "ja", "sv", "pt-BR", "zh-TW", "sr-Latn", "ast", "chr", "pdc", "xal"} {
+		if !ValidLanguage(code) {
+			t.Fatalf("want %t but %t for %q", true, false, code)
+		}
+	}
+	for _, code := range []string{"", "EN", "eng", "en-US", "pt-br", "xx"} {
+		if ValidLanguage(code) {
+			t.Fatalf("want %t but %t for %q", false, true, code)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ListEvent is an event received on the stream of one of the user's lists.
+type ListEvent struct {
+	List  *List
+	Event Event
+}
+
+// ListMembersEvent is a struct for passing list membership changes to app.
+// It is sent on the ListAggregator feed whenever accounts are added to or
+// removed from a list.
+type ListMembersEvent struct {
+	Added   []ID
+	Removed []ID
+}
+
+func (e *ListMembersEvent) event() {}
+
+// ListAggregator streams all lists of the current user into a single feed.
+//
+// Lists and their members are refreshed every RefreshInterval: streams are
+// started for new lists, stopped for deleted lists, and membership changes
+// are reported as ListMembersEvent. Changes made through Add and Remove are
+// reported immediately.
+type ListAggregator struct {
+	Client *Client
+
+	// RefreshInterval defaults to five minutes.
+	RefreshInterval time.Duration
+
+	mu      sync.Mutex
+	q       chan *ListEvent
+	wg      sync.WaitGroup
+	ctx     context.Context
+	done    bool
+	lists   map[ID]*List
+	members map[ID]map[ID]bool
+	cancels map[ID]context.CancelFunc
+}
+
+// Stream starts streaming all lists and returns the merged feed. The feed is
+// closed after ctx is canceled.
+func (a *ListAggregator) Stream(ctx context.Context) (chan *ListEvent, error) {
+	a.mu.Lock()
+	a.q = make(chan *ListEvent)
+	a.ctx = ctx
+	a.done = false
+	a.lists = map[ID]*List{}
+	a.members = map[ID]map[ID]bool{}
+	a.cancels = map[ID]context.CancelFunc{}
+	a.mu.Unlock()
+
+	if err := a.Refresh(ctx); err != nil {
+		a.stopAll()
+		return nil, err
+	}
+
+	interval := a.RefreshInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if err := a.Refresh(ctx); err != nil {
+					a.send(ctx, &ListEvent{Event: &ErrorEvent{err}})
+				}
+			}
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		a.stopAll()
+		a.wg.Wait()
+		close(a.q)
+	}()
+
+	return a.q, nil
+}
+
+// Members returns the IDs of the accounts in the list as last seen by the
+// aggregator.
+func (a *ListAggregator) Members(list ID) []ID {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var ids []ID
+	for id := range a.members[list] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Refresh reconciles the streamed lists and their members with the server.
+// It returns an error if Stream wasn't called.
+func (a *ListAggregator) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	streaming := a.ctx != nil
+	a.mu.Unlock()
+	if !streaming {
+		return errors.New("mastodon: list aggregator isn't streaming")
+	}
+
+	lists, err := a.Client.GetLists(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := map[ID]bool{}
+	for _, l := range lists {
+		current[l.ID] = true
+		accounts, err := a.Client.GetListAccounts(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		ids := make([]ID, len(accounts))
+		for i, acct := range accounts {
+			ids[i] = acct.ID
+		}
+		if err := a.startList(l); err != nil {
+			return err
+		}
+		a.setMembers(ctx, l.ID, ids)
+	}
+
+	a.mu.Lock()
+	for id, cancel := range a.cancels {
+		if !current[id] {
+			cancel()
+			delete(a.cancels, id)
+			delete(a.lists, id)
+			delete(a.members, id)
+		}
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// Add adds accounts to a list and records the change.
+func (a *ListAggregator) Add(ctx context.Context, list ID, accounts ...ID) error {
+	if err := a.Client.AddToList(ctx, list, accounts...); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	ids := a.memberIDs(list)
+	a.mu.Unlock()
+	a.setMembers(ctx, list, append(ids, accounts...))
+	return nil
+}
+
+// Remove removes accounts from a list and records the change.
+func (a *ListAggregator) Remove(ctx context.Context, list ID, accounts ...ID) error {
+	if err := a.Client.RemoveFromList(ctx, list, accounts...); err != nil {
+		return err
+	}
+	removed := map[ID]bool{}
+	for _, id := range accounts {
+		removed[id] = true
+	}
+	a.mu.Lock()
+	var ids []ID
+	for _, id := range a.memberIDs(list) {
+		if !removed[id] {
+			ids = append(ids, id)
+		}
+	}
+	a.mu.Unlock()
+	a.setMembers(ctx, list, ids)
+	return nil
+}
+
+func (a *ListAggregator) memberIDs(list ID) []ID {
+	var ids []ID
+	for id := range a.members[list] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// setMembers replaces the members of a list and sends a ListMembersEvent if
+// they changed. The first time members of a list are set no event is sent.
+func (a *ListAggregator) setMembers(ctx context.Context, list ID, ids []ID) {
+	a.mu.Lock()
+	old, known := a.members[list]
+	l := a.lists[list]
+	next := map[ID]bool{}
+	e := &ListMembersEvent{}
+	for _, id := range ids {
+		next[id] = true
+		if known && !old[id] {
+			e.Added = append(e.Added, id)
+		}
+	}
+	for id := range old {
+		if !next[id] {
+			e.Removed = append(e.Removed, id)
+		}
+	}
+	if l == nil {
+		a.mu.Unlock()
+		return
+	}
+	a.members[list] = next
+	notify := !a.done && (len(e.Added) > 0 || len(e.Removed) > 0)
+	if notify {
+		a.wg.Add(1)
+	}
+	a.mu.Unlock()
+
+	if notify {
+		defer a.wg.Done()
+		a.send(ctx, &ListEvent{List: l, Event: e})
+	}
+}
+
+// startList starts the stream of l unless it is already streamed. The
+// stream is opened without holding a.mu, since opening it may discover the
+// streaming URL from the instance.
+func (a *ListAggregator) startList(l *List) error {
+	a.mu.Lock()
+	if a.done {
+		a.mu.Unlock()
+		return nil
+	}
+	if _, ok := a.cancels[l.ID]; ok {
+		a.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.mu.Unlock()
+
+	q, err := a.Client.StreamingList(ctx, l.ID)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.cancels[l.ID]; ok || a.done {
+		// Stopped, or started by a concurrent refresh, meanwhile.
+		cancel()
+		return nil
+	}
+	a.lists[l.ID] = l
+	a.cancels[l.ID] = cancel
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for e := range q {
+			a.send(ctx, &ListEvent{List: l, Event: e})
+		}
+	}()
+	return nil
+}
+
+func (a *ListAggregator) stopAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.done = true
+	for id, cancel := range a.cancels {
+		cancel()
+		delete(a.cancels, id)
+	}
+}
+
+func (a *ListAggregator) send(ctx context.Context, e *ListEvent) {
+	select {
+	case a.q <- e:
+	case <-ctx.Done():
+	case <-a.ctx.Done():
+	}
+}
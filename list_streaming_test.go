@@ -0,0 +1,113 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListAggregator(t *testing.T) {
+	var mu sync.Mutex
+	members := map[string]string{
+		"1": `[{"id": "10"}, {"id": "11"}]`,
+		"2": `[{"id": "20"}]`,
+	}
+	streamed := map[string]bool{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/lists":
+			fmt.Fprintln(w, `[{"id": "1", "title": "foo"}, {"id": "2", "title": "bar"}]`)
+		case "/api/v1/lists/1/accounts":
+			if r.Method == http.MethodDelete {
+				members["1"] = `[{"id": "10"}]`
+			}
+			fmt.Fprintln(w, members["1"])
+		case "/api/v1/lists/2/accounts":
+			fmt.Fprintln(w, members["2"])
+		case "/api/v1/streaming/list":
+			list := r.URL.Query().Get("list")
+			if streamed[list] {
+				return
+			}
+			streamed[list] = true
+			fmt.Fprintf(w, "event: update\ndata: {\"content\": \"from list %s\"}\n\n", list)
+			w.(http.Flusher).Flush()
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &ListAggregator{
+		Client:          NewClient(&Config{Server: ts.URL}),
+		RefreshInterval: time.Hour,
+	}
+	if err := a.Refresh(ctx); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	q, err := a.Stream(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	got := map[string]string{}
+	for len(got) < 2 {
+		e := <-q
+		if u, ok := e.Event.(*UpdateEvent); ok {
+			got[string(e.List.ID)] = u.Status.Content
+		}
+	}
+	if got["1"] != "from list 1" || got["2"] != "from list 2" {
+		t.Fatalf("unexpected updates: %v", got)
+	}
+
+	ids := a.Members("1")
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) != 2 || ids[0] != "10" || ids[1] != "11" {
+		t.Fatalf("unexpected members: %v", ids)
+	}
+
+	go func() {
+		if err := a.Remove(ctx, "1", "11"); err != nil {
+			t.Errorf("should not be fail: %v", err)
+		}
+	}()
+	for e := range q {
+		if m, ok := e.Event.(*ListMembersEvent); ok {
+			if e.List.ID != "1" || len(m.Removed) != 1 || m.Removed[0] != "11" || len(m.Added) != 0 {
+				t.Fatalf("unexpected members event: %v %+v", e.List.ID, m)
+			}
+			break
+		}
+	}
+
+	mu.Lock()
+	members["2"] = `[{"id": "20"}, {"id": "21"}]`
+	mu.Unlock()
+	go func() {
+		if err := a.Refresh(ctx); err != nil {
+			t.Errorf("should not be fail: %v", err)
+		}
+	}()
+	for e := range q {
+		if m, ok := e.Event.(*ListMembersEvent); ok {
+			if e.List.ID != "2" || len(m.Added) != 1 || m.Added[0] != "21" || len(m.Removed) != 0 {
+				t.Fatalf("unexpected members event: %v %+v", e.List.ID, m)
+			}
+			break
+		}
+	}
+
+	cancel()
+	for range q {
+	}
+}
@@ -5,12 +5,39 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 // List is metadata for a list of users.
 type List struct {
-	ID    ID     `json:"id"`
-	Title string `json:"title"`
+	ID            ID            `json:"id"`
+	Title         string        `json:"title"`
+	RepliesPolicy RepliesPolicy `json:"replies_policy"`
+	// Exclusive hides the statuses of the list from the home timeline,
+	// since Mastodon 4.2.
+	Exclusive bool `json:"exclusive"`
+}
+
+// ListOptions holds the settings of a list for CreateListWithOptions and
+// UpdateList.
+type ListOptions struct {
+	// RepliesPolicy defaults to RepliesPolicyList when creating a list and
+	// is left unchanged when empty on update.
+	RepliesPolicy RepliesPolicy
+	// Exclusive is left unchanged when nil.
+	Exclusive *bool
+}
+
+func (o *ListOptions) setValues(params url.Values) {
+	if o == nil {
+		return
+	}
+	if o.RepliesPolicy != "" {
+		params.Set("replies_policy", string(o.RepliesPolicy))
+	}
+	if o.Exclusive != nil {
+		params.Set("exclusive", strconv.FormatBool(*o.Exclusive))
+	}
 }
 
 // GetLists returns all the lists on the current account.
@@ -43,6 +70,16 @@ func (c *Client) GetListAccounts(ctx context.Context, id ID) ([]*Account, error)
 	return accounts, nil
 }
 
+// GetListAccountsPaginated returns a page of the accounts in a given list.
+func (c *Client) GetListAccountsPaginated(ctx context.Context, id ID, pg *Pagination) ([]*Account, error) {
+	var accounts []*Account
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/lists/%s/accounts", url.PathEscape(string(id))), nil, &accounts, pg)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
 // GetList retrieves a list by ID.
 func (c *Client) GetList(ctx context.Context, id ID) (*List, error) {
 	var list List
@@ -55,8 +92,15 @@ func (c *Client) GetList(ctx context.Context, id ID) (*List, error) {
 
 // CreateList creates a new list with a given title.
 func (c *Client) CreateList(ctx context.Context, title string) (*List, error) {
+	return c.CreateListWithOptions(ctx, title, nil)
+}
+
+// CreateListWithOptions creates a new list with a given title and the
+// settings of opts, which may be nil.
+func (c *Client) CreateListWithOptions(ctx context.Context, title string, opts *ListOptions) (*List, error) {
 	params := url.Values{}
 	params.Set("title", title)
+	opts.setValues(params)
 
 	var list List
 	err := c.doAPI(ctx, http.MethodPost, "/api/v1/lists", params, &list, nil)
@@ -68,8 +112,15 @@ func (c *Client) CreateList(ctx context.Context, title string) (*List, error) {
 
 // RenameList assigns a new title to a list.
 func (c *Client) RenameList(ctx context.Context, id ID, title string) (*List, error) {
+	return c.UpdateList(ctx, id, title, nil)
+}
+
+// UpdateList assigns a new title and the settings of opts, which may be nil,
+// to a list.
+func (c *Client) UpdateList(ctx context.Context, id ID, title string, opts *ListOptions) (*List, error) {
 	params := url.Values{}
 	params.Set("title", title)
+	opts.setValues(params)
 
 	var list List
 	err := c.doAPI(ctx, http.MethodPut, fmt.Sprintf("/api/v1/lists/%s", url.PathEscape(string(id))), params, &list, nil)
@@ -84,24 +135,39 @@ func (c *Client) DeleteList(ctx context.Context, id ID) error {
 	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/lists/%s", url.PathEscape(string(id))), nil, nil, nil)
 }
 
-// AddToList adds accounts to a list.
+// listAccountsChunk is the most accounts added to or removed from a list
+// in one request.
+const listAccountsChunk = 100
+
+// AddToList adds accounts to a list. Many accounts are added in chunks,
+// one request each; the chunks before an error stay added.
 //
 // Only accounts already followed by the user can be added to a list.
 func (c *Client) AddToList(ctx context.Context, list ID, accounts ...ID) error {
-	params := url.Values{}
-	for _, acct := range accounts {
-		params.Add("account_ids[]", string(acct))
-	}
-
-	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/lists/%s/accounts", url.PathEscape(string(list))), params, nil, nil)
+	return c.listAccounts(ctx, http.MethodPost, list, accounts)
 }
 
-// RemoveFromList removes accounts from a list.
+// RemoveFromList removes accounts from a list. Many accounts are removed in
+// chunks, one request each; the chunks before an error stay removed.
 func (c *Client) RemoveFromList(ctx context.Context, list ID, accounts ...ID) error {
-	params := url.Values{}
-	for _, acct := range accounts {
-		params.Add("account_ids[]", string(acct))
-	}
+	return c.listAccounts(ctx, http.MethodDelete, list, accounts)
+}
 
-	return c.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/lists/%s/accounts", url.PathEscape(string(list))), params, nil, nil)
+func (c *Client) listAccounts(ctx context.Context, method string, list ID, accounts []ID) error {
+	for len(accounts) > 0 {
+		n := len(accounts)
+		if n > listAccountsChunk {
+			n = listAccountsChunk
+		}
+		params := url.Values{}
+		for _, acct := range accounts[:n] {
+			params.Add("account_ids[]", string(acct))
+		}
+		err := c.doAPI(ctx, method, fmt.Sprintf("/api/v1/lists/%s/accounts", url.PathEscape(string(list))), params, nil, nil)
+		if err != nil {
+			return err
+		}
+		accounts = accounts[n:]
+	}
+	return nil
 }
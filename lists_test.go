@@ -3,8 +3,11 @@ package mastodon
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -109,6 +112,45 @@ func TestGetListAccounts(t *testing.T) {
 	}
 }
 
+func TestGetListAccountsPaginated(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/lists/1/accounts" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("max_id") == "" {
+			w.Header().Set("Link", `<http://example.com/api/v1/lists/1/accounts?max_id=3>; rel="next"`)
+			fmt.Fprintln(w, `[{"username": "foo"}]`)
+			return
+		}
+		fmt.Fprintln(w, `[{"username": "bar"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	pg := &Pagination{Limit: 1}
+	accounts, err := client.GetListAccountsPaginated(context.Background(), "1", pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Username != "foo" {
+		t.Fatalf("want %q but %v", "foo", accounts)
+	}
+	if !pg.HasNext() || pg.MaxID != "3" {
+		t.Fatalf("want %q but %q", "3", pg.MaxID)
+	}
+	accounts, err = client.GetListAccountsPaginated(context.Background(), "1", pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Username != "bar" {
+		t.Fatalf("want %q but %v", "bar", accounts)
+	}
+	if pg.HasNext() {
+		t.Fatalf("should be the last page: %q", pg.MaxID)
+	}
+}
+
 func TestGetList(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/lists/1" {
@@ -144,7 +186,11 @@ func TestCreateList(t *testing.T) {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
-		fmt.Fprintln(w, `{"id": "1", "title": "foo"}`)
+		policy := r.PostFormValue("replies_policy")
+		if policy == "" {
+			policy = "list"
+		}
+		fmt.Fprintf(w, `{"id": "1", "title": "foo", "replies_policy": %q, "exclusive": %s}`, policy, strconv.FormatBool(r.PostFormValue("exclusive") == "true"))
 	}))
 	defer ts.Close()
 
@@ -165,6 +211,20 @@ func TestCreateList(t *testing.T) {
 	if list.Title != "foo" {
 		t.Fatalf("want %q but %q", "foo", list.Title)
 	}
+	if list.RepliesPolicy != RepliesPolicyList || list.Exclusive {
+		t.Fatalf("want %q but %q", RepliesPolicyList, list.RepliesPolicy)
+	}
+	exclusive := true
+	list, err = client.CreateListWithOptions(context.Background(), "foo", &ListOptions{
+		RepliesPolicy: RepliesPolicyNone,
+		Exclusive:     &exclusive,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if list.RepliesPolicy != RepliesPolicyNone || !list.Exclusive {
+		t.Fatalf("want %q but %q", RepliesPolicyNone, list.RepliesPolicy)
+	}
 }
 
 func TestRenameList(t *testing.T) {
@@ -177,7 +237,11 @@ func TestRenameList(t *testing.T) {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
-		fmt.Fprintln(w, `{"id": "1", "title": "bar"}`)
+		if _, ok := r.PostForm["exclusive"]; ok && r.PostFormValue("replies_policy") != "followed" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "title": "bar", "exclusive": %s}`, strconv.FormatBool(r.PostFormValue("exclusive") == "true"))
 	}))
 	defer ts.Close()
 
@@ -198,6 +262,17 @@ func TestRenameList(t *testing.T) {
 	if list.Title != "bar" {
 		t.Fatalf("want %q but %q", "bar", list.Title)
 	}
+	exclusive := false
+	list, err = client.UpdateList(context.Background(), "1", "bar", &ListOptions{
+		RepliesPolicy: RepliesPolicyFollowed,
+		Exclusive:     &exclusive,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if list.Exclusive {
+		t.Fatalf("want %t but %t", false, list.Exclusive)
+	}
 }
 
 func TestDeleteList(t *testing.T) {
@@ -278,3 +353,39 @@ func TestRemoveFromList(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func TestAddToListChunks(t *testing.T) {
+	var chunks []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		params, _ := url.ParseQuery(string(b))
+		ids := params["account_ids[]"]
+		if len(ids) > 0 && ids[0] == "250" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		chunks = append(chunks, len(ids))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	ids := make([]ID, 250)
+	for i := range ids {
+		ids[i] = ID(strconv.Itoa(i))
+	}
+	if err := client.RemoveFromList(context.Background(), "1", ids...); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if fmt.Sprint(chunks) != "[100 100 50]" {
+		t.Fatalf("want %q but %q", "[100 100 50]", fmt.Sprint(chunks))
+	}
+
+	chunks = nil
+	ids = append(ids[:200], "250")
+	if err := client.AddToList(context.Background(), "1", ids...); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if fmt.Sprint(chunks) != "[100 100]" {
+		t.Fatalf("want %q but %q", "[100 100]", fmt.Sprint(chunks))
+	}
+}
@@ -0,0 +1,296 @@
+package mastodon
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// IndexDocument is a status stored in a LocalIndex.
+type IndexDocument struct {
+	Status     *Status
+	Bookmarked bool
+	Favourited bool
+}
+
+// IndexCursor records how far the history of a source of a LocalIndex was
+// synced.
+type IndexCursor struct {
+	// MaxID is where to resume fetching older statuses.
+	MaxID ID
+	// Complete reports whether the whole history was synced.
+	Complete bool
+}
+
+// IndexStore stores the documents of a LocalIndex and searches them.
+// Implement it on top of a full-text search engine to persist the index;
+// MemoryIndexStore is the default.
+type IndexStore interface {
+	// Put stores doc, replacing the document of the same status.
+	Put(doc *IndexDocument) error
+	// Get returns the document of the status id, or nil if absent.
+	Get(id ID) (*IndexDocument, error)
+	// Search returns the documents matching all the terms, which are
+	// lower case words as returned by IndexTerms.
+	Search(terms []string) ([]*IndexDocument, error)
+	// LoadCursor returns the cursor of a source, or nil if absent.
+	LoadCursor(source string) (*IndexCursor, error)
+	// SaveCursor stores the cursor of a source.
+	SaveCursor(source string, cursor *IndexCursor) error
+}
+
+// MemoryIndexStore is an IndexStore keeping an inverted index in memory.
+type MemoryIndexStore struct {
+	mu      sync.Mutex
+	docs    map[ID]*IndexDocument
+	terms   map[string]map[ID]bool
+	cursors map[string]*IndexCursor
+}
+
+// Put implements IndexStore.
+func (s *MemoryIndexStore) Put(doc *IndexDocument) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.docs == nil {
+		s.docs = map[ID]*IndexDocument{}
+		s.terms = map[string]map[ID]bool{}
+	}
+	id := doc.Status.ID
+	if old, ok := s.docs[id]; ok {
+		for _, t := range IndexTerms(indexText(old.Status)) {
+			delete(s.terms[t], id)
+		}
+	}
+	d := *doc
+	s.docs[id] = &d
+	for _, t := range IndexTerms(indexText(doc.Status)) {
+		if s.terms[t] == nil {
+			s.terms[t] = map[ID]bool{}
+		}
+		s.terms[t][id] = true
+	}
+	return nil
+}
+
+// Get implements IndexStore.
+func (s *MemoryIndexStore) Get(id ID) (*IndexDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, nil
+	}
+	d := *doc
+	return &d, nil
+}
+
+// Search implements IndexStore.
+func (s *MemoryIndexStore) Search(terms []string) ([]*IndexDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	var r []*IndexDocument
+	for id := range s.terms[terms[0]] {
+		match := true
+		for _, t := range terms[1:] {
+			if !s.terms[t][id] {
+				match = false
+				break
+			}
+		}
+		if match {
+			d := *s.docs[id]
+			r = append(r, &d)
+		}
+	}
+	return r, nil
+}
+
+// LoadCursor implements IndexStore.
+func (s *MemoryIndexStore) LoadCursor(source string) (*IndexCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cursors[source]
+	if !ok {
+		return nil, nil
+	}
+	cursor := *c
+	return &cursor, nil
+}
+
+// SaveCursor implements IndexStore.
+func (s *MemoryIndexStore) SaveCursor(source string, cursor *IndexCursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursors == nil {
+		s.cursors = map[string]*IndexCursor{}
+	}
+	c := *cursor
+	s.cursors[source] = &c
+	return nil
+}
+
+// IndexTerms splits text into the lower case words indexed by a LocalIndex.
+func IndexTerms(text string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, t := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// indexText returns the searchable text of a status: its content, content
+// warning, author, tags and media descriptions.
+func indexText(s *Status) string {
+	parts := []string{statusText(s.Content), s.SpoilerText, s.Account.Acct, s.Account.DisplayName}
+	for _, t := range s.Tags {
+		parts = append(parts, t.Name)
+	}
+	for _, a := range s.MediaAttachments {
+		parts = append(parts, a.Description)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Sources of a LocalIndex.
+const (
+	IndexSourceBookmarks  = "bookmarks"
+	IndexSourceFavourites = "favourites"
+)
+
+// LocalIndex keeps the bookmarks and favourites of the current user in a
+// local full-text index, for offline search of old statuses the server
+// search can't find.
+type LocalIndex struct {
+	Client *Client
+	// Store defaults to a MemoryIndexStore.
+	Store IndexStore
+	// Interval spaces the bookmark and favourite pages read by a sync.
+	// Zero means one second and negative values disable it.
+	Interval time.Duration
+
+	once sync.Once
+}
+
+func (x *LocalIndex) store() IndexStore {
+	x.once.Do(func() {
+		if x.Store == nil {
+			x.Store = &MemoryIndexStore{}
+		}
+	})
+	return x.Store
+}
+
+// Sync fetches the bookmarks and favourites added since the last sync and
+// returns the number of indexed statuses. The first sync fetches the whole
+// history; an interrupted sync resumes where it stopped.
+func (x *LocalIndex) Sync(ctx context.Context) (int, error) {
+	n, err := x.sync(ctx, IndexSourceBookmarks, x.Client.GetBookmarks)
+	if err != nil {
+		return n, err
+	}
+	m, err := x.sync(ctx, IndexSourceFavourites, x.Client.GetFavourites)
+	return n + m, err
+}
+
+func (x *LocalIndex) sync(ctx context.Context, source string, fetch func(context.Context, *Pagination) ([]*Status, error)) (int, error) {
+	interval := pageInterval(x.Interval)
+	store := x.store()
+	cursor, err := store.LoadCursor(source)
+	if err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	// index stores the statuses of a page and reports whether they were
+	// all new.
+	index := func(statuses []*Status) (bool, error) {
+		allNew := true
+		for _, s := range statuses {
+			doc, err := store.Get(s.ID)
+			if err != nil {
+				return false, err
+			}
+			if doc == nil {
+				doc = &IndexDocument{}
+			}
+			known := doc.Bookmarked && source == IndexSourceBookmarks || doc.Favourited && source == IndexSourceFavourites
+			if known {
+				allNew = false
+				continue
+			}
+			doc.Status = s
+			if source == IndexSourceBookmarks {
+				doc.Bookmarked = true
+			} else {
+				doc.Favourited = true
+			}
+			if err := store.Put(doc); err != nil {
+				return false, err
+			}
+			indexed++
+		}
+		return allNew && len(statuses) > 0, nil
+	}
+
+	// Newest statuses first, until a known one.
+	backfill := cursor == nil
+	err = walkPages(ctx, interval, &Pagination{Limit: 40}, func(pg *Pagination) (bool, error) {
+		statuses, err := fetch(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		more, err := index(statuses)
+		if err != nil {
+			return false, err
+		}
+		if backfill {
+			if err := store.SaveCursor(source, &IndexCursor{MaxID: pg.MaxID, Complete: !more || pg.MaxID == ""}); err != nil {
+				return false, err
+			}
+		}
+		return more, nil
+	})
+	if err != nil || backfill || cursor.Complete {
+		return indexed, err
+	}
+
+	// Then the rest of an interrupted history.
+	err = walkPages(ctx, interval, &Pagination{MaxID: cursor.MaxID, Limit: 40}, func(pg *Pagination) (bool, error) {
+		statuses, err := fetch(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		if _, err := index(statuses); err != nil {
+			return false, err
+		}
+		more := len(statuses) > 0
+		if err := store.SaveCursor(source, &IndexCursor{MaxID: pg.MaxID, Complete: !more || pg.MaxID == ""}); err != nil {
+			return false, err
+		}
+		return more, nil
+	})
+	return indexed, err
+}
+
+// Search returns the indexed statuses containing all the words of query,
+// newest first.
+func (x *LocalIndex) Search(query string) ([]*IndexDocument, error) {
+	docs, err := x.store().Search(IndexTerms(query))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Status.CreatedAt.After(docs[j].Status.CreatedAt) })
+	return docs, nil
+}
@@ -0,0 +1,109 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLocalIndex(t *testing.T) {
+	bookmarks := []string{
+		`{"id": "3", "created_at": "2024-05-03T00:00:00Z", "content": "<p>Go generics explained</p>", "account": {"acct": "foo"}}`,
+		`{"id": "2", "created_at": "2024-05-02T00:00:00Z", "content": "<p>Rust &amp; Go</p>", "account": {"acct": "bar"}}`,
+		`{"id": "1", "created_at": "2024-05-01T00:00:00Z", "content": "<p>cats</p>", "account": {"acct": "foo"}, "media_attachments": [{"description": "A sleeping Gopher"}]}`,
+	}
+	failAt := ""
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/api/v1/bookmarks":
+			maxID := r.URL.Query().Get("max_id")
+			if maxID == failAt && failAt != "" {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			switch maxID {
+			case "":
+				w.Header().Set("Link", `<http://example.com/api/v1/bookmarks?max_id=b2>; rel="next"`)
+				fmt.Fprintf(w, "[%s, %s]", bookmarks[0], bookmarks[1])
+			case "b2":
+				w.Header().Set("Link", `<http://example.com/api/v1/bookmarks?max_id=b1>; rel="next"`)
+				fmt.Fprintf(w, "[%s]", bookmarks[2])
+			default:
+				fmt.Fprintln(w, `[]`)
+			}
+		case "/api/v1/favourites":
+			fmt.Fprintf(w, "[%s]", bookmarks[1])
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	x := &LocalIndex{
+		Client:   NewClient(&Config{Server: ts.URL, AccessToken: "zoo"}),
+		Interval: -1,
+	}
+	x.Client.RetryPolicy = &RetryPolicy{}
+
+	// The first sync is interrupted after the first page.
+	failAt = "b2"
+	n, err := x.Sync(context.Background())
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("result should be two: %d", n)
+	}
+	failAt = ""
+	n, err = x.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("want one bookmark and one favourite but %d", n)
+	}
+
+	docs, err := x.Search("go")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	var ids []ID
+	for _, d := range docs {
+		ids = append(ids, d.Status.ID)
+	}
+	if !reflect.DeepEqual(ids, []ID{"3", "2"}) {
+		t.Fatalf("want %v but %v", []ID{"3", "2"}, ids)
+	}
+	if !docs[1].Bookmarked || !docs[1].Favourited || docs[0].Favourited {
+		t.Fatalf("unexpected sources: %+v %+v", docs[0], docs[1])
+	}
+	if docs, _ := x.Search("Gopher foo"); len(docs) != 1 || docs[0].Status.ID != "1" {
+		t.Fatalf("want status 1 but %v", docs)
+	}
+	if docs, _ := x.Search("go cats"); len(docs) != 0 {
+		t.Fatalf("result should be empty: %v", docs)
+	}
+
+	// Nothing new: a single request per source.
+	requests = 0
+	n, err = x.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if n != 0 || requests != 2 {
+		t.Fatalf("want no new status with 2 requests but %d with %d", n, requests)
+	}
+}
+
+func TestIndexTerms(t *testing.T) {
+	got := IndexTerms("Hello, World! hello #Go_lang café")
+	want := []string{"hello", "world", "go", "lang", "café"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %q but %q", want, got)
+	}
+}
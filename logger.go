@@ -0,0 +1,18 @@
+package mastodon
+
+import (
+	"context"
+)
+
+// Logger receives the debug logs of a Client: request and response
+// summaries, retries and streaming reconnects. *slog.Logger implements it.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+}
+
+func (c *Client) logDebug(ctx context.Context, msg string, args ...interface{}) {
+	if c.Config == nil || c.Config.Logger == nil {
+		return
+	}
+	c.Config.Logger.DebugContext(ctx, msg, args...)
+}
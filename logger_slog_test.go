@@ -0,0 +1,32 @@
+//go:build go1.21
+// +build go1.21
+
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo", Logger: logger})
+	if _, err := client.GetAccount(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !strings.Contains(buf.String(), `msg="mastodon: response" method=GET path=/api/v1/accounts/1 status=200`) {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordLogger struct {
+	msgs []string
+}
+
+func (l *recordLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.msgs = append(l.msgs, strings.TrimSpace(fmt.Sprintln(append([]interface{}{msg}, args[:6]...)...)))
+}
+
+func TestLogger(t *testing.T) {
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo", Logger: logger})
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}
+	if _, err := client.GetAccount(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := []string{
+		"mastodon: response method GET path /api/v1/accounts/1 status 429",
+		"mastodon: retrying request method GET path /api/v1/accounts/1 status 429",
+		"mastodon: response method GET path /api/v1/accounts/1 status 200",
+	}
+	if fmt.Sprint(logger.msgs) != fmt.Sprint(want) {
+		t.Fatalf("want %q but %q", want, logger.msgs)
+	}
+}
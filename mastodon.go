@@ -7,10 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tomnomnom/linkheader"
@@ -22,6 +24,11 @@ type Config struct {
 	ClientID     string
 	ClientSecret string
 	AccessToken  string
+	// StreamingServer is the base URL of the streaming API. When empty, it
+	// is discovered from the instance each time a stream is opened.
+	StreamingServer string
+	// Logger receives debug logs when set, for example a *slog.Logger.
+	Logger Logger
 }
 
 // Client is a API client for mastodon.
@@ -29,6 +36,39 @@ type Client struct {
 	http.Client
 	Config    *Config
 	UserAgent string
+	// RetryPolicy defaults to DefaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// Middlewares wrap every request sent by the client; see Use.
+	Middlewares []Middleware
+	// ResponseCache, when set, keeps the responses of the instance, custom
+	// emojis and account profiles to revalidate them with conditional
+	// requests, which the server answers without a body if unchanged.
+	ResponseCache ResponseCache
+	// StreamIdleTimeout reconnects HTTP streams which received nothing, not
+	// even a heartbeat, for that long. Mastodon sends heartbeats every 15
+	// seconds. Zero means 1 minute; negative disables it.
+	StreamIdleTimeout time.Duration
+	// LowBandwidth is for metered or slow connections: pages are smaller,
+	// instance data is cached for hours, helpers don't fetch media
+	// and AvatarURL returns static avatars.
+	LowBandwidth bool
+	// ExtraFields keeps the members of the JSON objects of responses
+	// without a field in the Extra field of Status, Account, Notification,
+	// Instance and InstanceV2, like the extensions of other servers.
+	// Decoding is slower.
+	ExtraFields bool
+
+	rateLimitMu sync.Mutex
+	rateLimit   *RateLimit
+	rateLimits  map[string]*RateLimit
+
+	cacheMu    sync.Mutex
+	cache      map[string]*instanceCacheEntry
+	softwareMu sync.Mutex
+	software   string
+
+	streamingMu  sync.Mutex
+	streamingURL string
 }
 
 func (c *Client) doAPI(ctx context.Context, method string, uri string, params interface{}, res interface{}, pg *Pagination) error {
@@ -38,13 +78,20 @@ func (c *Client) doAPI(ctx context.Context, method string, uri string, params in
 	}
 	u.Path = path.Join(u.Path, uri)
 
+	cached := c.LowBandwidth && method == http.MethodGet && params == nil && pg == nil && lowBandwidthCached(uri)
+	if cached {
+		if body, ok := c.cachedBody(tokenCacheKey(ctx, uri), time.Now()); ok {
+			return c.decode(body, res)
+		}
+	}
+
 	var req *http.Request
 	ct := "application/x-www-form-urlencoded"
 	if values, ok := params.(url.Values); ok {
 		var body io.Reader
 		if method == http.MethodGet {
 			if pg != nil {
-				values = pg.setValues(values)
+				values = c.pagination(pg).setValues(values)
 			}
 			u.RawQuery = values.Encode()
 		} else {
@@ -54,8 +101,8 @@ func (c *Client) doAPI(ctx context.Context, method string, uri string, params in
 		if err != nil {
 			return err
 		}
-	} else if media, ok := params.(*Media); ok {
-		r, contentType, err := media.bodyAndContentType()
+	} else if form, ok := params.(multipartForm); ok {
+		r, contentType, err := form.bodyAndContentType()
 		if err != nil {
 			return err
 		}
@@ -64,11 +111,14 @@ func (c *Client) doAPI(ctx context.Context, method string, uri string, params in
 		if err != nil {
 			return err
 		}
+		if media, ok := params.(*Media); ok && media.Progress != nil {
+			trackProgress(req, media.Progress)
+		}
 
 		ct = contentType
 	} else {
 		if method == http.MethodGet && pg != nil {
-			u.RawQuery = pg.toValues().Encode()
+			u.RawQuery = c.pagination(pg).toValues().Encode()
 		}
 		req, err = http.NewRequest(method, u.String(), nil)
 		if err != nil {
@@ -76,43 +126,76 @@ func (c *Client) doAPI(ctx context.Context, method string, uri string, params in
 		}
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.Config.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+c.accessToken(ctx))
 	if params != nil {
 		req.Header.Set("Content-Type", ct)
 	}
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
+	setContextHeaders(ctx, req.Header)
+
+	var conditional *CachedResponse
+	revalidate := c.ResponseCache != nil && method == http.MethodGet && conditionalCached(uri)
+	if revalidate {
+		conditional = c.ResponseCache.Get(tokenCacheKey(ctx, req.URL.String()))
+		conditional.setHeaders(req.Header)
+	}
 
 	var resp *http.Response
-	backoff := time.Second
-	for {
+	policy := c.retryPolicy()
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
 		resp, err = c.Do(req)
 		if err != nil {
+			c.logDebug(ctx, "mastodon: request failed", "method", method, "path", u.Path, "attempt", attempt, "error", err)
 			return err
 		}
 		defer resp.Body.Close()
-
-		// handle status code 429, which indicates the server is throttling
-		// our requests. Do an exponential backoff and retry the request.
-		if resp.StatusCode == 429 {
-			if backoff > time.Hour {
+		c.updateRateLimit(ctx, resp.Header)
+		c.logDebug(ctx, "mastodon: response", "method", method, "path", u.Path, "status", resp.StatusCode, "duration", time.Since(start), "attempt", attempt)
+
+		// Retry throttled requests and idempotent requests failing on an
+		// unavailable server, waiting as long as the server asks or with an
+		// exponential backoff.
+		if attempt >= policy.MaxAttempts {
+			break
+		}
+		wait, ok := policy.delay(req, resp, backoff)
+		if !ok {
+			break
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
 				break
 			}
-
-			select {
-			case <-time.After(backoff):
-			case <-ctx.Done():
-				return ctx.Err()
+			body, err := req.GetBody()
+			if err != nil {
+				return err
 			}
+			req.Body = body
+		}
+		resp.Body.Close()
+		c.logDebug(ctx, "mastodon: retrying request", "method", method, "path", u.Path, "status", resp.StatusCode, "wait", wait, "attempt", attempt)
 
-			backoff = time.Duration(1.5 * float64(backoff))
-			continue
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-		break
+		backoff = policy.nextBackoff(backoff)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusNotModified && conditional != nil {
+		if res == nil {
+			return nil
+		}
+		if cached {
+			c.cacheBody(tokenCacheKey(ctx, uri), conditional.Body, time.Now())
+		}
+		return c.decode(conditional.Body, res)
+	} else if resp.StatusCode != http.StatusOK {
 		return parseAPIError("bad request", resp)
 	} else if res == nil {
 		return nil
@@ -122,18 +205,42 @@ func (c *Client) doAPI(ctx context.Context, method string, uri string, params in
 			if err != nil {
 				return err
 			}
+			pg2.Limit = pg.Limit
 			*pg = *pg2
+		} else {
+			// No Link header means there are no other pages.
+			*pg = Pagination{Limit: pg.Limit}
+		}
+	}
+	if cached || revalidate || c.ExtraFields {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if err := c.decode(body, res); err != nil {
+			return err
+		}
+		if cached {
+			c.cacheBody(tokenCacheKey(ctx, uri), body, time.Now())
 		}
+		if revalidate {
+			c.ResponseCache.Set(tokenCacheKey(ctx, req.URL.String()), newCachedResponse(resp.Header, body))
+		}
+		return nil
 	}
 	return json.NewDecoder(resp.Body).Decode(&res)
 }
 
-// NewClient returns a new mastodon API client.
-func NewClient(config *Config) *Client {
-	return &Client{
+// NewClient returns a new mastodon API client configured with opts.
+func NewClient(config *Config, opts ...Option) *Client {
+	c := &Client{
 		Client: *http.DefaultClient,
 		Config: config,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Authenticate gets access-token to the API.
@@ -152,12 +259,24 @@ func (c *Client) Authenticate(ctx context.Context, username, password string) er
 
 // AuthenticateApp logs in using client credentials.
 func (c *Client) AuthenticateApp(ctx context.Context) error {
+	return c.AuthenticateAppScopes(ctx)
+}
+
+// AuthenticateAppScopes logs in using client credentials, requesting an
+// app token with scopes, which must be a subset of the scopes the
+// application was registered with. The server grants "read" when scopes is
+// empty. Creating accounts with the API, for example, needs
+// ScopeWriteAccounts.
+func (c *Client) AuthenticateAppScopes(ctx context.Context, scopes ...string) error {
 	params := url.Values{
 		"client_id":     {c.Config.ClientID},
 		"client_secret": {c.Config.ClientSecret},
 		"grant_type":    {"client_credentials"},
 		"redirect_uri":  {"urn:ietf:wg:oauth:2.0:oob"},
 	}
+	if len(scopes) > 0 {
+		params.Set("scope", JoinScopes(scopes...))
+	}
 
 	return c.authenticate(ctx, params)
 }
@@ -177,6 +296,22 @@ func (c *Client) AuthenticateToken(ctx context.Context, authCode, redirectURI st
 	return c.authenticate(ctx, params)
 }
 
+// AuthenticateTokenPKCE logs in using a grant token returned by an
+// authorization URL with the code challenge of PKCE. codeVerifier is the
+// PKCE.Verifier the challenge was made from.
+func (c *Client) AuthenticateTokenPKCE(ctx context.Context, authCode, redirectURI, codeVerifier string) error {
+	params := url.Values{
+		"client_id":     {c.Config.ClientID},
+		"client_secret": {c.Config.ClientSecret},
+		"grant_type":    {"authorization_code"},
+		"code":          {authCode},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+
+	return c.authenticate(ctx, params)
+}
+
 func (c *Client) authenticate(ctx context.Context, params url.Values) error {
 	u, err := url.Parse(c.Config.Server)
 	if err != nil {
@@ -214,14 +349,6 @@ func (c *Client) authenticate(ctx context.Context, params url.Values) error {
 	return nil
 }
 
-// Convenience constants for Toot.Visibility
-const (
-	VisibilityPublic        = "public"
-	VisibilityUnlisted      = "unlisted"
-	VisibilityFollowersOnly = "private"
-	VisibilityDirectMessage = "direct"
-)
-
 // Toot is a struct to post status.
 type Toot struct {
 	Status      string     `json:"status"`
@@ -229,10 +356,24 @@ type Toot struct {
 	MediaIDs    []ID       `json:"media_ids"`
 	Sensitive   bool       `json:"sensitive"`
 	SpoilerText string     `json:"spoiler_text"`
-	Visibility  string     `json:"visibility"`
+	Visibility  Visibility `json:"visibility"`
+	// Language is an ISO 639 code, like "en", or a regional locale, like
+	// "pt-BR"; see ValidLanguage.
 	Language    string     `json:"language"`
 	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
 	Poll        *TootPoll  `json:"poll"`
+
+	// MediaAttributes updates the description and focus of attached media
+	// when editing a status with UpdateStatus.
+	MediaAttributes []TootMediaAttribute `json:"media_attributes,omitempty"`
+}
+
+// TootMediaAttribute holds the attributes of media attached to an edited
+// status.
+type TootMediaAttribute struct {
+	ID          ID     `json:"id"`
+	Description string `json:"description"`
+	Focus       string `json:"focus,omitempty"`
 }
 
 // TootPoll holds information for creating a poll in Toot.
@@ -261,13 +402,14 @@ type History struct {
 // Attachment hold information for attachment.
 type Attachment struct {
 	ID          ID             `json:"id"`
-	Type        string         `json:"type"`
+	Type        AttachmentType `json:"type"`
 	URL         string         `json:"url"`
 	RemoteURL   string         `json:"remote_url"`
 	PreviewURL  string         `json:"preview_url"`
 	TextURL     string         `json:"text_url"`
 	Description string         `json:"description"`
 	Meta        AttachmentMeta `json:"meta"`
+	Blurhash    string         `json:"blurhash"`
 }
 
 // AttachmentMeta holds information for attachment metadata.
@@ -290,6 +432,7 @@ type Emoji struct {
 	StaticURL       string `json:"static_url"`
 	URL             string `json:"url"`
 	VisibleInPicker bool   `json:"visible_in_picker"`
+	Category        string `json:"category"`
 }
 
 // Results hold information for search result.
@@ -300,6 +443,11 @@ type Results struct {
 }
 
 // Pagination is a struct for specifying the get range.
+//
+// Methods taking a *Pagination replace its IDs with the cursors of the
+// Link header of the response: MaxID points to the next, older page and
+// SinceID or MinID to the previous, newer page. IDs are empty when there is
+// no such page. Limit is kept.
 type Pagination struct {
 	MaxID   ID
 	SinceID ID
@@ -307,6 +455,30 @@ type Pagination struct {
 	Limit   int64
 }
 
+// HasNext reports whether there is an older page.
+func (p *Pagination) HasNext() bool {
+	return p.MaxID != ""
+}
+
+// HasPrev reports whether there is a newer page.
+func (p *Pagination) HasPrev() bool {
+	return p.MinID != "" || p.SinceID != ""
+}
+
+// Next returns the pagination of the older page.
+func (p *Pagination) Next() *Pagination {
+	return &Pagination{MaxID: p.MaxID, Limit: p.Limit}
+}
+
+// Prev returns the pagination of the newer page, right after the current
+// one.
+func (p *Pagination) Prev() *Pagination {
+	if p.MinID != "" {
+		return &Pagination{MinID: p.MinID, Limit: p.Limit}
+	}
+	return &Pagination{SinceID: p.SinceID, Limit: p.Limit}
+}
+
 func newPagination(rawlink string) (*Pagination, error) {
 	if rawlink == "" {
 		return nil, errors.New("empty link header")
@@ -76,6 +76,10 @@ func TestDoAPI(t *testing.T) {
 		t.Fatalf("want %q but %q", "bar", accounts[1].Username)
 	}
 
+	if pg.Limit != 10 {
+		t.Fatalf("want %d but %d", 10, pg.Limit)
+	}
+
 	// *Pagination is nil
 	err = c.doAPI(context.Background(), http.MethodGet, "/", nil, &accounts, nil)
 	if err != nil {
@@ -89,6 +93,43 @@ func TestDoAPI(t *testing.T) {
 	}
 }
 
+func TestDoAPIWithoutLink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"username": "foo"}]`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(&Config{Server: ts.URL})
+	var accounts []Account
+	pg := &Pagination{MaxID: "123", Limit: 10}
+	err := c.doAPI(context.Background(), http.MethodGet, "/", nil, &accounts, pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if pg.HasNext() || pg.HasPrev() {
+		t.Fatalf("should not have other pages: %+v", pg)
+	}
+	if pg.Limit != 10 {
+		t.Fatalf("want %d but %d", 10, pg.Limit)
+	}
+}
+
+func TestPaginationNextPrev(t *testing.T) {
+	pg := &Pagination{MaxID: "1", MinID: "5", SinceID: "4", Limit: 20}
+	next := pg.Next()
+	if next.MaxID != "1" || next.MinID != "" || next.SinceID != "" || next.Limit != 20 {
+		t.Fatalf("unexpected next page: %+v", next)
+	}
+	prev := pg.Prev()
+	if prev.MinID != "5" || prev.MaxID != "" || prev.SinceID != "" || prev.Limit != 20 {
+		t.Fatalf("unexpected prev page: %+v", prev)
+	}
+	pg = &Pagination{SinceID: "4"}
+	if prev := pg.Prev(); prev.SinceID != "4" {
+		t.Fatalf("want %q but %q", "4", prev.SinceID)
+	}
+}
+
 func TestAuthenticate(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.FormValue("username") != "valid" || r.FormValue("password") != "user" {
@@ -173,6 +214,34 @@ func TestAuthenticateApp(t *testing.T) {
 	}
 }
 
+func TestAuthenticateAppScopes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "read write:accounts" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"access_token": "app-token", "token_type": "Bearer", "scope": "read write:accounts"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:       ts.URL,
+		ClientID:     "foo",
+		ClientSecret: "bar",
+	})
+	err := client.AuthenticateAppScopes(context.Background(), ScopeRead)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AuthenticateAppScopes(context.Background(), ScopeRead, ScopeWriteAccounts)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if client.Config.AccessToken != "app-token" {
+		t.Fatalf("want %q but %q", "app-token", client.Config.AccessToken)
+	}
+}
+
 func TestPostStatus(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer zoo" {
@@ -207,6 +276,16 @@ func TestPostStatus(t *testing.T) {
 	if err != nil {
 		t.Fatalf("should not be fail: %v", err)
 	}
+	// The server validates visibilities and languages, which may be
+	// extensions like Pleroma's local visibility.
+	_, err = client.PostStatus(context.Background(), &Toot{
+		Status:     "foobar",
+		Visibility: "local",
+		Language:   "pt-BR",
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
 }
 
 func TestPostStatusWithCancel(t *testing.T) {
@@ -250,7 +329,7 @@ func TestPostStatusParams(t *testing.T) {
 			s.InReplyToID = ID(r.FormValue("in_reply_to_id"))
 		}
 		if r.FormValue("visibility") != "" {
-			s.Visibility = (r.FormValue("visibility"))
+			s.Visibility = Visibility(r.FormValue("visibility"))
 		}
 		if r.FormValue("language") != "" {
 			s.Language = (r.FormValue("language"))
@@ -276,6 +355,10 @@ func TestPostStatusParams(t *testing.T) {
 			if r.FormValue("poll[multiple]") == "true" {
 				p.Multiple = true
 			}
+			if r.FormValue("poll[expires_in]") != "3600" || r.FormValue("poll[hide_totals]") != "true" {
+				http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+				return
+			}
 			s.Poll = &p
 		}
 		json.NewEncoder(w).Encode(s)
@@ -326,9 +409,10 @@ func TestPostStatusParams(t *testing.T) {
 	s, err = client.PostStatus(context.Background(), &Toot{
 		Status: "foobar",
 		Poll: &TootPoll{
-			Multiple:   true,
-			Options:    []string{"A", "B"},
-			HideTotals: true,
+			Multiple:         true,
+			Options:          []string{"A", "B"},
+			ExpiresInSeconds: 3600,
+			HideTotals:       true,
 		},
 	})
 	if err != nil {
@@ -428,7 +512,7 @@ func TestUpdateStatusParams(t *testing.T) {
 			s.InReplyToID = ID(r.FormValue("in_reply_to_id"))
 		}
 		if r.FormValue("visibility") != "" {
-			s.Visibility = (r.FormValue("visibility"))
+			s.Visibility = Visibility(r.FormValue("visibility"))
 		}
 		if r.FormValue("language") != "" {
 			s.Language = (r.FormValue("language"))
@@ -592,7 +676,13 @@ func TestGetTimelineHomeWithCancel(t *testing.T) {
 
 func TestForTheCoverages(t *testing.T) {
 	(*UpdateEvent)(nil).event()
-	(*UpdateEditEvent)(nil).event()
+	(*StatusUpdateEvent)(nil).event()
+	(*ConnectEvent)(nil).event()
+	(*DisconnectEvent)(nil).event()
+	(*FiltersChangedEvent)(nil).event()
+	(*AnnouncementEvent)(nil).event()
+	(*AnnouncementReactionEvent)(nil).event()
+	(*AnnouncementDeleteEvent)(nil).event()
 	(*NotificationEvent)(nil).event()
 	(*ConversationEvent)(nil).event()
 	(*DeleteEvent)(nil).event()
@@ -0,0 +1,297 @@
+package mastodontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode tells a Recorder whether to record or replay interactions.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of the cassette and fails
+	// requests it has no interaction for.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to the server and records them,
+	// replacing the cassette on Save.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// Redacted replaces the secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+// secrets are the names of the parameters, form fields and JSON members
+// whose values are redacted.
+var secrets = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"client_secret": true,
+	"password":      true,
+	"code":          true,
+	"code_verifier": true,
+	"vapid_key":     true,
+}
+
+// Interaction is a request and its response, as saved in cassettes.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request. Bodies other than forms and JSON,
+// like media uploads, aren't kept.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper recording the interactions with a real
+// server to a cassette file, and replaying them in later runs, so tests
+// don't depend on a live instance. Use it as the transport of a client:
+//
+//	rec, err := mastodontest.NewRecorder("testdata/home.json", mastodontest.ModeAuto)
+//	client := mastodon.NewClient(config, mastodon.WithTransport(rec))
+//	defer rec.Save()
+//
+// Access tokens, client secrets, passwords and authorization codes are
+// redacted from cassettes, and cookies are dropped. Streams can't be
+// recorded.
+type Recorder struct {
+	// Path is the path of the cassette file.
+	Path string
+	Mode Mode
+	// Transport sends the requests when recording; nil means
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// Redact, if set, is called on each interaction before it is recorded
+	// to remove other secrets.
+	Redact func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// NewRecorder returns a recorder of the cassette at path. In ModeAuto the
+// mode becomes ModeReplay if the cassette exists and ModeRecord otherwise.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+	if mode == ModeAuto {
+		r.Mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.Mode = ModeReplay
+		}
+	}
+	if r.Mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, fmt.Errorf("mastodontest: invalid cassette %s: %v", path, err)
+		}
+		r.replayed = make([]bool, len(r.interactions))
+	}
+	return r, nil
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.Mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	// The body may change in length when redacted.
+	header.Del("Content-Length")
+	i := &Interaction{
+		Request: *recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       redactJSON(string(body)),
+		},
+	}
+	if r.Redact != nil {
+		r.Redact(i)
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replay returns the response of the first interaction matching the
+// request which wasn't replayed yet.
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, i := range r.interactions {
+		if r.replayed[n] || i.Request.Method != recorded.Method || i.Request.URL != recorded.URL || i.Request.Body != recorded.Body {
+			continue
+		}
+		r.replayed[n] = true
+		header := i.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("mastodontest: no recorded interaction for %s %s in %s", recorded.Method, recorded.URL, r.Path)
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing
+// when replaying.
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interactions := r.interactions
+	if interactions == nil {
+		interactions = []*Interaction{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(interactions); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.Path, buf.Bytes(), 0644)
+}
+
+// recordRequest returns the request as recorded, with its secrets
+// redacted. The body of req is restored.
+func recordRequest(req *http.Request) (*RecordedRequest, error) {
+	u := *req.URL
+	u.RawQuery = redactValues(u.Query()).Encode()
+	recorded := &RecordedRequest{Method: req.Method, URL: u.String()}
+
+	header := req.Header.Clone()
+	header.Del("Cookie")
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", "Bearer "+Redacted)
+	}
+	if len(header) > 0 {
+		recorded.Header = header
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	switch ct := req.Header.Get("Content-Type"); {
+	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		recorded.Body = redactValues(values).Encode()
+	case strings.HasPrefix(ct, "application/json"):
+		recorded.Body = redactJSON(string(body))
+	}
+	return recorded, nil
+}
+
+func redactValues(values url.Values) url.Values {
+	for k := range values {
+		if secrets[k] {
+			values[k] = []string{Redacted}
+		}
+	}
+	return values
+}
+
+// redactJSON redacts the secret members of the JSON objects of body. Other
+// bodies are returned unchanged.
+func redactJSON(body string) string {
+	var v interface{}
+	if json.Unmarshal([]byte(body), &v) != nil || !redactValue(v) {
+		return body
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(b)
+}
+
+// redactValue redacts the secret members of the objects of v and reports
+// whether it found any.
+func redactValue(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if secrets[k] {
+				v[k] = Redacted
+				found = true
+			} else if redactValue(value) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactValue(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
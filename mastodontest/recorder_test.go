@@ -0,0 +1,137 @@
+package mastodontest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+func TestRecorder(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	s := NewServer()
+	s.AddStatus(&mastodon.Status{Content: "hello"})
+	ctx := context.Background()
+	config := &mastodon.Config{Server: s.URL, AccessToken: AccessToken}
+
+	rec, err := NewRecorder(cassette, ModeAuto)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rec.Mode != ModeRecord {
+		t.Fatalf("want %v but %v", ModeRecord, rec.Mode)
+	}
+	client := mastodon.NewClient(config, mastodon.WithTransport(rec))
+	if _, err := client.PostStatus(ctx, &mastodon.Toot{Status: "again"}); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	recorded, err := client.GetTimelineHome(ctx, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	s.Close()
+
+	b, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if strings.Contains(string(b), AccessToken) {
+		t.Fatalf("cassette should not contain the access token: %s", b)
+	}
+
+	rec, err = NewRecorder(cassette, ModeAuto)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rec.Mode != ModeReplay {
+		t.Fatalf("want %v but %v", ModeReplay, rec.Mode)
+	}
+	client = mastodon.NewClient(config, mastodon.WithTransport(rec))
+	if _, err := client.PostStatus(ctx, &mastodon.Toot{Status: "again"}); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	replayed, err := client.GetTimelineHome(ctx, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].ID != recorded[0].ID || replayed[1].Content != "hello" {
+		t.Fatalf("unexpected replayed timeline: %v", replayed)
+	}
+	if _, err := client.GetTimelineHome(ctx, nil); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if _, err := client.PostStatus(ctx, &mastodon.Toot{Status: "other"}); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestRecorderRedaction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "_session_id", Value: "cookie"})
+		fmt.Fprintln(w, `{"access_token": "secret-token", "token_type": "Bearer", "scope": "read"}`)
+	}))
+	defer ts.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := NewRecorder(cassette, ModeRecord)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	rec.Redact = func(i *Interaction) {
+		i.Response.Header.Del("Date")
+	}
+	client := mastodon.NewClient(&mastodon.Config{
+		Server:       ts.URL,
+		ClientID:     "foo",
+		ClientSecret: "secret-client",
+	}, mastodon.WithTransport(rec))
+	if err := client.AuthenticateToken(context.Background(), "secret-code", "urn:ietf:wg:oauth:2.0:oob"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if client.Config.AccessToken != "secret-token" {
+		t.Fatalf("want %q but %q", "secret-token", client.Config.AccessToken)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	for _, secret := range []string{"secret-", "cookie", "Date"} {
+		if strings.Contains(string(b), secret) {
+			t.Fatalf("cassette should not contain %q: %s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "client_id=foo") || !strings.Contains(string(b), Redacted) {
+		t.Fatalf("unexpected cassette: %s", b)
+	}
+
+	rec, err = NewRecorder(cassette, ModeReplay)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	client = mastodon.NewClient(&mastodon.Config{Server: ts.URL, ClientID: "foo", ClientSecret: "other"}, mastodon.WithTransport(rec))
+	if err := client.AuthenticateToken(context.Background(), "other-code", "urn:ietf:wg:oauth:2.0:oob"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if client.Config.AccessToken != Redacted {
+		t.Fatalf("want %q but %q", Redacted, client.Config.AccessToken)
+	}
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
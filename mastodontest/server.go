@@ -0,0 +1,427 @@
+// Package mastodontest provides an in-memory Mastodon server to test code
+// using the mastodon package without a real instance.
+//
+// The server implements the commonly used endpoints of statuses, accounts,
+// timelines and streaming over server-sent events. It has no follow graph:
+// the home timeline and the user stream hold all statuses.
+package mastodontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+// AccessToken is the access token of the clients returned by Server.Client.
+const AccessToken = "mastodontest"
+
+// Server is a fake Mastodon server holding accounts and statuses in memory.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int64
+	me       *mastodon.Account
+	accounts map[mastodon.ID]*mastodon.Account
+	statuses map[mastodon.ID]*mastodon.Status
+	streams  map[*stream]bool
+	closed   chan struct{}
+}
+
+// NewServer starts a server whose current user is @alice. Close it when
+// done.
+func NewServer() *Server {
+	s := &Server{
+		accounts: map[mastodon.ID]*mastodon.Account{},
+		statuses: map[mastodon.ID]*mastodon.Status{},
+		streams:  map[*stream]bool{},
+		closed:   make(chan struct{}),
+	}
+	s.me = s.addAccount(&mastodon.Account{Username: "alice", DisplayName: "Alice"})
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close ends the connected streams and shuts down the server.
+func (s *Server) Close() {
+	close(s.closed)
+	s.Server.Close()
+}
+
+// Client returns a client of the server authenticated as the current user.
+func (s *Server) Client() *mastodon.Client {
+	return mastodon.NewClient(&mastodon.Config{Server: s.URL, AccessToken: AccessToken})
+}
+
+// CurrentUser returns the account of the current user.
+func (s *Server) CurrentUser() *mastodon.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := *s.me
+	return &a
+}
+
+// AddAccount seeds the server with a copy of a. Its ID, Acct and CreatedAt
+// are set when empty. The stored account is returned.
+func (s *Server) AddAccount(a *mastodon.Account) *mastodon.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := *s.addAccount(a)
+	return &r
+}
+
+func (s *Server) addAccount(a *mastodon.Account) *mastodon.Account {
+	account := *a
+	if account.ID == "" {
+		account.ID = s.newID()
+	}
+	if account.Acct == "" {
+		account.Acct = account.Username
+	}
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = time.Now()
+	}
+	s.accounts[account.ID] = &account
+	return &account
+}
+
+// AddStatus seeds the server with a copy of st, posted by the current user
+// unless st.Account is set to a seeded account. Its ID, CreatedAt and
+// Visibility are set when empty. The stored status is returned and sent to
+// the streams.
+func (s *Server) AddStatus(st *mastodon.Status) *mastodon.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := *st
+	if status.ID == "" {
+		status.ID = s.newID()
+	}
+	author := s.me
+	if status.Account.ID != "" {
+		a, ok := s.accounts[status.Account.ID]
+		if !ok {
+			a = s.addAccount(&status.Account)
+		}
+		author = a
+	}
+	author.StatusesCount++
+	status.Account = *author
+	if status.CreatedAt.IsZero() {
+		status.CreatedAt = time.Now()
+	}
+	if status.Visibility == "" {
+		status.Visibility = mastodon.VisibilityPublic
+	}
+	if status.Tags == nil {
+		status.Tags = hashtags(status.Content)
+	}
+	s.statuses[status.ID] = &status
+	s.publish("update", &status, &status)
+	r := status
+	return &r
+}
+
+// Statuses returns the statuses of the server, newest first.
+func (s *Server) Statuses() []*mastodon.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var r []*mastodon.Status
+	for _, st := range s.sortedStatuses() {
+		c := *st
+		r = append(r, &c)
+	}
+	return r
+}
+
+// newID returns a new ID, greater than the previous ones.
+func (s *Server) newID() mastodon.ID {
+	s.nextID++
+	return mastodon.ID(strconv.FormatInt(s.nextID, 10))
+}
+
+func (s *Server) sortedStatuses() []*mastodon.Status {
+	r := make([]*mastodon.Status, 0, len(s.statuses))
+	for _, st := range s.statuses {
+		r = append(r, st)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[j].ID.Less(r[i].ID) })
+	return r
+}
+
+var reHashtag = regexp.MustCompile(`(?:^|[^\w/])#(\w+)`)
+
+func hashtags(content string) []mastodon.Tag {
+	var tags []mastodon.Tag
+	for _, m := range reHashtag.FindAllStringSubmatch(content, -1) {
+		tags = append(tags, mastodon.Tag{Name: strings.ToLower(m[1])})
+	}
+	return tags
+}
+
+var (
+	reAccount         = regexp.MustCompile(`^/api/v1/accounts/([^/]+)$`)
+	reAccountStatuses = regexp.MustCompile(`^/api/v1/accounts/([^/]+)/statuses$`)
+	reStatus          = regexp.MustCompile(`^/api/v1/statuses/([^/]+)$`)
+	reStatusAction    = regexp.MustCompile(`^/api/v1/statuses/([^/]+)/(favourite|unfavourite|reblog|unreblog|bookmark|unbookmark)$`)
+	reTagTimeline     = regexp.MustCompile(`^/api/v1/timelines/tag/([^/]+)$`)
+)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	if strings.HasPrefix(p, "/api/v1/streaming") {
+		s.serveStreaming(w, r)
+		return
+	}
+	if p == "/api/v1/instance" || p == "/api/v2/instance" {
+		s.serveInstance(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+AccessToken {
+		writeError(w, http.StatusUnauthorized, "The access token is invalid")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var m []string
+	switch {
+	case p == "/api/v1/accounts/verify_credentials" && r.Method == http.MethodGet:
+		writeJSON(w, s.me)
+	case match(reAccountStatuses, p, &m) && r.Method == http.MethodGet:
+		if _, ok := s.accounts[mastodon.ID(m[1])]; !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+		s.writeStatuses(w, r, func(st *mastodon.Status) bool { return st.Account.ID == mastodon.ID(m[1]) })
+	case match(reAccount, p, &m) && r.Method == http.MethodGet:
+		a, ok := s.accounts[mastodon.ID(m[1])]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+		writeJSON(w, a)
+	case p == "/api/v1/statuses" && r.Method == http.MethodPost:
+		s.postStatus(w, r)
+	case match(reStatusAction, p, &m) && r.Method == http.MethodPost:
+		s.statusAction(w, mastodon.ID(m[1]), m[2])
+	case match(reStatus, p, &m) && r.Method == http.MethodGet:
+		st, ok := s.statuses[mastodon.ID(m[1])]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+		writeJSON(w, st)
+	case match(reStatus, p, &m) && r.Method == http.MethodDelete:
+		st, ok := s.statuses[mastodon.ID(m[1])]
+		if !ok || st.Account.ID != s.me.ID {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+		delete(s.statuses, st.ID)
+		s.accounts[st.Account.ID].StatusesCount--
+		s.publish("delete", st, string(st.ID))
+		writeJSON(w, st)
+	case p == "/api/v1/timelines/home" && r.Method == http.MethodGet:
+		s.writeStatuses(w, r, func(st *mastodon.Status) bool { return true })
+	case p == "/api/v1/timelines/public" && r.Method == http.MethodGet:
+		s.writeStatuses(w, r, func(st *mastodon.Status) bool { return st.Visibility == mastodon.VisibilityPublic })
+	case match(reTagTimeline, p, &m) && r.Method == http.MethodGet:
+		tag := strings.ToLower(m[1])
+		s.writeStatuses(w, r, func(st *mastodon.Status) bool {
+			return st.Visibility == mastodon.VisibilityPublic && hasTag(st, tag)
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Record not found")
+	}
+}
+
+func match(re *regexp.Regexp, s string, m *[]string) bool {
+	*m = re.FindStringSubmatch(s)
+	return *m != nil
+}
+
+func hasTag(st *mastodon.Status, tag string) bool {
+	for _, t := range st.Tags {
+		if strings.EqualFold(t.Name, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveInstance(w http.ResponseWriter, r *http.Request) {
+	u, _ := url.Parse(s.URL)
+	if r.URL.Path == "/api/v1/instance" {
+		writeJSON(w, &mastodon.Instance{
+			URI:     u.Host,
+			Title:   "mastodontest",
+			Version: "4.2.0",
+			URLs:    map[string]string{"streaming_api": s.URL},
+		})
+		return
+	}
+	var instance mastodon.InstanceV2
+	instance.Domain = u.Host
+	instance.Title = "mastodontest"
+	instance.Version = "4.2.0"
+	instance.SourceURL = "https://github.com/mastodon/mastodon"
+	instance.Configuration.Urls.Streaming = s.URL
+	instance.Configuration.Statuses.MaxCharacters = 500
+	instance.Configuration.Statuses.MaxMediaAttachments = 4
+	instance.Configuration.Statuses.CharactersReservedPerURL = 23
+	writeJSON(w, &instance)
+}
+
+func (s *Server) postStatus(w http.ResponseWriter, r *http.Request) {
+	text := r.Form.Get("status")
+	if strings.TrimSpace(text) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation failed: Text can't be blank")
+		return
+	}
+	st := &mastodon.Status{
+		ID:          s.newID(),
+		Account:     *s.me,
+		Content:     "<p>" + text + "</p>",
+		CreatedAt:   time.Now(),
+		SpoilerText: r.Form.Get("spoiler_text"),
+		Sensitive:   r.Form.Get("sensitive") == "true",
+		Visibility:  mastodon.Visibility(r.Form.Get("visibility")),
+		Language:    r.Form.Get("language"),
+		Tags:        hashtags(text),
+	}
+	if st.Visibility == "" {
+		st.Visibility = mastodon.VisibilityPublic
+	}
+	if id := r.Form.Get("in_reply_to_id"); id != "" {
+		parent, ok := s.statuses[mastodon.ID(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+		st.InReplyToID = parent.ID
+		st.InReplyToAccountID = parent.Account.ID
+		parent.RepliesCount++
+	}
+	s.statuses[st.ID] = st
+	s.me.StatusesCount++
+	s.publish("update", st, st)
+	writeJSON(w, st)
+}
+
+func (s *Server) statusAction(w http.ResponseWriter, id mastodon.ID, action string) {
+	st, ok := s.statuses[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	switch action {
+	case "favourite", "unfavourite":
+		on := action == "favourite"
+		if on != isTrue(st.Favourited) {
+			st.Favourited = on
+			if on {
+				st.FavouritesCount++
+			} else {
+				st.FavouritesCount--
+			}
+		}
+	case "reblog", "unreblog":
+		on := action == "reblog"
+		if on != isTrue(st.Reblogged) {
+			st.Reblogged = on
+			if on {
+				st.ReblogsCount++
+			} else {
+				st.ReblogsCount--
+			}
+		}
+	case "bookmark", "unbookmark":
+		st.Bookmarked = action == "bookmark"
+	}
+	writeJSON(w, st)
+}
+
+func isTrue(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// writeStatuses writes the statuses matching keep, newest first, paginated
+// with max_id, since_id, min_id and limit like Mastodon.
+func (s *Server) writeStatuses(w http.ResponseWriter, r *http.Request, keep func(*mastodon.Status) bool) {
+	q := r.Form
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 40 {
+		limit = 40
+	}
+	maxID, sinceID, minID := mastodon.ID(q.Get("max_id")), mastodon.ID(q.Get("since_id")), mastodon.ID(q.Get("min_id"))
+
+	var matched []*mastodon.Status
+	for _, st := range s.sortedStatuses() {
+		if !keep(st) {
+			continue
+		}
+		if maxID != "" && !st.ID.Less(maxID) {
+			continue
+		}
+		if sinceID != "" && !sinceID.Less(st.ID) {
+			continue
+		}
+		if minID != "" && !minID.Less(st.ID) {
+			continue
+		}
+		matched = append(matched, st)
+	}
+	page := matched
+	if minID != "" && len(page) > limit {
+		// min_id returns the statuses right after it.
+		page = page[len(page)-limit:]
+	} else if len(page) > limit {
+		page = page[:limit]
+	}
+
+	if len(page) > 0 {
+		u := *r.URL
+		u.Scheme, u.Host = "http", r.Host
+		link := func(key string, id mastodon.ID, rel string) string {
+			v := url.Values{}
+			v.Set("limit", strconv.Itoa(limit))
+			v.Set(key, string(id))
+			u.RawQuery = v.Encode()
+			return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+		}
+		w.Header().Set("Link", link("max_id", page[len(page)-1].ID, "next")+", "+link("min_id", page[0].ID, "prev"))
+	}
+	if page == nil {
+		page = []*mastodon.Status{}
+	}
+	writeJSON(w, page)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
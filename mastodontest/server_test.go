@@ -0,0 +1,178 @@
+package mastodontest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+func TestStatuses(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	ctx := context.Background()
+
+	status, err := client.PostStatus(ctx, &mastodon.Toot{Status: "hello #golang", Visibility: mastodon.VisibilityUnlisted})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if status.Content != "<p>hello #golang</p>" || status.Visibility != mastodon.VisibilityUnlisted || status.Account.Acct != "alice" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	reply, err := client.PostStatus(ctx, &mastodon.Toot{Status: "again", InReplyToID: status.ID})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if reply.InReplyToID != string(status.ID) {
+		t.Fatalf("want %q but %v", status.ID, reply.InReplyToID)
+	}
+	if _, err := client.PostStatus(ctx, &mastodon.Toot{Status: " "}); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+
+	got, err := client.GetStatus(ctx, status.ID)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if got.RepliesCount != 1 {
+		t.Fatalf("want %d but %d", 1, got.RepliesCount)
+	}
+	fav, err := client.Favourite(ctx, status.ID)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if fav.FavouritesCount != 1 || fav.Favourited != true {
+		t.Fatalf("unexpected favourite: %+v", fav)
+	}
+
+	if err := client.DeleteStatus(ctx, reply.ID); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	_, err = client.GetStatus(ctx, reply.ID)
+	var apiErr *mastodon.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("want %d but %v", http.StatusNotFound, err)
+	}
+	if statuses := s.Statuses(); len(statuses) != 1 || statuses[0].ID != status.ID {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+}
+
+func TestTimelines(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	bob := s.AddAccount(&mastodon.Account{Username: "bob"})
+	for i := 0; i < 5; i++ {
+		s.AddStatus(&mastodon.Status{Content: fmt.Sprintf("status %d #tag%d", i, i%2)})
+	}
+	s.AddStatus(&mastodon.Status{Account: *bob, Content: "private", Visibility: mastodon.VisibilityPrivate})
+	client := s.Client()
+	ctx := context.Background()
+
+	pg := mastodon.Pagination{Limit: 4}
+	home, err := client.GetTimelineHome(ctx, &pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(home) != 4 || home[0].Content != "private" || home[3].Content != "status 2 #tag0" {
+		t.Fatalf("unexpected home timeline: %v", home)
+	}
+	home, err = client.GetTimelineHome(ctx, pg.Next())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(home) != 2 || home[1].Content != "status 0 #tag0" {
+		t.Fatalf("unexpected second page: %v", home)
+	}
+
+	public, err := client.GetTimelinePublic(ctx, false, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(public) != 5 {
+		t.Fatalf("want %d but %d", 5, len(public))
+	}
+	tagged, err := client.GetTimelineHashtag(ctx, "TAG1", false, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tagged) != 2 {
+		t.Fatalf("result should be two: %d", len(tagged))
+	}
+
+	statuses, err := client.GetAccountStatuses(ctx, bob.ID, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Account.StatusesCount != 1 {
+		t.Fatalf("unexpected statuses of bob: %v", statuses)
+	}
+	a, err := client.GetAccount(ctx, bob.ID)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if a.Acct != "bob" || a.StatusesCount != 1 {
+		t.Fatalf("unexpected account: %+v", a)
+	}
+	me, err := client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if me.Acct != "alice" || me.StatusesCount != 5 {
+		t.Fatalf("unexpected current user: %+v", me)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := mastodon.NewClient(&mastodon.Config{Server: s.URL, AccessToken: "wrong"})
+	_, err := client.GetTimelineHome(context.Background(), nil)
+	var apiErr *mastodon.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want %d but %v", http.StatusUnauthorized, err)
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	q, err := client.StreamingHashtag(ctx, "golang", false)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	for s.Streams() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.AddStatus(&mastodon.Status{Content: "untagged"})
+	st := s.AddStatus(&mastodon.Status{Content: "hello #golang"})
+
+	e := <-q
+	update, ok := e.(*mastodon.UpdateEvent)
+	if !ok || update.Status.ID != st.ID {
+		t.Fatalf("want update of %q but %#v", st.ID, e)
+	}
+}
+
+func ExampleServer() {
+	s := NewServer()
+	defer s.Close()
+	s.AddStatus(&mastodon.Status{Content: "hello"})
+
+	statuses, err := s.Client().GetTimelineHome(context.Background(), nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(statuses[0].Account.Acct, statuses[0].Content)
+	// Output: alice hello
+}
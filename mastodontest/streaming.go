@@ -0,0 +1,112 @@
+package mastodontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RasmusLindroth/go-mastodon"
+)
+
+// stream is a connected client of the streaming API.
+type stream struct {
+	name   string
+	tag    string
+	events chan string
+}
+
+// wants reports whether the stream receives the events of st.
+func (t *stream) wants(st *mastodon.Status) bool {
+	switch t.name {
+	case "user":
+		return true
+	case "public", "public/local":
+		return st.Visibility == mastodon.VisibilityPublic
+	case "hashtag", "hashtag/local":
+		return st.Visibility == mastodon.VisibilityPublic && hasTag(st, t.tag)
+	}
+	return false
+}
+
+// publish sends the event of st with payload, a string or encoded as JSON,
+// to the streams wanting it. It is called with s.mu held. Events are
+// dropped for streams too slow to read them.
+func (s *Server) publish(event string, st *mastodon.Status, payload interface{}) {
+	data, ok := payload.(string)
+	if !ok {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return
+		}
+		data = string(b)
+	}
+	msg := fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)
+	for t := range s.streams {
+		if !t.wants(st) {
+			continue
+		}
+		select {
+		case t.events <- msg:
+		default:
+		}
+	}
+}
+
+// Streams returns the number of connected streams. Wait for a stream to be
+// connected before adding the statuses it should receive.
+func (s *Server) Streams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams)
+}
+
+func (s *Server) serveStreaming(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/streaming"), "/")
+	if name == "health" {
+		fmt.Fprint(w, "OK")
+		return
+	}
+	switch name {
+	case "user":
+		if r.Header.Get("Authorization") != "Bearer "+AccessToken && r.URL.Query().Get("access_token") != AccessToken {
+			writeError(w, http.StatusUnauthorized, "Error: Invalid access token")
+			return
+		}
+	case "public", "public/local", "hashtag", "hashtag/local":
+	default:
+		writeError(w, http.StatusNotFound, "Unknown stream type")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	t := &stream{name: name, tag: strings.ToLower(r.URL.Query().Get("tag")), events: make(chan string, 64)}
+	s.mu.Lock()
+	s.streams[t] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, t)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ":)\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		case msg := <-t.events:
+			fmt.Fprint(w, msg)
+			flusher.Flush()
+		}
+	}
+}
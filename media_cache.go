@@ -0,0 +1,113 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// MediaCache remembers uploaded media by the hash of their content, so the
+// same file isn't uploaded again when an account retries a post or a bot
+// posts it again the same day.
+//
+// Mastodon only lets the uploader attach media to a single status, and
+// deletes media that are never attached after a day. So a cached
+// attachment is only reused for the same account, before MaxAge, and after
+// checking that the server still has it unattached.
+type MediaCache struct {
+	// MaxAge is how long an upload is reused; defaults to 23 hours.
+	MaxAge time.Duration
+
+	mu      sync.Mutex
+	entries map[string]mediaCacheEntry
+}
+
+type mediaCacheEntry struct {
+	id         ID
+	uploadedAt time.Time
+}
+
+// Upload uploads media with c, or returns the attachment already uploaded
+// for the same account with the same file, thumbnail, description and
+// focus. The File and Thumbnail of media are read into memory to hash
+// them.
+func (m *MediaCache) Upload(ctx context.Context, c *Client, media *Media) (*Attachment, error) {
+	file, err := ioutil.ReadAll(media.File)
+	if err != nil {
+		return nil, err
+	}
+	var thumb []byte
+	if media.Thumbnail != nil {
+		if thumb, err = ioutil.ReadAll(media.Thumbnail); err != nil {
+			return nil, err
+		}
+	}
+
+	key := mediaCacheKey(ctx, c, file, thumb, media.Description, media.Focus)
+	maxAge := m.MaxAge
+	if maxAge <= 0 {
+		maxAge = 23 * time.Hour
+	}
+
+	m.mu.Lock()
+	e, ok := m.entries[key]
+	m.mu.Unlock()
+	if ok && time.Since(e.uploadedAt) < maxAge {
+		// Attached or deleted media are not found.
+		if a, err := c.GetMedia(ctx, e.id); err == nil {
+			return a, nil
+		}
+	}
+
+	upload := &Media{
+		File:        bytes.NewReader(file),
+		Description: media.Description,
+		Focus:       media.Focus,
+		Progress:    media.Progress,
+	}
+	if thumb != nil {
+		upload.Thumbnail = bytes.NewReader(thumb)
+	}
+	a, err := c.UploadMediaFromMedia(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.entries == nil {
+		m.entries = map[string]mediaCacheEntry{}
+	}
+	m.entries[key] = mediaCacheEntry{id: a.ID, uploadedAt: time.Now()}
+	m.mu.Unlock()
+	return a, nil
+}
+
+// Forget removes the cached upload of the attachment id, for example after
+// attaching it to a status.
+func (m *MediaCache) Forget(id ID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, e := range m.entries {
+		if e.id == id {
+			delete(m.entries, k)
+		}
+	}
+}
+
+// mediaCacheKey hashes the content with the server and access token of c,
+// since media can only be attached by the account that uploaded them.
+func mediaCacheKey(ctx context.Context, c *Client, file, thumb []byte, description, focus string) string {
+	h := sha256.New()
+	for _, b := range [][]byte{[]byte(c.Config.Server), []byte(c.accessToken(ctx)), file, thumb, []byte(description), []byte(focus)} {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(b)))
+		h.Write(n[:])
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
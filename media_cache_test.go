@@ -0,0 +1,65 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMediaCache(t *testing.T) {
+	uploads := 0
+	attached := map[string]bool{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/media":
+			uploads++
+			fmt.Fprintf(w, `{"id": "%d", "type": "image"}`, uploads)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/media/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/media/")
+			if attached[id] {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, `{"id": "%s", "type": "image"}`, id)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	alice := NewClient(&Config{Server: ts.URL, AccessToken: "alice"})
+	bob := NewClient(&Config{Server: ts.URL, AccessToken: "bob"})
+	cache := &MediaCache{}
+	upload := func(c *Client, content string) ID {
+		a, err := cache.Upload(context.Background(), c, &Media{File: strings.NewReader(content)})
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		return a.ID
+	}
+
+	if id := upload(alice, "cat.png"); id != "1" {
+		t.Fatalf("want %q but %q", "1", id)
+	}
+	if id := upload(alice, "cat.png"); id != "1" || uploads != 1 {
+		t.Fatalf("upload should be reused: %q %d", id, uploads)
+	}
+	if id := upload(bob, "cat.png"); id != "2" {
+		t.Fatalf("want %q but %q", "2", id)
+	}
+	if id := upload(alice, "dog.png"); id != "3" {
+		t.Fatalf("want %q but %q", "3", id)
+	}
+
+	attached["1"] = true
+	if id := upload(alice, "cat.png"); id != "4" {
+		t.Fatalf("attached media should be uploaded again: %q", id)
+	}
+	cache.Forget("4")
+	if id := upload(alice, "cat.png"); id != "5" {
+		t.Fatalf("forgotten media should be uploaded again: %q", id)
+	}
+}
@@ -0,0 +1,207 @@
+package mastodon
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	reHTMLTag  = regexp.MustCompile(`<[^>]*>`)
+	reLinkTag  = regexp.MustCompile(`<a\s[^>]*>`)
+	reLinkSkip = regexp.MustCompile(`class="[^"]*\b(mention|hashtag)\b`)
+)
+
+// MentionScorer scores an incoming mention notification. Built-in scorers
+// return a score between 0 (looks fine) and 1 (looks like spam).
+type MentionScorer interface {
+	Score(n *Notification) float64
+}
+
+// MentionScorerFunc is an adapter to use an ordinary function as a MentionScorer.
+type MentionScorerFunc func(n *Notification) float64
+
+// Score calls f(n).
+func (f MentionScorerFunc) Score(n *Notification) float64 { return f(n) }
+
+// AccountAgeScorer returns a scorer that scores mentions from accounts
+// younger than minAge, from 1 for brand new accounts down to 0 at minAge.
+func AccountAgeScorer(minAge time.Duration) MentionScorer {
+	return MentionScorerFunc(func(n *Notification) float64 {
+		age := time.Since(n.Account.CreatedAt)
+		if age >= minAge {
+			return 0
+		}
+		if age < 0 {
+			return 1
+		}
+		return 1 - float64(age)/float64(minAge)
+	})
+}
+
+// LinkDensityScorer returns a scorer that scores mentions by the number of
+// links per word, not counting mentions and hashtags. A density of maxDensity
+// or more scores 1.
+func LinkDensityScorer(maxDensity float64) MentionScorer {
+	return MentionScorerFunc(func(n *Notification) float64 {
+		if n.Status == nil {
+			return 0
+		}
+		links := 0
+		for _, tag := range reLinkTag.FindAllString(n.Status.Content, -1) {
+			if !reLinkSkip.MatchString(tag) {
+				links++
+			}
+		}
+		if links == 0 {
+			return 0
+		}
+		words := len(strings.Fields(plainText(n.Status.Content)))
+		if words == 0 {
+			words = 1
+		}
+		density := float64(links) / float64(words)
+		if density >= maxDensity {
+			return 1
+		}
+		return density / maxDensity
+	})
+}
+
+// DuplicateTextScorer scores mentions whose text was already seen in recent
+// mentions. Mentions of accounts are ignored when comparing texts, so the
+// same message sent to different people is detected.
+//
+// The first occurrence of a text scores 0, the second 0.5 and any further
+// occurrence 1. The zero value remembers the last 100 mentions.
+type DuplicateTextScorer struct {
+	mu     sync.Mutex
+	window int
+	recent []string
+	counts map[string]int
+}
+
+// NewDuplicateTextScorer returns a DuplicateTextScorer that remembers the
+// last window mentions.
+func NewDuplicateTextScorer(window int) *DuplicateTextScorer {
+	return &DuplicateTextScorer{
+		window: window,
+		counts: map[string]int{},
+	}
+}
+
+// Score implements MentionScorer.
+func (d *DuplicateTextScorer) Score(n *Notification) float64 {
+	if n.Status == nil {
+		return 0
+	}
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(plainText(n.Status.Content))) {
+		if !strings.HasPrefix(w, "@") {
+			words = append(words, w)
+		}
+	}
+	text := strings.Join(words, " ")
+	if text == "" {
+		return 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.counts == nil {
+		d.counts = map[string]int{}
+	}
+	window := d.window
+	if window <= 0 {
+		window = 100
+	}
+	seen := d.counts[text]
+	d.counts[text]++
+	d.recent = append(d.recent, text)
+	if len(d.recent) > window {
+		old := d.recent[0]
+		d.recent = d.recent[1:]
+		if d.counts[old]--; d.counts[old] <= 0 {
+			delete(d.counts, old)
+		}
+	}
+
+	if seen >= 2 {
+		return 1
+	}
+	return float64(seen) / 2
+}
+
+// MentionFilter drops incoming mentions that look like spam.
+//
+// The score of a mention is the sum of the scores of all Scorers. Mentions
+// scoring Threshold or more are considered spam.
+type MentionFilter struct {
+	Scorers   []MentionScorer
+	Threshold float64
+
+	// OnSpam is called for every mention considered spam.
+	OnSpam func(n *Notification, score float64)
+}
+
+// NewMentionFilter returns a MentionFilter using the built-in heuristics:
+// accounts younger than a week, more than one link per five words, and
+// duplicate texts among the last 100 mentions.
+func NewMentionFilter(threshold float64) *MentionFilter {
+	return &MentionFilter{
+		Scorers: []MentionScorer{
+			AccountAgeScorer(7 * 24 * time.Hour),
+			LinkDensityScorer(0.2),
+			NewDuplicateTextScorer(100),
+		},
+		Threshold: threshold,
+	}
+}
+
+// Score returns the score of the mention.
+func (f *MentionFilter) Score(n *Notification) float64 {
+	var score float64
+	for _, s := range f.Scorers {
+		score += s.Score(n)
+	}
+	return score
+}
+
+// Allow reports whether the notification should be processed. Notifications
+// other than mentions are always allowed.
+func (f *MentionFilter) Allow(n *Notification) bool {
+	if n.Type != NotificationTypeMention {
+		return true
+	}
+	score := f.Score(n)
+	if score < f.Threshold {
+		return true
+	}
+	if f.OnSpam != nil {
+		f.OnSpam(n, score)
+	}
+	return false
+}
+
+// Filter reads events from q and returns a channel with the same events,
+// except for mentions that are considered spam. The returned channel is
+// closed when q is closed.
+func (f *MentionFilter) Filter(q chan Event) chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		for e := range q {
+			if ne, ok := e.(*NotificationEvent); ok && !f.Allow(ne.Notification) {
+				continue
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+func plainText(content string) string {
+	return html.UnescapeString(reHTMLTag.ReplaceAllString(content, " "))
+}
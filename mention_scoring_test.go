@@ -0,0 +1,87 @@
+package mastodon
+
+import (
+	"testing"
+	"time"
+)
+
+func mentionOf(acct string, age time.Duration, content string) *Notification {
+	return &Notification{
+		Type:    "mention",
+		Account: Account{Acct: acct, CreatedAt: time.Now().Add(-age)},
+		Status:  &Status{Content: content},
+	}
+}
+
+func TestAccountAgeScorer(t *testing.T) {
+	s := AccountAgeScorer(10 * 24 * time.Hour)
+	if got := s.Score(mentionOf("old", 30*24*time.Hour, "")); got != 0 {
+		t.Fatalf("want %v but %v", 0, got)
+	}
+	got := s.Score(mentionOf("new", 5*24*time.Hour, ""))
+	if got < 0.49 || got > 0.51 {
+		t.Fatalf("want about %v but %v", 0.5, got)
+	}
+}
+
+func TestLinkDensityScorer(t *testing.T) {
+	s := LinkDensityScorer(0.2)
+	mention := `<span class="h-card"><a href="https://example.com/@bot" class="u-url mention">@<span>bot</span></a></span>`
+	if got := s.Score(mentionOf("a", 0, `<p>`+mention+` hello there, how are you doing today?</p>`)); got != 0 {
+		t.Fatalf("want %v but %v", 0, got)
+	}
+	spam := `<p>` + mention + ` <a href="https://spam.example">cheap</a> <a href="https://spam.example/2">pills</a></p>`
+	if got := s.Score(mentionOf("a", 0, spam)); got != 1 {
+		t.Fatalf("want %v but %v", 1, got)
+	}
+}
+
+func TestDuplicateTextScorer(t *testing.T) {
+	s := NewDuplicateTextScorer(2)
+	if got := s.Score(mentionOf("a", 0, "<p>@alice Buy now!</p>")); got != 0 {
+		t.Fatalf("want %v but %v", 0, got)
+	}
+	if got := s.Score(mentionOf("b", 0, "<p>@bob buy NOW!</p>")); got != 0.5 {
+		t.Fatalf("want %v but %v", 0.5, got)
+	}
+	if got := s.Score(mentionOf("c", 0, "<p>@carol buy now!</p>")); got != 1 {
+		t.Fatalf("want %v but %v", 1, got)
+	}
+	s.Score(mentionOf("d", 0, "something else"))
+	s.Score(mentionOf("e", 0, "and another thing"))
+	if got := s.Score(mentionOf("f", 0, "buy now!")); got != 0 {
+		t.Fatalf("want %v but %v", 0, got)
+	}
+
+	var zero DuplicateTextScorer
+	zero.Score(mentionOf("a", 0, "buy now!"))
+	if got := zero.Score(mentionOf("b", 0, "buy now!")); got != 0.5 {
+		t.Fatalf("want %v but %v", 0.5, got)
+	}
+}
+
+func TestMentionFilter(t *testing.T) {
+	var spam []string
+	f := NewMentionFilter(1.5)
+	f.OnSpam = func(n *Notification, score float64) { spam = append(spam, n.Account.Acct) }
+
+	q := make(chan Event)
+	go func() {
+		defer close(q)
+		q <- &NotificationEvent{mentionOf("friend", 365*24*time.Hour, "<p>@me lunch tomorrow?</p>")}
+		q <- &NotificationEvent{mentionOf("spammer", time.Hour, `<p>@me <a href="https://spam.example">deal</a></p>`)}
+		q <- &NotificationEvent{&Notification{Type: "follow", Account: Account{Acct: "newbie"}}}
+		q <- &UpdateEvent{&Status{Content: "foo"}}
+	}()
+
+	var events []Event
+	for e := range f.Filter(q) {
+		events = append(events, e)
+	}
+	if len(events) != 3 {
+		t.Fatalf("result should be three: %d", len(events))
+	}
+	if len(spam) != 1 || spam[0] != "spammer" {
+		t.Fatalf("unexpected spam: %v", spam)
+	}
+}
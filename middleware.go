@@ -0,0 +1,33 @@
+package mastodon
+
+import (
+	"net/http"
+)
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the sending of the requests of a Client, to log, cache,
+// rewrite or mutate them. It may return a response without calling next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use appends middlewares to the client. The first middleware added is the
+// outermost one.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.Middlewares = append(c.Middlewares, middlewares...)
+}
+
+// Do sends a request through the middlewares of the client. Every request
+// of the API methods goes through it, once per attempt.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(c.Client.Do)
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		next = c.Middlewares[i](next)
+	}
+	return next(req)
+}
@@ -0,0 +1,67 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewares(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "1", "username": %q}`, r.Header.Get("X-Test"))
+	}))
+	defer ts.Close()
+
+	var log []string
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			log = append(log, "outer "+req.URL.Path)
+			req.Header.Set("X-Test", "foo")
+			return next(req)
+		}
+	}, func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			log = append(log, "inner "+req.Header.Get("X-Test"))
+			resp, err := next(req)
+			if err == nil {
+				log = append(log, "status "+resp.Status)
+			}
+			return resp, err
+		}
+	})
+	account, err := client.GetAccount(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if account.Username != "foo" {
+		t.Fatalf("want %q but %q", "foo", account.Username)
+	}
+	want := "outer /api/v1/accounts/1|inner foo|status 200 OK"
+	if got := strings.Join(log, "|"); got != want {
+		t.Fatalf("want %q but %q", want, got)
+	}
+
+	// A middleware can answer without sending the request.
+	client.Middlewares = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     "200 OK",
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "username": "cached"}`)),
+			}, nil
+		}
+	}}
+	account, err = client.GetAccount(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if account.Username != "cached" {
+		t.Fatalf("want %q but %q", "cached", account.Username)
+	}
+}
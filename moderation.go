@@ -0,0 +1,259 @@
+package mastodon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModerationSeverity is the severity of a ModerationEvent.
+type ModerationSeverity int
+
+// Severities of moderation events, from least to most severe.
+const (
+	SeverityInfo ModerationSeverity = iota
+	SeverityNotice
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s ModerationSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityNotice:
+		return "notice"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("ModerationSeverity(%d)", int(s))
+}
+
+// Categories of moderation events.
+const (
+	ModerationCategoryAccount = "account"
+	ModerationCategoryReport  = "report"
+	ModerationCategoryStatus  = "status"
+)
+
+// Sources of moderation events.
+const (
+	ModerationSourceNotification = "notification"
+	ModerationSourceWebhook      = "webhook"
+)
+
+// ModerationEvent is a moderation event, whichever way it was delivered.
+type ModerationEvent struct {
+	// Type is the type of the event in the format of webhook events, like
+	// "account.created" or "report.created".
+	Type      string
+	Category  string
+	Severity  ModerationSeverity
+	Source    string
+	CreatedAt time.Time
+
+	// Account is the account the event is about: the new account, the
+	// reporter or the author of the status.
+	Account *Account
+	// AdminAccount is set for account events received by webhook.
+	AdminAccount *AdminAccount
+	// Report is set for report events received as notifications.
+	Report *Report
+	// AdminReport is set for report events received by webhook.
+	AdminReport *AdminReport
+	// Status is set for status events.
+	Status *Status
+}
+
+// ModerationBus normalizes admin notifications and webhook payloads into
+// ModerationEvents and dispatches them to subscribers, so moderation tools
+// have a single integration point whatever the delivery mechanism.
+//
+// ModerationBus is an http.Handler receiving Mastodon webhooks. When Secret
+// is set, requests without a valid X-Hub-Signature are rejected.
+type ModerationBus struct {
+	// Secret is the secret of the webhook.
+	Secret string
+	// Severity overrides the default severity of events. It returns the
+	// severity for e, which has its default severity set.
+	Severity func(e *ModerationEvent) ModerationSeverity
+
+	mu       sync.Mutex
+	handlers []moderationHandler
+}
+
+type moderationHandler struct {
+	min ModerationSeverity
+	fn  func(e *ModerationEvent)
+}
+
+// Subscribe registers fn for the events of severity min or more. fn is
+// called synchronously by Publish.
+func (b *ModerationBus) Subscribe(min ModerationSeverity, fn func(e *ModerationEvent)) {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, moderationHandler{min: min, fn: fn})
+	b.mu.Unlock()
+}
+
+// Publish sets the severity of e and dispatches it to the subscribers.
+func (b *ModerationBus) Publish(e *ModerationEvent) {
+	e.Severity = defaultModerationSeverity(e)
+	if b.Severity != nil {
+		e.Severity = b.Severity(e)
+	}
+	b.mu.Lock()
+	handlers := make([]moderationHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.Unlock()
+	for _, h := range handlers {
+		if e.Severity >= h.min {
+			h.fn(e)
+		}
+	}
+}
+
+// PublishNotification publishes admin.sign_up and admin.report
+// notifications and reports whether n was one of them.
+func (b *ModerationBus) PublishNotification(n *Notification) bool {
+	e := &ModerationEvent{
+		Source:    ModerationSourceNotification,
+		CreatedAt: n.CreatedAt,
+	}
+	account := n.Account
+	e.Account = &account
+	switch n.Type {
+	case NotificationTypeAdminSignUp:
+		e.Type = "account.created"
+		e.Category = ModerationCategoryAccount
+	case NotificationTypeAdminReport:
+		e.Type = "report.created"
+		e.Category = ModerationCategoryReport
+		e.Report = n.Report
+	default:
+		return false
+	}
+	b.Publish(e)
+	return true
+}
+
+// PublishWebhook publishes the payload of a webhook request.
+func (b *ModerationBus) PublishWebhook(payload []byte) error {
+	var p struct {
+		Event     string          `json:"event"`
+		CreatedAt time.Time       `json:"created_at"`
+		Object    json.RawMessage `json:"object"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	e := &ModerationEvent{
+		Type:      p.Event,
+		Source:    ModerationSourceWebhook,
+		CreatedAt: p.CreatedAt,
+	}
+	switch e.Category = strings.SplitN(p.Event, ".", 2)[0]; e.Category {
+	case ModerationCategoryAccount:
+		var a AdminAccount
+		if err := json.Unmarshal(p.Object, &a); err != nil {
+			return err
+		}
+		e.AdminAccount = &a
+		e.Account = a.Account
+	case ModerationCategoryReport:
+		var r AdminReport
+		if err := json.Unmarshal(p.Object, &r); err != nil {
+			return err
+		}
+		e.AdminReport = &r
+		if r.Account != nil {
+			e.Account = r.Account.Account
+		}
+	case ModerationCategoryStatus:
+		var s Status
+		if err := json.Unmarshal(p.Object, &s); err != nil {
+			return err
+		}
+		e.Status = &s
+		e.Account = &s.Account
+	default:
+		return fmt.Errorf("unknown webhook event %q", p.Event)
+	}
+	b.Publish(e)
+	return nil
+}
+
+// Consume publishes the admin notifications of a stream, such as the one
+// returned by StreamingUser, until q is closed.
+func (b *ModerationBus) Consume(q chan Event) {
+	for e := range q {
+		if ne, ok := e.(*NotificationEvent); ok {
+			b.PublishNotification(ne.Notification)
+		}
+	}
+}
+
+// ServeHTTP receives webhook requests.
+func (b *ModerationBus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if b.Secret != "" && !validWebhookSignature(b.Secret, r.Header.Get("X-Hub-Signature"), payload) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if err := b.PublishWebhook(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func validWebhookSignature(secret, signature string, payload []byte) bool {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// defaultModerationSeverity rates new reports as warnings, or critical for
+// legal issues, accounts waiting for approval as notices and anything
+// else as information.
+func defaultModerationSeverity(e *ModerationEvent) ModerationSeverity {
+	switch e.Type {
+	case "report.created":
+		var category ReportCategory
+		if e.AdminReport != nil {
+			category = e.AdminReport.Category
+		} else if e.Report != nil {
+			category = e.Report.Category
+		}
+		if category == ReportCategoryLegal {
+			return SeverityCritical
+		}
+		return SeverityWarning
+	case "account.created":
+		if e.AdminAccount != nil && !e.AdminAccount.Approved {
+			return SeverityNotice
+		}
+	}
+	return SeverityInfo
+}
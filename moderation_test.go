@@ -0,0 +1,99 @@
+package mastodon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestModerationBusNotifications(t *testing.T) {
+	var events []*ModerationEvent
+	b := &ModerationBus{}
+	b.Subscribe(SeverityWarning, func(e *ModerationEvent) { events = append(events, e) })
+
+	q := make(chan Event, 3)
+	q <- &NotificationEvent{&Notification{Type: "admin.sign_up", Account: Account{Acct: "newbie"}}}
+	q <- &NotificationEvent{&Notification{Type: "admin.report", Account: Account{Acct: "alice"}, Report: &Report{ID: "7", Category: "spam"}}}
+	q <- &NotificationEvent{&Notification{Type: "mention"}}
+	close(q)
+	b.Consume(q)
+
+	if len(events) != 1 {
+		t.Fatalf("result should be one: %d", len(events))
+	}
+	e := events[0]
+	if e.Type != "report.created" || e.Category != ModerationCategoryReport || e.Severity != SeverityWarning || e.Source != ModerationSourceNotification {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e.Account.Acct != "alice" || e.Report.ID != "7" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestModerationBusWebhook(t *testing.T) {
+	var events []*ModerationEvent
+	b := &ModerationBus{
+		Secret: "secret",
+		Severity: func(e *ModerationEvent) ModerationSeverity {
+			if e.Status != nil && strings.Contains(e.Status.Content, "crypto") {
+				return SeverityCritical
+			}
+			return e.Severity
+		},
+	}
+	b.Subscribe(SeverityInfo, func(e *ModerationEvent) { events = append(events, e) })
+	ts := httptest.NewServer(b)
+	defer ts.Close()
+
+	post := func(payload, secret string) int {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(payload))
+		req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post(`{"event": "account.created", "object": {"id": "1"}}`, "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("want %v but %v", http.StatusUnauthorized, code)
+	}
+	if code := post(`{"event": "account.created", "created_at": "2022-09-14T00:00:00Z", "object": {"id": "1", "approved": false, "account": {"id": "1", "acct": "newbie"}}}`, "secret"); code != http.StatusOK {
+		t.Fatalf("want %v but %v", http.StatusOK, code)
+	}
+	if code := post(`{"event": "report.created", "object": {"id": "2", "category": "legal", "account": {"id": "3", "account": {"id": "3", "acct": "alice"}}}}`, "secret"); code != http.StatusOK {
+		t.Fatalf("want %v but %v", http.StatusOK, code)
+	}
+	if code := post(`{"event": "status.created", "object": {"id": "4", "content": "free crypto", "account": {"id": "5", "acct": "spammer"}}}`, "secret"); code != http.StatusOK {
+		t.Fatalf("want %v but %v", http.StatusOK, code)
+	}
+	if code := post(`{"event": "unknown.created", "object": {}}`, "secret"); code != http.StatusBadRequest {
+		t.Fatalf("want %v but %v", http.StatusBadRequest, code)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("result should be three: %d", len(events))
+	}
+	if events[0].Severity != SeverityNotice || events[0].Account.Acct != "newbie" || events[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+	if events[1].Severity != SeverityCritical || events[1].AdminReport.ID != "2" || events[1].Account.Acct != "alice" {
+		t.Fatalf("unexpected event: %+v", events[1])
+	}
+	if events[2].Severity != SeverityCritical || events[2].Category != ModerationCategoryStatus || events[2].Account.Acct != "spammer" {
+		t.Fatalf("unexpected event: %+v", events[2])
+	}
+	if SeverityWarning.String() != "warning" {
+		t.Fatalf("want %q but %q", "warning", SeverityWarning.String())
+	}
+}
@@ -0,0 +1,135 @@
+package mastodon
+
+import (
+	"context"
+	"time"
+)
+
+// MovedFollow is a followed account that moved to another account.
+type MovedFollow struct {
+	// Account is the followed account and Target the account it moved to,
+	// following chains of moves.
+	Account *Account
+	Target  *Account
+	// Following reports whether Target was already followed or requested.
+	Following bool
+	// Fixed reports whether Target was followed and Account unfollowed.
+	Fixed bool
+	// Err is the error of the fix, if it failed.
+	Err error
+}
+
+// MovedFollowsFixer finds the accounts followed by the current user that
+// moved to another account, and follows the new account while unfollowing
+// the old one. Errors of single fixes are recorded on their MovedFollow
+// and don't stop the others.
+type MovedFollowsFixer struct {
+	Client *Client
+	// DryRun only reports the moved accounts without changing anything.
+	DryRun bool
+	// BatchSize is the number of accounts fixed before pausing for
+	// BatchInterval; defaults to 10 and one minute.
+	BatchSize     int
+	BatchInterval time.Duration
+	// Interval is waited between the pages of followed accounts while
+	// looking for moved ones. Zero means one second and negative values
+	// disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoint of the search for moved accounts, so an
+	// interrupted run resumes it where it left off; nil disables resuming.
+	Jobs CheckpointStore
+}
+
+// Fix returns the moved accounts followed by the current user, fixing them
+// unless DryRun is set.
+func (f *MovedFollowsFixer) Fix(ctx context.Context) ([]*MovedFollow, error) {
+	interval := pageInterval(f.Interval)
+	batchSize := f.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	batchInterval := f.BatchInterval
+	if batchInterval == 0 {
+		batchInterval = time.Minute
+	}
+
+	me, err := f.Client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var moved []*MovedFollow
+	job := "moved-follows-" + string(me.ID)
+	err = walkJob(ctx, f.Jobs, job, &moved, interval, &Pagination{Limit: 80}, func(pg *Pagination) (bool, error) {
+		accounts, err := f.Client.GetAccountFollowing(ctx, me.ID, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			if target := movedTarget(a); target != nil {
+				moved = append(moved, &MovedFollow{Account: a, Target: target})
+			}
+		}
+		return len(accounts) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The relationships endpoint takes up to 40 accounts.
+	for i := 0; i < len(moved); i += 40 {
+		batch := moved[i:]
+		if len(batch) > 40 {
+			batch = batch[:40]
+		}
+		ids := make([]string, len(batch))
+		for j, m := range batch {
+			ids[j] = string(m.Target.ID)
+		}
+		relationships, err := f.Client.GetAccountRelationships(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		following := map[ID]bool{}
+		for _, r := range relationships {
+			following[r.ID] = r.Following || r.Requested
+		}
+		for _, m := range batch {
+			m.Following = following[m.Target.ID]
+		}
+	}
+	if f.DryRun {
+		return moved, deleteJobs(ctx, f.Jobs, job)
+	}
+
+	for i, m := range moved {
+		if i > 0 && i%batchSize == 0 && batchInterval > 0 {
+			select {
+			case <-time.After(batchInterval):
+			case <-ctx.Done():
+				return moved, ctx.Err()
+			}
+		}
+		if !m.Following {
+			if _, m.Err = f.Client.AccountFollow(ctx, m.Target.ID); m.Err != nil {
+				continue
+			}
+		}
+		if _, m.Err = f.Client.AccountUnfollow(ctx, m.Account.ID); m.Err != nil {
+			continue
+		}
+		m.Fixed = true
+	}
+	return moved, deleteJobs(ctx, f.Jobs, job)
+}
+
+// movedTarget returns the account a moved to, following chains of moves,
+// or nil if a didn't move.
+func movedTarget(a *Account) *Account {
+	seen := map[ID]bool{a.ID: true}
+	target := a.Moved
+	for target != nil && target.Moved != nil && !seen[target.Moved.ID] {
+		seen[target.ID] = true
+		target = target.Moved
+	}
+	return target
+}
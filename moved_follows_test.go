@@ -0,0 +1,73 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMovedFollowsFixer(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/verify_credentials":
+			fmt.Fprintln(w, `{"id": "1"}`)
+		case "/api/v1/accounts/1/following":
+			fmt.Fprintln(w, `[
+				{"id": "2", "acct": "old@a.example", "moved": {"id": "3", "acct": "new@b.example"}},
+				{"id": "4", "acct": "stay@a.example"},
+				{"id": "5", "acct": "twice@a.example", "moved": {"id": "6", "moved": {"id": "7", "acct": "last@c.example"}}},
+				{"id": "8", "acct": "gone@a.example", "moved": {"id": "9"}}
+			]`)
+		case "/api/v1/accounts/relationships":
+			if strings.Join(r.URL.Query()["id[]"], ",") != "3,7,9" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "3"}, {"id": "7", "following": true}, {"id": "9"}]`)
+		case "/api/v1/accounts/9/follow":
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		default:
+			calls = append(calls, r.URL.Path)
+			fmt.Fprintln(w, `{}`)
+		}
+	}))
+	defer ts.Close()
+
+	f := &MovedFollowsFixer{
+		Client:        NewClient(&Config{Server: ts.URL, AccessToken: "zoo"}),
+		DryRun:        true,
+		BatchSize:     1,
+		BatchInterval: -1,
+		Interval:      -1,
+	}
+	moved, err := f.Fix(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(moved) != 3 {
+		t.Fatalf("result should be three: %d", len(moved))
+	}
+	if moved[1].Target.ID != "7" || !moved[1].Following {
+		t.Fatalf("unexpected move: %+v", moved[1])
+	}
+	if len(calls) != 0 {
+		t.Fatalf("dry run should not change anything: %v", calls)
+	}
+
+	f.DryRun = false
+	moved, err = f.Fix(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := "/api/v1/accounts/3/follow,/api/v1/accounts/2/unfollow,/api/v1/accounts/5/unfollow"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("want %q but %q", want, got)
+	}
+	if !moved[0].Fixed || !moved[1].Fixed || moved[2].Fixed || moved[2].Err == nil {
+		t.Fatalf("unexpected fixes: %+v %+v %+v", moved[0], moved[1], moved[2])
+	}
+}
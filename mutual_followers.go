@@ -0,0 +1,93 @@
+package mastodon
+
+import (
+	"context"
+	"time"
+)
+
+// MutualFollowersFinder finds the accounts following two accounts, for
+// audience overlap analysis.
+type MutualFollowersFinder struct {
+	Client *Client
+	// Interval is waited between the pages of followers. Zero means one
+	// second and negative values disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoints of the walks of both follower lists,
+	// named after the accounts, so an interrupted search resumes where it
+	// left off; nil disables resuming.
+	Jobs CheckpointStore
+}
+
+// MutualFollowers returns the accounts that follow both a and b, like
+// MutualFollowersFinder.Find with the default interval.
+func (c *Client) MutualFollowers(ctx context.Context, a, b ID) ([]*Account, error) {
+	return (&MutualFollowersFinder{Client: c}).Find(ctx, a, b)
+}
+
+// Find returns the accounts that follow both a and b.
+//
+// Both follower lists are enumerated page by page, starting with the
+// account with fewer followers, so it can take a while for popular
+// accounts. Remote accounts may not expose all of their followers to the
+// instance. To find the accounts you follow that also follow an account,
+// use GetFamiliarFollowers instead.
+func (m *MutualFollowersFinder) Find(ctx context.Context, a, b ID) ([]*Account, error) {
+	c := m.Client
+	interval := pageInterval(m.Interval)
+	accountA, err := c.GetAccount(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	accountB, err := c.GetAccount(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	if accountA.FollowersCount > accountB.FollowersCount {
+		a, b = b, a
+	}
+
+	jobA := "mutual-followers-" + string(a) + "-" + string(b)
+	jobB := jobA + "-mutual"
+	followers := map[ID]bool{}
+	err = walkJob(ctx, m.Jobs, jobA, &followers, interval, &Pagination{Limit: 80}, func(pg *Pagination) (bool, error) {
+		accounts, err := c.GetAccountFollowers(ctx, a, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, acct := range accounts {
+			followers[acct.ID] = true
+		}
+		return len(accounts) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var mutual []*Account
+	if len(followers) == 0 {
+		return mutual, deleteJobs(ctx, m.Jobs, jobA)
+	}
+	// The followers of a found in the followers of b are removed, so the
+	// walk can stop early.
+	state := &struct {
+		Followers *map[ID]bool `json:"followers"`
+		Mutual    *[]*Account  `json:"mutual"`
+	}{&followers, &mutual}
+	err = walkJob(ctx, m.Jobs, jobB, state, interval, &Pagination{Limit: 80}, func(pg *Pagination) (bool, error) {
+		accounts, err := c.GetAccountFollowers(ctx, b, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, acct := range accounts {
+			if followers[acct.ID] {
+				mutual = append(mutual, acct)
+				delete(followers, acct.ID)
+			}
+		}
+		return len(accounts) > 0 && len(followers) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mutual, deleteJobs(ctx, m.Jobs, jobA, jobB)
+}
@@ -0,0 +1,58 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMutualFollowers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/1":
+			fmt.Fprintln(w, `{"id": "1", "followers_count": 3}`)
+		case "/api/v1/accounts/2":
+			fmt.Fprintln(w, `{"id": "2", "followers_count": 2}`)
+		case "/api/v1/accounts/1/followers":
+			if r.FormValue("max_id") == "" {
+				w.Header().Set("Link", `<http://example.com/api/v1/accounts/1/followers?max_id=11>; rel="next"`)
+				fmt.Fprintln(w, `[{"id": "12", "acct": "carol"}, {"id": "11", "acct": "bob"}]`)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "10", "acct": "alice"}]`)
+		case "/api/v1/accounts/2/followers":
+			fmt.Fprintln(w, `[{"id": "13", "acct": "dave"}, {"id": "10", "acct": "alice"}]`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	mutual, err := client.MutualFollowers(context.Background(), "1", "2")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(mutual) != 1 || mutual[0].Acct != "alice" {
+		t.Fatalf("unexpected mutual followers: %+v", mutual)
+	}
+
+	finder := &MutualFollowersFinder{Client: client, Interval: -1}
+	mutual, err = finder.Find(context.Background(), "2", "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(mutual) != 1 || mutual[0].Acct != "alice" {
+		t.Fatalf("unexpected mutual followers: %+v", mutual)
+	}
+
+	_, err = finder.Find(context.Background(), "1", "3")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const nodeInfoSchemaPrefix = "http://nodeinfo.diaspora.software/ns/schema/"
+
+// NodeInfo holds the NodeInfo document of a server.
+//
+// NodeInfo is not part of the Mastodon API, it is served by most fediverse
+// software and can be used to tell which software a server is running.
+type NodeInfo struct {
+	Version           string                 `json:"version"`
+	Software          NodeInfoSoftware       `json:"software"`
+	Protocols         []string               `json:"protocols"`
+	Services          NodeInfoServices       `json:"services"`
+	OpenRegistrations bool                   `json:"openRegistrations"`
+	Usage             NodeInfoUsage          `json:"usage"`
+	Metadata          map[string]interface{} `json:"metadata"`
+}
+
+// NodeInfoSoftware holds the name and version of the server software.
+type NodeInfoSoftware struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Repository string `json:"repository"`
+	Homepage   string `json:"homepage"`
+}
+
+// NodeInfoServices holds the third party services the server can connect to.
+type NodeInfoServices struct {
+	Inbound  []string `json:"inbound"`
+	Outbound []string `json:"outbound"`
+}
+
+// NodeInfoUsage holds usage statistics of the server.
+type NodeInfoUsage struct {
+	Users         NodeInfoUsers `json:"users"`
+	LocalPosts    int64         `json:"localPosts"`
+	LocalComments int64         `json:"localComments"`
+}
+
+// NodeInfoUsers holds user statistics of the server.
+type NodeInfoUsers struct {
+	Total          int64 `json:"total"`
+	ActiveMonth    int64 `json:"activeMonth"`
+	ActiveHalfyear int64 `json:"activeHalfyear"`
+}
+
+// GetNodeInfo discovers and returns the NodeInfo document of the server.
+//
+// The newest schema version advertised in /.well-known/nodeinfo is used.
+func (c *Client) GetNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	u, err := url.Parse(c.Config.Server)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/.well-known/nodeinfo")
+
+	var wellKnown struct {
+		Links []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	if err := c.getJSON(ctx, u.String(), &wellKnown); err != nil {
+		return nil, err
+	}
+
+	var rel, href string
+	for _, link := range wellKnown.Links {
+		if strings.HasPrefix(link.Rel, nodeInfoSchemaPrefix) && link.Rel > rel {
+			rel, href = link.Rel, link.Href
+		}
+	}
+	if href == "" {
+		return nil, errors.New("no nodeinfo link found")
+	}
+	ref, err := u.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeInfo NodeInfo
+	if err := c.getJSON(ctx, ref.String(), &nodeInfo); err != nil {
+		return nil, err
+	}
+	return &nodeInfo, nil
+}
+
+// getJSON fetches an unauthenticated JSON document from rawurl.
+func (c *Client) getJSON(ctx context.Context, rawurl string, res interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseAPIError("bad request", resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
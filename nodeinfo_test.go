@@ -0,0 +1,72 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetNodeInfo(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/nodeinfo":
+			fmt.Fprintf(w, `{"links": [{"rel": "http://nodeinfo.diaspora.software/ns/schema/2.0", "href": "%[1]s/nodeinfo/2.0"}, {"rel": "http://nodeinfo.diaspora.software/ns/schema/2.1", "href": "%[1]s/nodeinfo/2.1.json"}]}`, ts.URL)
+		case "/nodeinfo/2.1.json":
+			fmt.Fprintln(w, `{"version": "2.1", "software": {"name": "akkoma", "version": "3.9.3", "repository": "https://akkoma.dev/AkkomaGang/akkoma"}, "protocols": ["activitypub"], "services": {"inbound": [], "outbound": []}, "openRegistrations": true, "usage": {"users": {"total": 42, "activeMonth": 7, "activeHalfyear": 12}, "localPosts": 1234}, "metadata": {"nodeName": "example"}}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server: ts.URL,
+	})
+	ni, err := client.GetNodeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if ni.Version != "2.1" {
+		t.Fatalf("want %q but %q", "2.1", ni.Version)
+	}
+	if ni.Software.Name != "akkoma" {
+		t.Fatalf("want %q but %q", "akkoma", ni.Software.Name)
+	}
+	if ni.Software.Version != "3.9.3" {
+		t.Fatalf("want %q but %q", "3.9.3", ni.Software.Version)
+	}
+	if len(ni.Protocols) != 1 || ni.Protocols[0] != "activitypub" {
+		t.Fatalf("want %v but %v", []string{"activitypub"}, ni.Protocols)
+	}
+	if !ni.OpenRegistrations {
+		t.Fatalf("want %v but %v", true, ni.OpenRegistrations)
+	}
+	if ni.Usage.Users.Total != 42 || ni.Usage.Users.ActiveMonth != 7 || ni.Usage.Users.ActiveHalfyear != 12 {
+		t.Fatalf("unexpected users: %+v", ni.Usage.Users)
+	}
+	if ni.Usage.LocalPosts != 1234 {
+		t.Fatalf("want %d but %d", 1234, ni.Usage.LocalPosts)
+	}
+}
+
+func TestGetNodeInfoNoLink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/nodeinfo" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"links": [{"rel": "self", "href": "/foo"}]}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server: ts.URL,
+	})
+	_, err := client.GetNodeInfo(context.Background())
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,26 +16,100 @@ import (
 
 // Notification holds information for a mastodon notification.
 type Notification struct {
-	ID        ID        `json:"id"`
-	Type      string    `json:"type"`
-	CreatedAt time.Time `json:"created_at"`
-	Account   Account   `json:"account"`
-	Status    *Status   `json:"status"`
-	Emoji     string    `json:"emoji"`
+	ID        ID               `json:"id"`
+	Type      NotificationType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Account   Account          `json:"account"`
+	Status    *Status          `json:"status"`
+	Emoji     string           `json:"emoji"`
+	// Report is set for admin.report notifications.
+	Report *Report `json:"report"`
+	// Extra holds the members of the JSON object without a field, like
+	// the additions of other servers, when Client.ExtraFields is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// PushSubscription holds information for a Web Push subscription.
 type PushSubscription struct {
 	ID        ID          `json:"id"`
 	Endpoint  string      `json:"endpoint"`
 	ServerKey string      `json:"server_key"`
 	Alerts    *PushAlerts `json:"alerts"`
+	Policy    PushPolicy  `json:"policy"`
 }
 
+// PushAlerts holds which types of notifications are pushed. Nil fields are
+// left unchanged when subscribing or updating.
 type PushAlerts struct {
-	Follow    *Sbool `json:"follow"`
-	Favourite *Sbool `json:"favourite"`
-	Reblog    *Sbool `json:"reblog"`
-	Mention   *Sbool `json:"mention"`
+	Follow        *Sbool `json:"follow"`
+	FollowRequest *Sbool `json:"follow_request"`
+	Favourite     *Sbool `json:"favourite"`
+	Reblog        *Sbool `json:"reblog"`
+	Mention       *Sbool `json:"mention"`
+	Poll          *Sbool `json:"poll"`
+	Status        *Sbool `json:"status"`
+	Update        *Sbool `json:"update"`
+	AdminSignUp   *Sbool `json:"admin.sign_up"`
+	AdminReport   *Sbool `json:"admin.report"`
+}
+
+func (a *PushAlerts) setValues(params url.Values) {
+	if a == nil {
+		return
+	}
+	for _, alert := range []struct {
+		name  string
+		value *Sbool
+	}{
+		{"follow", a.Follow},
+		{"follow_request", a.FollowRequest},
+		{"favourite", a.Favourite},
+		{"reblog", a.Reblog},
+		{"mention", a.Mention},
+		{"poll", a.Poll},
+		{"status", a.Status},
+		{"update", a.Update},
+		{"admin.sign_up", a.AdminSignUp},
+		{"admin.report", a.AdminReport},
+	} {
+		if alert.value != nil {
+			params.Set("data[alerts]["+alert.name+"]", strconv.FormatBool(bool(*alert.value)))
+		}
+	}
+}
+
+// PushKeys holds the keys of a push subscription. The server encrypts the
+// payloads it pushes with them.
+type PushKeys struct {
+	PrivateKey *ecdsa.PrivateKey
+	// Auth is the 16 bytes authentication secret.
+	Auth []byte
+}
+
+// GeneratePushKeys generates a new P-256 key pair and authentication secret
+// for a push subscription. Keep them to decrypt the pushed payloads.
+func GeneratePushKeys() (*PushKeys, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		return nil, err
+	}
+	return &PushKeys{PrivateKey: priv, Auth: auth}, nil
+}
+
+// P256dh returns the public key encoded for the subscription request.
+func (k *PushKeys) P256dh() string {
+	pub := k.PrivateKey.PublicKey
+	return base64.RawURLEncoding.EncodeToString(elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+}
+
+// AuthSecret returns the authentication secret encoded for the subscription
+// request.
+func (k *PushKeys) AuthSecret() string {
+	return base64.RawURLEncoding.EncodeToString(k.Auth)
 }
 
 // GetNotifications returns notifications.
@@ -43,13 +119,47 @@ func (c *Client) GetNotifications(ctx context.Context, pg *Pagination) ([]*Notif
 
 // GetNotificationsExclude returns notifications with excluded notifications
 func (c *Client) GetNotificationsExclude(ctx context.Context, exclude *[]string, pg *Pagination) ([]*Notification, error) {
-	var notifications []*Notification
-	params := url.Values{}
+	opts := &NotificationsOptions{}
 	if exclude != nil {
 		for _, ex := range *exclude {
-			params.Add("exclude_types[]", ex)
+			opts.ExcludeTypes = append(opts.ExcludeTypes, NotificationType(ex))
 		}
 	}
+	return c.GetNotificationsWithOptions(ctx, opts, pg)
+}
+
+// NotificationsOptions filters the notifications returned by
+// GetNotificationsWithOptions.
+type NotificationsOptions struct {
+	// Types only returns notifications of these types.
+	Types []NotificationType
+	// ExcludeTypes skips notifications of these types.
+	ExcludeTypes []NotificationType
+	// AccountID only returns notifications from this account.
+	AccountID ID
+}
+
+func (o *NotificationsOptions) setValues(params url.Values) {
+	if o == nil {
+		return
+	}
+	for _, t := range o.Types {
+		params.Add("types[]", string(t))
+	}
+	for _, t := range o.ExcludeTypes {
+		params.Add("exclude_types[]", string(t))
+	}
+	if o.AccountID != "" {
+		params.Set("account_id", string(o.AccountID))
+	}
+}
+
+// GetNotificationsWithOptions returns the notifications filtered by opts,
+// which may be nil.
+func (c *Client) GetNotificationsWithOptions(ctx context.Context, opts *NotificationsOptions, pg *Pagination) ([]*Notification, error) {
+	var notifications []*Notification
+	params := url.Values{}
+	opts.setValues(params)
 	err := c.doAPI(ctx, http.MethodGet, "/api/v1/notifications", params, &notifications, pg)
 	if err != nil {
 		return nil, err
@@ -57,6 +167,22 @@ func (c *Client) GetNotificationsExclude(ctx context.Context, exclude *[]string,
 	return notifications, nil
 }
 
+// GetNotificationsUnreadCount returns the number of unread notifications
+// filtered by opts, which may be nil. The server caps the count, at 100 by
+// default.
+func (c *Client) GetNotificationsUnreadCount(ctx context.Context, opts *NotificationsOptions) (int64, error) {
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	params := url.Values{}
+	opts.setValues(params)
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/notifications/unread_count", params, &result, nil)
+	if err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
 // GetNotification returns notification.
 func (c *Client) GetNotification(ctx context.Context, id ID) (*Notification, error) {
 	var notification Notification
@@ -79,23 +205,26 @@ func (c *Client) ClearNotifications(ctx context.Context) error {
 
 // AddPushSubscription adds a new push subscription.
 func (c *Client) AddPushSubscription(ctx context.Context, endpoint string, public ecdsa.PublicKey, shared []byte, alerts PushAlerts) (*PushSubscription, error) {
-	var subscription PushSubscription
 	pk := elliptic.Marshal(public.Curve, public.X, public.Y)
+	return c.pushSubscribe(ctx, endpoint, base64.RawURLEncoding.EncodeToString(pk), base64.RawURLEncoding.EncodeToString(shared), &alerts, "")
+}
+
+// PushSubscribe subscribes endpoint to push notifications encrypted with
+// keys, replacing the active subscription of the access token. An empty
+// policy keeps the default of the server.
+func (c *Client) PushSubscribe(ctx context.Context, endpoint string, keys *PushKeys, alerts *PushAlerts, policy PushPolicy) (*PushSubscription, error) {
+	return c.pushSubscribe(ctx, endpoint, keys.P256dh(), keys.AuthSecret(), alerts, policy)
+}
+
+func (c *Client) pushSubscribe(ctx context.Context, endpoint, p256dh, auth string, alerts *PushAlerts, policy PushPolicy) (*PushSubscription, error) {
+	var subscription PushSubscription
 	params := url.Values{}
 	params.Add("subscription[endpoint]", endpoint)
-	params.Add("subscription[keys][p256dh]", base64.RawURLEncoding.EncodeToString(pk))
-	params.Add("subscription[keys][auth]", base64.RawURLEncoding.EncodeToString(shared))
-	if alerts.Follow != nil {
-		params.Add("data[alerts][follow]", strconv.FormatBool(bool(*alerts.Follow)))
-	}
-	if alerts.Favourite != nil {
-		params.Add("data[alerts][favourite]", strconv.FormatBool(bool(*alerts.Favourite)))
-	}
-	if alerts.Reblog != nil {
-		params.Add("data[alerts][reblog]", strconv.FormatBool(bool(*alerts.Reblog)))
-	}
-	if alerts.Mention != nil {
-		params.Add("data[alerts][mention]", strconv.FormatBool(bool(*alerts.Mention)))
+	params.Add("subscription[keys][p256dh]", p256dh)
+	params.Add("subscription[keys][auth]", auth)
+	alerts.setValues(params)
+	if policy != "" {
+		params.Add("data[policy]", string(policy))
 	}
 	err := c.doAPI(ctx, http.MethodPost, "/api/v1/push/subscription", params, &subscription, nil)
 	if err != nil {
@@ -106,20 +235,20 @@ func (c *Client) AddPushSubscription(ctx context.Context, endpoint string, publi
 
 // UpdatePushSubscription updates which type of notifications are sent for the active push subscription.
 func (c *Client) UpdatePushSubscription(ctx context.Context, alerts *PushAlerts) (*PushSubscription, error) {
-	var subscription PushSubscription
 	params := url.Values{}
-	if alerts.Follow != nil {
-		params.Add("data[alerts][follow]", strconv.FormatBool(bool(*alerts.Follow)))
-	}
-	if alerts.Mention != nil {
-		params.Add("data[alerts][favourite]", strconv.FormatBool(bool(*alerts.Favourite)))
-	}
-	if alerts.Reblog != nil {
-		params.Add("data[alerts][reblog]", strconv.FormatBool(bool(*alerts.Reblog)))
-	}
-	if alerts.Mention != nil {
-		params.Add("data[alerts][mention]", strconv.FormatBool(bool(*alerts.Mention)))
-	}
+	alerts.setValues(params)
+	return c.updatePushSubscription(ctx, params)
+}
+
+// UpdatePushSubscriptionPolicy updates the policy of the active push subscription.
+func (c *Client) UpdatePushSubscriptionPolicy(ctx context.Context, policy PushPolicy) (*PushSubscription, error) {
+	params := url.Values{}
+	params.Set("data[policy]", string(policy))
+	return c.updatePushSubscription(ctx, params)
+}
+
+func (c *Client) updatePushSubscription(ctx context.Context, params url.Values) (*PushSubscription, error) {
+	var subscription PushSubscription
 	err := c.doAPI(ctx, http.MethodPut, "/api/v1/push/subscription", params, &subscription, nil)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,217 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GroupedNotifications holds grouped notifications, since Mastodon 4.3.
+// The accounts and statuses of the groups are returned once beside them.
+type GroupedNotifications struct {
+	Accounts []*Account `json:"accounts"`
+	// PartialAccounts holds the accounts of the groups beyond the first of
+	// each group when requested with ExpandAccountsPartialAvatars.
+	PartialAccounts    []*PartialAccount    `json:"partial_accounts"`
+	Statuses           []*Status            `json:"statuses"`
+	NotificationGroups []*NotificationGroup `json:"notification_groups"`
+}
+
+// NotificationGroup holds notifications of the same type about the same
+// status, like the favourites of a status.
+type NotificationGroup struct {
+	GroupKey                 string           `json:"group_key"`
+	NotificationsCount       int64            `json:"notifications_count"`
+	Type                     NotificationType `json:"type"`
+	MostRecentNotificationID ID               `json:"most_recent_notification_id"`
+	// PageMinID and PageMaxID are the oldest and newest notifications of
+	// the group in the returned page.
+	PageMinID                ID        `json:"page_min_id"`
+	PageMaxID                ID        `json:"page_max_id"`
+	LatestPageNotificationAt time.Time `json:"latest_page_notification_at"`
+	// SampleAccountIDs holds the IDs of a few accounts of the group, most
+	// recent first.
+	SampleAccountIDs []ID    `json:"sample_account_ids"`
+	StatusID         ID      `json:"status_id"`
+	Report           *Report `json:"report"`
+}
+
+// PartialAccount holds the few attributes of an account needed to show its
+// avatar.
+type PartialAccount struct {
+	ID           ID     `json:"id"`
+	Acct         string `json:"acct"`
+	URL          string `json:"url"`
+	Avatar       string `json:"avatar"`
+	AvatarStatic string `json:"avatar_static"`
+	Locked       bool   `json:"locked"`
+	Bot          bool   `json:"bot"`
+}
+
+// Account returns the account id among the accounts of n, or nil.
+func (n *GroupedNotifications) Account(id ID) *Account {
+	for _, a := range n.Accounts {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// Status returns the status of g among the statuses of n, or nil.
+func (n *GroupedNotifications) Status(g *NotificationGroup) *Status {
+	if g.StatusID == "" {
+		return nil
+	}
+	for _, s := range n.Statuses {
+		if s.ID == g.StatusID {
+			return s
+		}
+	}
+	return nil
+}
+
+// Ways to return the accounts of grouped notifications.
+const (
+	ExpandAccountsFull           = "full"
+	ExpandAccountsPartialAvatars = "partial_avatars"
+)
+
+// GroupedNotificationsOptions filters and groups the notifications returned
+// by GetGroupedNotifications.
+type GroupedNotificationsOptions struct {
+	NotificationsOptions
+	// GroupedTypes are the types of notifications to group, which
+	// defaults to favourite, follow and reblog.
+	GroupedTypes []NotificationType
+	// ExpandAccounts is ExpandAccountsFull, the default, or
+	// ExpandAccountsPartialAvatars.
+	ExpandAccounts string
+	// IncludeFiltered includes the notifications filtered by the
+	// NotificationPolicy.
+	IncludeFiltered bool
+}
+
+func (o *GroupedNotificationsOptions) setValues(params url.Values) {
+	if o == nil {
+		return
+	}
+	o.NotificationsOptions.setValues(params)
+	for _, t := range o.GroupedTypes {
+		params.Add("grouped_types[]", string(t))
+	}
+	if o.ExpandAccounts != "" {
+		params.Set("expand_accounts", o.ExpandAccounts)
+	}
+	if o.IncludeFiltered {
+		params.Set("include_filtered", "true")
+	}
+}
+
+// GetGroupedNotifications returns the notifications grouped by the server
+// filtered by opts, which may be nil. Servers without grouped notifications,
+// like Pleroma, Akkoma and Mastodon before 4.3, return the notifications
+// ungrouped, each in its own group.
+func (c *Client) GetGroupedNotifications(ctx context.Context, opts *GroupedNotificationsOptions, pg *Pagination) (*GroupedNotifications, error) {
+	var notifications GroupedNotifications
+	params := url.Values{}
+	opts.setValues(params)
+	err := c.doAPI(ctx, http.MethodGet, "/api/v2/notifications", params, &notifications, pg)
+	if notFound(err) {
+		var o *NotificationsOptions
+		if opts != nil {
+			o = &opts.NotificationsOptions
+		}
+		ns, err := c.GetNotificationsWithOptions(ctx, o, pg)
+		if err != nil {
+			return nil, err
+		}
+		return ungroupedNotifications(ns), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notifications, nil
+}
+
+// ungroupedNotifications puts each notification of ns in its own group,
+// with the group key Mastodon gives to notifications it doesn't group.
+func ungroupedNotifications(ns []*Notification) *GroupedNotifications {
+	r := &GroupedNotifications{}
+	accounts := map[ID]bool{}
+	statuses := map[ID]bool{}
+	for _, n := range ns {
+		g := &NotificationGroup{
+			GroupKey:                 "ungrouped-" + string(n.ID),
+			NotificationsCount:       1,
+			Type:                     n.Type,
+			MostRecentNotificationID: n.ID,
+			PageMinID:                n.ID,
+			PageMaxID:                n.ID,
+			LatestPageNotificationAt: n.CreatedAt,
+			Report:                   n.Report,
+		}
+		if n.Account.ID != "" {
+			g.SampleAccountIDs = []ID{n.Account.ID}
+			if !accounts[n.Account.ID] {
+				accounts[n.Account.ID] = true
+				account := n.Account
+				r.Accounts = append(r.Accounts, &account)
+			}
+		}
+		if n.Status != nil {
+			g.StatusID = n.Status.ID
+			if !statuses[n.Status.ID] {
+				statuses[n.Status.ID] = true
+				r.Statuses = append(r.Statuses, n.Status)
+			}
+		}
+		r.NotificationGroups = append(r.NotificationGroups, g)
+	}
+	return r
+}
+
+// GetNotificationGroup returns the notification group groupKey.
+func (c *Client) GetNotificationGroup(ctx context.Context, groupKey string) (*GroupedNotifications, error) {
+	var notifications GroupedNotifications
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v2/notifications/%s", url.PathEscape(groupKey)), nil, &notifications, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &notifications, nil
+}
+
+// DismissNotificationGroup dismisses the notifications of the group
+// groupKey.
+func (c *Client) DismissNotificationGroup(ctx context.Context, groupKey string) error {
+	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v2/notifications/%s/dismiss", url.PathEscape(groupKey)), nil, nil, nil)
+}
+
+// GetNotificationGroupAccounts returns all the accounts of the notification
+// group groupKey.
+func (c *Client) GetNotificationGroupAccounts(ctx context.Context, groupKey string) ([]*Account, error) {
+	var accounts []*Account
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v2/notifications/%s/accounts", url.PathEscape(groupKey)), nil, &accounts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetGroupedNotificationsUnreadCount returns the number of unread
+// notification groups filtered by opts, which may be nil. The server caps
+// the count, at 100 by default.
+func (c *Client) GetGroupedNotificationsUnreadCount(ctx context.Context, opts *GroupedNotificationsOptions) (int64, error) {
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	params := url.Values{}
+	opts.setValues(params)
+	err := c.doAPI(ctx, http.MethodGet, "/api/v2/notifications/unread_count", params, &result, nil)
+	if err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
@@ -0,0 +1,159 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGroupedNotifications(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/notifications":
+			q := r.URL.Query()
+			if strings.Join(q["grouped_types[]"], ",") != "favourite,reblog" || q.Get("exclude_types[]") != "mention" || q.Get("expand_accounts") != "partial_avatars" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Link", `<http://example.com?max_id=33>; rel="next"`)
+			fmt.Fprintln(w, `{
+				"accounts": [{"id": "1", "acct": "foo"}],
+				"partial_accounts": [{"id": "2", "acct": "bar", "avatar": "https://example.com/bar.png"}],
+				"statuses": [{"id": "10", "content": "hi"}],
+				"notification_groups": [
+					{"group_key": "favourite-10-483", "notifications_count": 2, "type": "favourite", "most_recent_notification_id": 34, "page_min_id": "33", "page_max_id": "34", "sample_account_ids": ["1", "2"], "status_id": "10"},
+					{"group_key": "ungrouped-35", "notifications_count": 1, "type": "follow", "most_recent_notification_id": "35", "sample_account_ids": ["1"], "status_id": null}
+				]
+			}`)
+			return
+		case "/api/v2/notifications/ungrouped-35":
+			fmt.Fprintln(w, `{"accounts": [{"id": "1"}], "notification_groups": [{"group_key": "ungrouped-35", "type": "follow"}]}`)
+			return
+		case "/api/v2/notifications/ungrouped-35/dismiss":
+			if r.Method == http.MethodPost {
+				fmt.Fprintln(w, `{}`)
+				return
+			}
+		case "/api/v2/notifications/favourite-10-483/accounts":
+			fmt.Fprintln(w, `[{"id": "1"}, {"id": "2"}]`)
+			return
+		case "/api/v2/notifications/unread_count":
+			if r.URL.Query().Get("types[]") == "follow" {
+				fmt.Fprintln(w, `{"count": 4}`)
+				return
+			}
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	var pg Pagination
+	ns, err := client.GetGroupedNotifications(context.Background(), &GroupedNotificationsOptions{
+		NotificationsOptions: NotificationsOptions{ExcludeTypes: []NotificationType{NotificationTypeMention}},
+		GroupedTypes:         []NotificationType{NotificationTypeFavourite, NotificationTypeReblog},
+		ExpandAccounts:       ExpandAccountsPartialAvatars,
+	}, &pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if pg.MaxID != "33" {
+		t.Fatalf("want %q but %q", "33", pg.MaxID)
+	}
+	if len(ns.NotificationGroups) != 2 {
+		t.Fatalf("result should be two: %d", len(ns.NotificationGroups))
+	}
+	g := ns.NotificationGroups[0]
+	if g.Type != NotificationTypeFavourite || g.NotificationsCount != 2 || g.MostRecentNotificationID != "34" {
+		t.Fatalf("want %q but %q", "favourite", g.Type)
+	}
+	if s := ns.Status(g); s == nil || s.Content != "hi" {
+		t.Fatalf("want %q but %v", "hi", s)
+	}
+	if a := ns.Account(g.SampleAccountIDs[0]); a == nil || a.Acct != "foo" {
+		t.Fatalf("want %q but %v", "foo", a)
+	}
+	if ns.Account(g.SampleAccountIDs[1]) != nil || ns.PartialAccounts[0].Avatar != "https://example.com/bar.png" {
+		t.Fatalf("want partial account but %v", ns.PartialAccounts[0])
+	}
+	if s := ns.Status(ns.NotificationGroups[1]); s != nil {
+		t.Fatalf("want nil but %v", s)
+	}
+
+	n, err := client.GetNotificationGroup(context.Background(), "ungrouped-35")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if n.NotificationGroups[0].GroupKey != "ungrouped-35" {
+		t.Fatalf("want %q but %q", "ungrouped-35", n.NotificationGroups[0].GroupKey)
+	}
+	if err := client.DismissNotificationGroup(context.Background(), "ungrouped-35"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	accounts, err := client.GetNotificationGroupAccounts(context.Background(), "favourite-10-483")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("result should be two: %d", len(accounts))
+	}
+	count, err := client.GetGroupedNotificationsUnreadCount(context.Background(), &GroupedNotificationsOptions{
+		NotificationsOptions: NotificationsOptions{Types: []NotificationType{NotificationTypeFollow}},
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if count != 4 {
+		t.Fatalf("want %d but %d", 4, count)
+	}
+}
+
+func TestGroupedNotificationsFallback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/notifications" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("exclude_types[]") != "mention" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Link", `<http://example.com?max_id=AbC>; rel="next"`)
+		fmt.Fprintln(w, `[
+			{"id": "AbD", "type": "favourite", "account": {"id": "1", "acct": "foo"}, "status": {"id": "10", "content": "hi"}},
+			{"id": "AbC", "type": "follow", "account": {"id": "1", "acct": "foo"}}
+		]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	var pg Pagination
+	ns, err := client.GetGroupedNotifications(context.Background(), &GroupedNotificationsOptions{
+		NotificationsOptions: NotificationsOptions{ExcludeTypes: []NotificationType{NotificationTypeMention}},
+	}, &pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if pg.MaxID != "AbC" {
+		t.Fatalf("want %q but %q", "AbC", pg.MaxID)
+	}
+	if len(ns.NotificationGroups) != 2 || len(ns.Accounts) != 1 || len(ns.Statuses) != 1 {
+		t.Fatalf("unexpected notifications: %+v", ns)
+	}
+	g := ns.NotificationGroups[0]
+	if g.GroupKey != "ungrouped-AbD" || g.NotificationsCount != 1 || g.Type != NotificationTypeFavourite {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+	if s := ns.Status(g); s == nil || s.Content != "hi" {
+		t.Fatalf("want %q but %v", "hi", s)
+	}
+	if a := ns.Account(g.SampleAccountIDs[0]); a == nil || a.Acct != "foo" {
+		t.Fatalf("want %q but %v", "foo", a)
+	}
+	if ns.Status(ns.NotificationGroups[1]) != nil {
+		t.Fatalf("want no status but %v", ns.Status(ns.NotificationGroups[1]))
+	}
+}
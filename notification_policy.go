@@ -0,0 +1,78 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// NotificationPolicy holds which notifications the server filters into
+// notification requests, since Mastodon 4.3.
+type NotificationPolicy struct {
+	ForNotFollowing    NotificationPolicyAction  `json:"for_not_following"`
+	ForNotFollowers    NotificationPolicyAction  `json:"for_not_followers"`
+	ForNewAccounts     NotificationPolicyAction  `json:"for_new_accounts"`
+	ForPrivateMentions NotificationPolicyAction  `json:"for_private_mentions"`
+	ForLimitedAccounts NotificationPolicyAction  `json:"for_limited_accounts"`
+	Summary            NotificationPolicySummary `json:"summary"`
+}
+
+// NotificationPolicySummary counts the notifications filtered by a
+// NotificationPolicy.
+type NotificationPolicySummary struct {
+	PendingRequestsCount      int64 `json:"pending_requests_count"`
+	PendingNotificationsCount int64 `json:"pending_notifications_count"`
+}
+
+// NotificationPolicyUpdate holds the settings to change with
+// UpdateNotificationPolicy. Empty settings are left unchanged.
+type NotificationPolicyUpdate struct {
+	// ForNotFollowing applies to accounts the user doesn't follow.
+	ForNotFollowing NotificationPolicyAction
+	// ForNotFollowers applies to accounts not following the user.
+	ForNotFollowers NotificationPolicyAction
+	// ForNewAccounts applies to accounts created in the last 30 days.
+	ForNewAccounts NotificationPolicyAction
+	// ForPrivateMentions applies to unsolicited private mentions.
+	ForPrivateMentions NotificationPolicyAction
+	// ForLimitedAccounts applies to accounts limited by the moderators.
+	ForLimitedAccounts NotificationPolicyAction
+}
+
+// GetNotificationPolicy returns the notification filtering policy of the
+// user.
+func (c *Client) GetNotificationPolicy(ctx context.Context) (*NotificationPolicy, error) {
+	var policy NotificationPolicy
+	err := c.doAPI(ctx, http.MethodGet, "/api/v2/notifications/policy", nil, &policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateNotificationPolicy changes the notification filtering policy of the
+// user.
+func (c *Client) UpdateNotificationPolicy(ctx context.Context, update *NotificationPolicyUpdate) (*NotificationPolicy, error) {
+	params := url.Values{}
+	for _, s := range []struct {
+		name   string
+		action NotificationPolicyAction
+	}{
+		{"for_not_following", update.ForNotFollowing},
+		{"for_not_followers", update.ForNotFollowers},
+		{"for_new_accounts", update.ForNewAccounts},
+		{"for_private_mentions", update.ForPrivateMentions},
+		{"for_limited_accounts", update.ForLimitedAccounts},
+	} {
+		if s.action != "" {
+			params.Set(s.name, string(s.action))
+		}
+	}
+
+	var policy NotificationPolicy
+	err := c.doAPI(ctx, http.MethodPatch, "/api/v2/notifications/policy", params, &policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -0,0 +1,55 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNotificationPolicy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/notifications/policy" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		forNewAccounts := "accept"
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch:
+			if r.FormValue("for_not_following") != "" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			forNewAccounts = r.FormValue("for_new_accounts")
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintf(w, `{"for_not_following": "accept", "for_not_followers": "accept", "for_new_accounts": %q, "for_private_mentions": "filter", "for_limited_accounts": "drop", "summary": {"pending_requests_count": 2, "pending_notifications_count": 5}}`, forNewAccounts)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	policy, err := client.GetNotificationPolicy(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if policy.ForPrivateMentions != NotificationPolicyFilter || policy.ForLimitedAccounts != NotificationPolicyDrop {
+		t.Fatalf("want %q but %q", NotificationPolicyFilter, policy.ForPrivateMentions)
+	}
+	if policy.Summary.PendingRequestsCount != 2 || policy.Summary.PendingNotificationsCount != 5 {
+		t.Fatalf("want %d but %d", 2, policy.Summary.PendingRequestsCount)
+	}
+
+	policy, err = client.UpdateNotificationPolicy(context.Background(), &NotificationPolicyUpdate{
+		ForNewAccounts: NotificationPolicyFilter,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if policy.ForNewAccounts != NotificationPolicyFilter {
+		t.Fatalf("want %q but %q", NotificationPolicyFilter, policy.ForNewAccounts)
+	}
+}
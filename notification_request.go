@@ -0,0 +1,88 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NotificationRequest groups the notifications from an account filtered by
+// the NotificationPolicy, since Mastodon 4.3.
+type NotificationRequest struct {
+	ID        ID        `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Account   Account   `json:"account"`
+	// NotificationsCount is the number of filtered notifications.
+	NotificationsCount int64   `json:"notifications_count,string"`
+	LastStatus         *Status `json:"last_status"`
+}
+
+// GetNotificationRequests returns the notification requests of the user.
+func (c *Client) GetNotificationRequests(ctx context.Context, pg *Pagination) ([]*NotificationRequest, error) {
+	var requests []*NotificationRequest
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/notifications/requests", nil, &requests, pg)
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// GetNotificationRequest returns the notification request id.
+func (c *Client) GetNotificationRequest(ctx context.Context, id ID) (*NotificationRequest, error) {
+	var request NotificationRequest
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/notifications/requests/%s", url.PathEscape(string(id))), nil, &request, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// AcceptNotificationRequest accepts the notification request id, which
+// moves its notifications into the notifications of the user and stops
+// filtering the notifications of its account.
+func (c *Client) AcceptNotificationRequest(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/notifications/requests/%s/accept", url.PathEscape(string(id))), nil, nil, nil)
+}
+
+// DismissNotificationRequest dismisses the notification request id and its
+// notifications.
+func (c *Client) DismissNotificationRequest(ctx context.Context, id ID) error {
+	return c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/notifications/requests/%s/dismiss", url.PathEscape(string(id))), nil, nil, nil)
+}
+
+// AcceptNotificationRequests accepts the notification requests ids at once.
+func (c *Client) AcceptNotificationRequests(ctx context.Context, ids ...ID) error {
+	return c.doAPI(ctx, http.MethodPost, "/api/v1/notifications/requests/accept", notificationRequestIDs(ids), nil, nil)
+}
+
+// DismissNotificationRequests dismisses the notification requests ids at
+// once.
+func (c *Client) DismissNotificationRequests(ctx context.Context, ids ...ID) error {
+	return c.doAPI(ctx, http.MethodPost, "/api/v1/notifications/requests/dismiss", notificationRequestIDs(ids), nil, nil)
+}
+
+// GetNotificationRequestsMerged reports whether the notifications of the
+// accepted requests have been merged into the notifications of the user.
+// Accepting is done in the background, so notifications fetched before the
+// merge may lack them.
+func (c *Client) GetNotificationRequestsMerged(ctx context.Context) (bool, error) {
+	var result struct {
+		Merged bool `json:"merged"`
+	}
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/notifications/requests/merged", nil, &result, nil)
+	if err != nil {
+		return false, err
+	}
+	return result.Merged, nil
+}
+
+func notificationRequestIDs(ids []ID) url.Values {
+	params := url.Values{}
+	for _, id := range ids {
+		params.Add("id[]", string(id))
+	}
+	return params
+}
@@ -0,0 +1,96 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotificationRequests(t *testing.T) {
+	var accepted, dismissed []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/notifications/requests":
+			fmt.Fprintln(w, `[{"id": "112456967201894256", "account": {"acct": "foo"}, "notifications_count": "3", "last_status": {"content": "hi"}}, {"id": "2", "notifications_count": "1"}]`)
+			return
+		case "/api/v1/notifications/requests/2":
+			fmt.Fprintln(w, `{"id": "2", "notifications_count": "1"}`)
+			return
+		case "/api/v1/notifications/requests/2/accept", "/api/v1/notifications/requests/accept":
+			if r.Method != http.MethodPost {
+				break
+			}
+			r.ParseForm()
+			accepted = append(accepted, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/notifications/requests/"), "/accept"))
+			accepted = append(accepted, r.PostForm["id[]"]...)
+			fmt.Fprintln(w, `{}`)
+			return
+		case "/api/v1/notifications/requests/2/dismiss", "/api/v1/notifications/requests/dismiss":
+			if r.Method != http.MethodPost {
+				break
+			}
+			r.ParseForm()
+			dismissed = append(dismissed, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/notifications/requests/"), "/dismiss"))
+			dismissed = append(dismissed, r.PostForm["id[]"]...)
+			fmt.Fprintln(w, `{}`)
+			return
+		case "/api/v1/notifications/requests/merged":
+			fmt.Fprintln(w, `{"merged": true}`)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	rs, err := client.GetNotificationRequests(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(rs) != 2 {
+		t.Fatalf("result should be two: %d", len(rs))
+	}
+	if rs[0].NotificationsCount != 3 || rs[0].Account.Acct != "foo" || rs[0].LastStatus.Content != "hi" {
+		t.Fatalf("want %d but %d", 3, rs[0].NotificationsCount)
+	}
+	r, err := client.GetNotificationRequest(context.Background(), "2")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if r.ID != "2" {
+		t.Fatalf("want %q but %q", "2", r.ID)
+	}
+
+	if err := client.AcceptNotificationRequest(context.Background(), "2"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := client.AcceptNotificationRequests(context.Background(), "3", "4"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if got := strings.Join(accepted, ","); got != "2,accept,3,4" {
+		t.Fatalf("want %q but %q", "2,accept,3,4", got)
+	}
+	if err := client.DismissNotificationRequest(context.Background(), "2"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := client.DismissNotificationRequests(context.Background(), "5"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if got := strings.Join(dismissed, ","); got != "2,dismiss,5" {
+		t.Fatalf("want %q but %q", "2,dismiss,5", got)
+	}
+
+	merged, err := client.GetNotificationRequestsMerged(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !merged {
+		t.Fatalf("want %t but %t", true, merged)
+	}
+	if err := client.AcceptNotificationRequest(context.Background(), "9"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -15,12 +16,22 @@ func TestGetNotifications(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/api/v1/notifications":
-			if r.URL.Query().Get("exclude_types[]") == "follow" {
+			q := r.URL.Query()
+			if q.Get("account_id") == "42" && strings.Join(q["types[]"], ",") == "mention,poll" {
+				fmt.Fprintln(w, `[{"id": 42, "type": "mention"}]`)
+			} else if q.Get("exclude_types[]") == "follow" {
 				fmt.Fprintln(w, `[{"id": 321, "action_taken": true}]`)
 			} else {
 				fmt.Fprintln(w, `[{"id": 122, "action_taken": false}, {"id": 123, "action_taken": true}]`)
 			}
 			return
+		case "/api/v1/notifications/unread_count":
+			if r.URL.Query().Get("exclude_types[]") == "follow" {
+				fmt.Fprintln(w, `{"count": 7}`)
+			} else {
+				fmt.Fprintln(w, `{"count": 9}`)
+			}
+			return
 		case "/api/v1/notifications/123":
 			fmt.Fprintln(w, `{"id": 123, "action_taken": true}`)
 			return
@@ -64,6 +75,32 @@ func TestGetNotifications(t *testing.T) {
 	if nse[0].ID != "321" {
 		t.Fatalf("want %v but %v", "321", nse[0].ID)
 	}
+	nso, err := client.GetNotificationsWithOptions(context.Background(), &NotificationsOptions{
+		Types:     []NotificationType{NotificationTypeMention, NotificationTypePoll},
+		AccountID: "42",
+	}, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(nso) != 1 || nso[0].Type != NotificationTypeMention {
+		t.Fatalf("want %q but %v", NotificationTypeMention, nso)
+	}
+	count, err := client.GetNotificationsUnreadCount(context.Background(), &NotificationsOptions{
+		ExcludeTypes: []NotificationType{NotificationTypeFollow},
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if count != 7 {
+		t.Fatalf("want %d but %d", 7, count)
+	}
+	count, err = client.GetNotificationsUnreadCount(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if count != 9 {
+		t.Fatalf("want %d but %d", 9, count)
+	}
 	n, err := client.GetNotification(context.Background(), "123")
 	if err != nil {
 		t.Fatalf("should not be fail: %v", err)
@@ -81,6 +118,29 @@ func TestGetNotifications(t *testing.T) {
 	}
 }
 
+func TestNotificationNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error": "Record not found"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	for _, err := range []error{
+		func() error { _, err := client.GetNotification(context.Background(), "123"); return err }(),
+		client.DismissNotification(context.Background(), "123"),
+		client.ClearNotifications(context.Background()),
+	} {
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			t.Fatalf("want *APIError but %T: %v", err, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Record not found" {
+			t.Fatalf("want %d but %d: %q", http.StatusNotFound, apiErr.StatusCode, apiErr.Message)
+		}
+	}
+}
+
 func TestPushSubscription(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -155,3 +215,58 @@ func TestPushSubscription(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func TestPushSubscribe(t *testing.T) {
+	keys, err := GeneratePushKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/push/subscription" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			if r.PostFormValue("subscription[keys][p256dh]") != keys.P256dh() || r.PostFormValue("subscription[keys][auth]") != keys.AuthSecret() {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if r.PostFormValue("data[alerts][admin.sign_up]") != "true" || r.PostFormValue("data[alerts][favourite]") != "false" || r.PostFormValue("data[policy]") != "followed" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `{"id": "1", "endpoint": "https://example.org", "alerts": {"admin.sign_up": true, "favourite": false}, "policy": "followed"}`)
+		case http.MethodPut:
+			if r.PostFormValue("data[policy]") != "none" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `{"id": "1", "endpoint": "https://example.org", "alerts": {}, "policy": "none"}`)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	if len(keys.Auth) != 16 {
+		t.Fatalf("want %v but %v", 16, len(keys.Auth))
+	}
+	enabled, disabled := Sbool(true), Sbool(false)
+	sub, err := client.PushSubscribe(context.Background(), "https://example.org", keys, &PushAlerts{AdminSignUp: &enabled, Favourite: &disabled}, PushPolicyFollowed)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if sub.Policy != PushPolicyFollowed || !bool(*sub.Alerts.AdminSignUp) {
+		t.Fatalf("unexpected subscription: %+v", sub)
+	}
+	sub, err = client.UpdatePushSubscriptionPolicy(context.Background(), PushPolicyNone)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if sub.Policy != PushPolicyNone {
+		t.Fatalf("want %q but %q", PushPolicyNone, sub.Policy)
+	}
+}
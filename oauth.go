@@ -0,0 +1,171 @@
+package mastodon
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// PKCE holds a Proof Key for Code Exchange (RFC 7636), which keeps an
+// intercepted authorization code from being exchanged for a token. Send
+// the challenge with the authorization request and the verifier with
+// AuthenticateTokenPKCE.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	// Method is the challenge method, always "S256".
+	Method string
+}
+
+// NewPKCE generates a random verifier and its S256 challenge.
+func NewPKCE() (*PKCE, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(verifier))
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Method:    "S256",
+	}, nil
+}
+
+// AuthURI adds the code challenge to an authorization URL, such as
+// Application.AuthURI.
+func (p *PKCE) AuthURI(authURI string) (string, error) {
+	u, err := url.Parse(authURI)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("code_challenge", p.Challenge)
+	q.Set("code_challenge_method", p.Method)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// OAuth scopes. The top-level scopes read, write and follow include all the
+// more specific scopes below them.
+const (
+	ScopeRead    = "read"
+	ScopeWrite   = "write"
+	ScopeFollow  = "follow"
+	ScopePush    = "push"
+	ScopeProfile = "profile"
+
+	ScopeReadAccounts      = "read:accounts"
+	ScopeReadBlocks        = "read:blocks"
+	ScopeReadBookmarks     = "read:bookmarks"
+	ScopeReadFavourites    = "read:favourites"
+	ScopeReadFilters       = "read:filters"
+	ScopeReadFollows       = "read:follows"
+	ScopeReadLists         = "read:lists"
+	ScopeReadMutes         = "read:mutes"
+	ScopeReadNotifications = "read:notifications"
+	ScopeReadSearch        = "read:search"
+	ScopeReadStatuses      = "read:statuses"
+
+	ScopeWriteAccounts      = "write:accounts"
+	ScopeWriteBlocks        = "write:blocks"
+	ScopeWriteBookmarks     = "write:bookmarks"
+	ScopeWriteConversations = "write:conversations"
+	ScopeWriteFavourites    = "write:favourites"
+	ScopeWriteFilters       = "write:filters"
+	ScopeWriteFollows       = "write:follows"
+	ScopeWriteLists         = "write:lists"
+	ScopeWriteMedia         = "write:media"
+	ScopeWriteMutes         = "write:mutes"
+	ScopeWriteNotifications = "write:notifications"
+	ScopeWriteReports       = "write:reports"
+	ScopeWriteStatuses      = "write:statuses"
+
+	ScopeAdminRead                     = "admin:read"
+	ScopeAdminReadAccounts             = "admin:read:accounts"
+	ScopeAdminReadReports              = "admin:read:reports"
+	ScopeAdminReadDomainAllows         = "admin:read:domain_allows"
+	ScopeAdminReadDomainBlocks         = "admin:read:domain_blocks"
+	ScopeAdminReadIPBlocks             = "admin:read:ip_blocks"
+	ScopeAdminReadEmailDomainBlocks    = "admin:read:email_domain_blocks"
+	ScopeAdminReadCanonicalEmailBlocks = "admin:read:canonical_email_blocks"
+
+	ScopeAdminWrite                     = "admin:write"
+	ScopeAdminWriteAccounts             = "admin:write:accounts"
+	ScopeAdminWriteReports              = "admin:write:reports"
+	ScopeAdminWriteDomainAllows         = "admin:write:domain_allows"
+	ScopeAdminWriteDomainBlocks         = "admin:write:domain_blocks"
+	ScopeAdminWriteIPBlocks             = "admin:write:ip_blocks"
+	ScopeAdminWriteEmailDomainBlocks    = "admin:write:email_domain_blocks"
+	ScopeAdminWriteCanonicalEmailBlocks = "admin:write:canonical_email_blocks"
+)
+
+// JoinScopes joins scopes into the space-separated list used by
+// AppConfig.Scopes.
+func JoinScopes(scopes ...string) string {
+	return strings.Join(scopes, " ")
+}
+
+// AuthorizeOptions holds the options of AuthorizeURL.
+type AuthorizeOptions struct {
+	// RedirectURI defaults to urn:ietf:wg:oauth:2.0:oob, which shows the
+	// authorization code to the user instead of redirecting.
+	RedirectURI string
+	// Scopes defaults to the scopes the application was registered with.
+	Scopes []string
+	// ForceLogin asks for the login form even if the user is logged in, to
+	// authorize another account.
+	ForceLogin bool
+	// Lang is the language of the authorization page, an ISO 639-1 code.
+	Lang string
+	// State is returned unchanged with the redirect.
+	State string
+	// PKCE adds a code challenge when set.
+	PKCE *PKCE
+}
+
+// AuthorizeURL returns the URL of the authorization page of the server of
+// config for its ClientID. Once the user authorizes the application, use
+// the code with AuthenticateToken, or AuthenticateTokenPKCE when opts has
+// a PKCE.
+func AuthorizeURL(config *Config, opts *AuthorizeOptions) (string, error) {
+	if opts == nil {
+		opts = &AuthorizeOptions{}
+	}
+	u, err := url.Parse(config.Server)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, "/oauth/authorize")
+
+	redirectURI := opts.RedirectURI
+	if redirectURI == "" {
+		redirectURI = "urn:ietf:wg:oauth:2.0:oob"
+	}
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {config.ClientID},
+		"redirect_uri":  {redirectURI},
+	}
+	if len(opts.Scopes) > 0 {
+		params.Set("scope", JoinScopes(opts.Scopes...))
+	}
+	if opts.ForceLogin {
+		params.Set("force_login", "true")
+	}
+	if opts.Lang != "" {
+		params.Set("lang", opts.Lang)
+	}
+	if opts.State != "" {
+		params.Set("state", opts.State)
+	}
+	if opts.PKCE != nil {
+		params.Set("code_challenge", opts.PKCE.Challenge)
+		params.Set("code_challenge_method", opts.PKCE.Method)
+	}
+	u.RawQuery = params.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,105 @@
+package mastodon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPKCE(t *testing.T) {
+	p, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(p.Verifier) != 43 {
+		t.Fatalf("want %v but %v", 43, len(p.Verifier))
+	}
+	sum := sha256.Sum256([]byte(p.Verifier))
+	if p.Challenge != base64.RawURLEncoding.EncodeToString(sum[:]) || p.Method != "S256" {
+		t.Fatalf("unexpected challenge: %+v", p)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" || r.FormValue("code") != "abc" || r.FormValue("code_verifier") != p.Verifier {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, `{"access_token": "zoo"}`)
+	}))
+	defer ts.Close()
+
+	authURI, err := p.AuthURI(ts.URL + "/oauth/authorize?client_id=foo&response_type=code")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	u, err := url.Parse(authURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "foo" || q.Get("code_challenge") != p.Challenge || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected auth URI: %s", authURI)
+	}
+
+	client := NewClient(&Config{Server: ts.URL, ClientID: "foo"})
+	err = client.AuthenticateTokenPKCE(context.Background(), "abc", "urn:ietf:wg:oauth:2.0:oob", "wrong")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	err = client.AuthenticateTokenPKCE(context.Background(), "abc", "urn:ietf:wg:oauth:2.0:oob", p.Verifier)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if client.Config.AccessToken != "zoo" {
+		t.Fatalf("want %q but %q", "zoo", client.Config.AccessToken)
+	}
+}
+
+func TestAuthorizeURL(t *testing.T) {
+	config := &Config{Server: "https://example.com/", ClientID: "foo"}
+	s, err := AuthorizeURL(config, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := "https://example.com/oauth/authorize?client_id=foo&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code"
+	if s != want {
+		t.Fatalf("want %q but %q", want, s)
+	}
+
+	p := &PKCE{Challenge: "challenge", Method: "S256"}
+	s, err = AuthorizeURL(config, &AuthorizeOptions{
+		RedirectURI: "https://app.example/callback",
+		Scopes:      []string{ScopeRead, ScopeWriteStatuses, ScopeAdminReadReports},
+		ForceLogin:  true,
+		Lang:        "ja",
+		State:       "xyz",
+		PKCE:        p,
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("scope") != "read write:statuses admin:read:reports" {
+		t.Fatalf("unexpected scope: %q", q.Get("scope"))
+	}
+	if q.Get("redirect_uri") != "https://app.example/callback" || q.Get("force_login") != "true" || q.Get("lang") != "ja" || q.Get("state") != "xyz" {
+		t.Fatalf("unexpected URL: %s", s)
+	}
+	if q.Get("code_challenge") != "challenge" || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected URL: %s", s)
+	}
+
+	_, err = AuthorizeURL(&Config{Server: ":"}, nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
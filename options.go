@@ -0,0 +1,50 @@
+package mastodon
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created with NewClient.
+type Option func(*Client)
+
+// WithHTTPClient makes the client send requests like hc, with its transport,
+// timeout, cookie jar and redirect policy. A nil hc is ignored.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.Client = *hc
+		}
+	}
+}
+
+// WithTransport makes the client send requests with rt, for example to use
+// a proxy or custom TLS settings.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.Transport = rt
+	}
+}
+
+// WithTimeout limits the time of each request, including reading the
+// response body. Streams are long-lived requests and should be read with a
+// client without timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = d
+	}
+}
+
+// WithBaseURL makes the client send requests to the server at u, overriding
+// Config.Server. The client gets its own copy of the Config, so other
+// clients sharing it are left unchanged.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		var config Config
+		if c.Config != nil {
+			config = *c.Config
+		}
+		config.Server = u
+		c.Config = &config
+	}
+}
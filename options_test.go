@@ -0,0 +1,64 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"title": "mastodon"}`)
+	}))
+	defer ts.Close()
+
+	requests := 0
+	transport := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	config := &Config{Server: "https://example.com"}
+	client := NewClient(config,
+		WithTimeout(time.Minute),
+		WithTransport(transport),
+		WithBaseURL(ts.URL),
+	)
+	if config.Server != "https://example.com" {
+		t.Fatalf("shared config should not be changed: %q", config.Server)
+	}
+	if client.Timeout != time.Minute {
+		t.Fatalf("want %v but %v", time.Minute, client.Timeout)
+	}
+	ins, err := client.GetInstance(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if ins.Title != "mastodon" {
+		t.Fatalf("want %q but %q", "mastodon", ins.Title)
+	}
+	if requests != 1 {
+		t.Fatalf("request should be sent with the transport: %d", requests)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	hc := &http.Client{Timeout: time.Second}
+	client := NewClient(&Config{}, WithHTTPClient(hc), WithTimeout(time.Minute))
+	if client.Timeout != time.Minute {
+		t.Fatalf("want %v but %v", time.Minute, client.Timeout)
+	}
+	if hc.Timeout != time.Second {
+		t.Fatalf("http client should not be changed: %v", hc.Timeout)
+	}
+
+	client = NewClient(nil, WithHTTPClient(nil), WithBaseURL("https://example.com"))
+	if client.Config.Server != "https://example.com" {
+		t.Fatalf("want %q but %q", "https://example.com", client.Config.Server)
+	}
+	if client.Timeout != http.DefaultClient.Timeout {
+		t.Fatalf("want %v but %v", http.DefaultClient.Timeout, client.Timeout)
+	}
+}
@@ -31,7 +31,7 @@ type PollOption struct {
 // GetPoll returns poll specified by id.
 func (c *Client) GetPoll(ctx context.Context, id ID) (*Poll, error) {
 	var poll Poll
-	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/polls/%s", id), nil, &poll, nil)
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/polls/%s", url.PathEscape(string(id))), nil, &poll, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -52,3 +52,81 @@ func (c *Client) PollVote(ctx context.Context, id ID, choices ...int) (*Poll, er
 	}
 	return &poll, nil
 }
+
+// PollResults holds the results of a poll normalized for display.
+type PollResults struct {
+	// Hidden is true when the poll hides its totals until it ends. Votes,
+	// percentages and winners are not available then.
+	Hidden   bool
+	Expired  bool
+	Multiple bool
+
+	// Votes is the number of votes cast. For multiple-choice polls one voter
+	// can cast several votes, so Voters may be less than Votes.
+	Votes  int64
+	Voters int64
+
+	Options []PollOptionResult
+
+	// Winners holds the indexes of the options with the most votes. It has
+	// more than one element on a tie and is empty if there are no votes.
+	Winners []int
+}
+
+// PollOptionResult holds the result of a single poll option.
+type PollOptionResult struct {
+	Title string
+	Votes int64
+	// Percent is the share of voters that chose the option, from 0 to 100.
+	// The percentages of a multiple-choice poll may add up to more than 100.
+	Percent float64
+	Winner  bool
+	OwnVote bool
+}
+
+// Results returns the results of the poll normalized for display.
+func (p *Poll) Results() *PollResults {
+	r := &PollResults{
+		Expired:  p.Expired,
+		Multiple: p.Multiple,
+		Votes:    p.VotesCount,
+		Voters:   p.VotersCount,
+		Options:  make([]PollOptionResult, len(p.Options)),
+	}
+	// voters_count is only sent for multiple-choice polls.
+	if r.Voters == 0 || !p.Multiple {
+		r.Voters = r.Votes
+	}
+
+	var sum, max int64
+	for i, o := range p.Options {
+		r.Options[i] = PollOptionResult{Title: o.Title, Votes: o.VotesCount}
+		sum += o.VotesCount
+		if o.VotesCount > max {
+			max = o.VotesCount
+		}
+	}
+	for _, i := range p.OwnVotes {
+		if i >= 0 && i < len(r.Options) {
+			r.Options[i].OwnVote = true
+		}
+	}
+
+	// Polls with hidden totals send null for the option counts until they
+	// end, while the poll still reports the number of votes.
+	if sum == 0 && p.VotesCount > 0 && !p.Expired {
+		r.Hidden = true
+		return r
+	}
+
+	for i := range r.Options {
+		if r.Voters > 0 {
+			r.Options[i].Percent = float64(r.Options[i].Votes) * 100 / float64(r.Voters)
+		}
+		if max > 0 && r.Options[i].Votes == max {
+			r.Options[i].Winner = true
+			r.Winners = append(r.Winners, i)
+		}
+	}
+	return r
+}
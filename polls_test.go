@@ -92,6 +92,10 @@ func TestPollVote(t *testing.T) {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusMethodNotAllowed)
 			return
 		}
+		if r.FormValue("choices[]") != "1" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
 		fmt.Fprintln(w, `{"id": "1234567", "expires_at": "2019-12-05T04:05:08.302Z", "expired": false, "multiple": false, "votes_count": 10, "voters_count": null, "voted": true, "own_votes": [1], "options": [{"title": "accept", "votes_count": 6}, {"title": "deny", "votes_count": 4}], "emojis":[]}`)
 	}))
 	defer ts.Close()
@@ -143,3 +147,65 @@ func TestPollVote(t *testing.T) {
 		t.Fatalf("want %q but %q", 4, poll.Options[1].VotesCount)
 	}
 }
+
+func TestPollResults(t *testing.T) {
+	p := &Poll{
+		VotesCount: 4,
+		Options:    []PollOption{{"foo", 1}, {"bar", 3}},
+		OwnVotes:   []int{1},
+	}
+	r := p.Results()
+	if r.Hidden {
+		t.Fatal("results should not be hidden")
+	}
+	if r.Voters != 4 {
+		t.Fatalf("want %d but %d", 4, r.Voters)
+	}
+	if r.Options[0].Percent != 25 || r.Options[1].Percent != 75 {
+		t.Fatalf("unexpected percentages: %v", r.Options)
+	}
+	if len(r.Winners) != 1 || r.Winners[0] != 1 || !r.Options[1].Winner || r.Options[0].Winner {
+		t.Fatalf("unexpected winners: %v", r.Winners)
+	}
+	if !r.Options[1].OwnVote || r.Options[0].OwnVote {
+		t.Fatalf("unexpected own votes: %v", r.Options)
+	}
+
+	p = &Poll{
+		Multiple:    true,
+		VotesCount:  6,
+		VotersCount: 4,
+		Options:     []PollOption{{"foo", 2}, {"bar", 2}, {"baz", 2}},
+	}
+	r = p.Results()
+	if r.Voters != 4 || r.Votes != 6 {
+		t.Fatalf("unexpected counts: %d voters, %d votes", r.Voters, r.Votes)
+	}
+	if r.Options[0].Percent != 50 {
+		t.Fatalf("want %v but %v", 50.0, r.Options[0].Percent)
+	}
+	if len(r.Winners) != 3 {
+		t.Fatalf("result should be three: %d", len(r.Winners))
+	}
+
+	p = &Poll{
+		VotesCount: 5,
+		Options:    []PollOption{{"foo", 0}, {"bar", 0}},
+	}
+	r = p.Results()
+	if !r.Hidden {
+		t.Fatal("results should be hidden")
+	}
+	if len(r.Winners) != 0 || r.Options[0].Percent != 0 {
+		t.Fatalf("hidden results should have no winners or percentages: %+v", r)
+	}
+
+	p = &Poll{
+		Expired: true,
+		Options: []PollOption{{"foo", 0}, {"bar", 0}},
+	}
+	r = p.Results()
+	if r.Hidden || len(r.Winners) != 0 {
+		t.Fatalf("unexpected results: %+v", r)
+	}
+}
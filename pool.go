@@ -0,0 +1,191 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ClientPool holds the clients of several accounts, possibly on different
+// servers, by their address "user@domain". It is safe for concurrent use.
+type ClientPool struct {
+	opts []Option
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewClientPool returns an empty pool creating its clients with opts.
+func NewClientPool(opts ...Option) *ClientPool {
+	return &ClientPool{opts: opts, clients: map[string]*Client{}}
+}
+
+// normalizeAcct returns acct, like "@user@domain" or "user@domain", in
+// lower case and without the leading @.
+func normalizeAcct(acct string) (string, error) {
+	acct = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(acct), "@"))
+	i := strings.Index(acct, "@")
+	if i <= 0 || i == len(acct)-1 || strings.Contains(acct[i+1:], "@") {
+		return "", fmt.Errorf("invalid account address %q, want user@domain", acct)
+	}
+	return acct, nil
+}
+
+// Add adds a client for the account acct, "user@domain", with config,
+// replacing the client of the account if there's one already.
+func (p *ClientPool) Add(acct string, config *Config) (*Client, error) {
+	acct, err := normalizeAcct(acct)
+	if err != nil {
+		return nil, err
+	}
+	c := NewClient(config, p.opts...)
+	p.mu.Lock()
+	p.clients[acct] = c
+	p.mu.Unlock()
+	return c, nil
+}
+
+// AddConfig adds a client with config for the account of its access token,
+// found with verify_credentials, and returns the address of the account.
+// The domain of the address is the host of Config.Server; use Add for
+// servers whose accounts use another domain.
+func (p *ClientPool) AddConfig(ctx context.Context, config *Config) (string, *Client, error) {
+	u, err := url.Parse(config.Server)
+	if err != nil {
+		return "", nil, err
+	}
+	if u.Host == "" {
+		return "", nil, fmt.Errorf("invalid server %q", config.Server)
+	}
+	c := NewClient(config, p.opts...)
+	account, err := c.GetAccountCurrentUser(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	acct, err := normalizeAcct(account.Username + "@" + u.Host)
+	if err != nil {
+		return "", nil, err
+	}
+	p.mu.Lock()
+	p.clients[acct] = c
+	p.mu.Unlock()
+	return acct, c, nil
+}
+
+// Get returns the client of the account acct.
+func (p *ClientPool) Get(acct string) (*Client, bool) {
+	acct, err := normalizeAcct(acct)
+	if err != nil {
+		return nil, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	c, ok := p.clients[acct]
+	return c, ok
+}
+
+// Remove removes the client of the account acct.
+func (p *ClientPool) Remove(acct string) {
+	acct, err := normalizeAcct(acct)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	delete(p.clients, acct)
+	p.mu.Unlock()
+}
+
+// Accts returns the sorted addresses of the accounts of the pool.
+func (p *ClientPool) Accts() []string {
+	p.mu.RLock()
+	accts := make([]string, 0, len(p.clients))
+	for acct := range p.clients {
+		accts = append(accts, acct)
+	}
+	p.mu.RUnlock()
+	sort.Strings(accts)
+	return accts
+}
+
+// PoolError holds the errors of the accounts for which a fan-out call of a
+// ClientPool failed, by account address.
+type PoolError map[string]error
+
+func (e PoolError) Error() string {
+	accts := make([]string, 0, len(e))
+	for acct := range e {
+		accts = append(accts, acct)
+	}
+	sort.Strings(accts)
+	msgs := make([]string, len(accts))
+	for i, acct := range accts {
+		msgs[i] = acct + ": " + e[acct].Error()
+	}
+	return fmt.Sprintf("%d accounts failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Each calls fn concurrently with the client of each account of accts, or
+// of every account of the pool when accts is empty. The error is a
+// PoolError holding the accounts for which fn failed, or which aren't in
+// the pool.
+func (p *ClientPool) Each(ctx context.Context, fn func(ctx context.Context, acct string, c *Client) error, accts ...string) error {
+	if len(accts) == 0 {
+		accts = p.Accts()
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   = PoolError{}
+		failed = func(acct string, err error) {
+			mu.Lock()
+			errs[acct] = err
+			mu.Unlock()
+		}
+	)
+	for _, acct := range accts {
+		if n, err := normalizeAcct(acct); err == nil {
+			acct = n
+		}
+		c, ok := p.Get(acct)
+		if !ok {
+			failed(acct, fmt.Errorf("no client for %s", acct))
+			continue
+		}
+		wg.Add(1)
+		go func(acct string, c *Client) {
+			defer wg.Done()
+			if err := fn(ctx, acct, c); err != nil {
+				failed(acct, err)
+			}
+		}(acct, c)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CrossPost posts toot with each account of accts, or with every account
+// of the pool when accts is empty, and returns the posted statuses by
+// account address. Statuses posted before an error are kept; the error is
+// a PoolError holding the accounts which failed. IDs are specific to a
+// server, so toot shouldn't have media or reply to a status.
+func (p *ClientPool) CrossPost(ctx context.Context, toot *Toot, accts ...string) (map[string]*Status, error) {
+	var mu sync.Mutex
+	statuses := map[string]*Status{}
+	err := p.Each(ctx, func(ctx context.Context, acct string, c *Client) error {
+		st, err := c.PostStatus(ctx, toot)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		statuses[acct] = st
+		mu.Unlock()
+		return nil
+	}, accts...)
+	return statuses, err
+}
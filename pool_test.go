@@ -0,0 +1,89 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newPoolServer(username string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+username {
+			http.Error(w, `{"error":"The access token is invalid"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/accounts/verify_credentials":
+			fmt.Fprintf(w, `{"id": "1", "username": %q, "acct": %q}`, username, username)
+		case "/api/v1/statuses":
+			fmt.Fprintf(w, `{"id": "2", "content": %q, "account": {"username": %q}}`, r.FormValue("status"), username)
+		default:
+			http.Error(w, `{"error":"Record not found"}`, http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClientPool(t *testing.T) {
+	alice := newPoolServer("alice")
+	defer alice.Close()
+	bob := newPoolServer("bob")
+	defer bob.Close()
+	ctx := context.Background()
+
+	p := NewClientPool()
+	acct, _, err := p.AddConfig(ctx, &Config{Server: alice.URL, AccessToken: "alice"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	u, _ := url.Parse(alice.URL)
+	if acct != "alice@"+u.Host {
+		t.Fatalf("want %q but %q", "alice@"+u.Host, acct)
+	}
+	if _, err := p.Add("@Bob@Example.com", &Config{Server: bob.URL, AccessToken: "bob"}); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if _, _, err := p.AddConfig(ctx, &Config{Server: bob.URL, AccessToken: "wrong"}); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	for _, acct := range []string{"bob", "@example.com", "bob@", "bob@example.com@x"} {
+		if _, err := p.Add(acct, &Config{}); err == nil {
+			t.Fatalf("should be fail: %q", acct)
+		}
+	}
+
+	if c, ok := p.Get("bob@example.com"); !ok || c.Config.Server != bob.URL {
+		t.Fatalf("unexpected client of bob: %v", c)
+	}
+	if accts := p.Accts(); len(accts) != 2 || accts[1] != "bob@example.com" {
+		t.Fatalf("unexpected accounts: %v", accts)
+	}
+
+	statuses, err := p.CrossPost(ctx, &Toot{Status: "hello"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(statuses) != 2 || statuses["bob@example.com"].Account.Username != "bob" || statuses[acct].Content != "hello" {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+
+	if _, err := p.Add("carol@example.com", &Config{Server: bob.URL, AccessToken: "carol"}); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	statuses, err = p.CrossPost(ctx, &Toot{Status: "again"}, "BOB@example.com", "carol@example.com", "dave@example.com")
+	var poolErr PoolError
+	if !errors.As(err, &poolErr) || len(poolErr) != 2 || poolErr["carol@example.com"] == nil || poolErr["dave@example.com"] == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 1 || statuses["bob@example.com"] == nil {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+
+	p.Remove("carol@example.com")
+	if _, ok := p.Get("carol@example.com"); ok {
+		t.Fatal("carol should be removed")
+	}
+}
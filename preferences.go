@@ -0,0 +1,25 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+)
+
+// Preferences holds the preferences of the current user.
+type Preferences struct {
+	PostingDefaultVisibility string `json:"posting:default:visibility"`
+	PostingDefaultSensitive  bool   `json:"posting:default:sensitive"`
+	PostingDefaultLanguage   string `json:"posting:default:language"`
+	ReadingExpandMedia       string `json:"reading:expand:media"`
+	ReadingExpandSpoilers    bool   `json:"reading:expand:spoilers"`
+}
+
+// GetPreferences returns the preferences of the current user.
+func (c *Client) GetPreferences(ctx context.Context) (*Preferences, error) {
+	var preferences Preferences
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/preferences", nil, &preferences, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
@@ -0,0 +1,32 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPreferences(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/preferences" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"posting:default:visibility": "unlisted", "posting:default:sensitive": true, "posting:default:language": null, "reading:expand:media": "show_all", "reading:expand:spoilers": true}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	preferences, err := client.GetPreferences(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if preferences.PostingDefaultVisibility != "unlisted" || !preferences.PostingDefaultSensitive || preferences.PostingDefaultLanguage != "" {
+		t.Fatalf("unexpected preferences: %+v", preferences)
+	}
+	if preferences.ReadingExpandMedia != "show_all" || !preferences.ReadingExpandSpoilers {
+		t.Fatalf("unexpected preferences: %+v", preferences)
+	}
+}
@@ -0,0 +1,115 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"text/template"
+)
+
+// FieldTemplate holds templates for the name and value of a profile field.
+type FieldTemplate struct {
+	Name  string
+	Value string
+}
+
+// ProfileData is the data passed to ProfileSync templates.
+type ProfileData struct {
+	// Account is the current user as returned by verify_credentials.
+	Account *Account
+	// Vars holds the variables passed to ProfileSync.Sync.
+	Vars map[string]interface{}
+}
+
+// ProfileSync renders the profile of the current user from templates and
+// updates it when the rendered content differs from the current profile.
+//
+// Templates use text/template syntax with ProfileData as data, for example
+// "{{.Account.StatusesCount}} posts, working on {{.Vars.project}}".
+// An empty Note leaves the note untouched and nil Fields leaves the profile
+// fields untouched.
+type ProfileSync struct {
+	Client *Client
+	Note   string
+	Fields []FieldTemplate
+}
+
+// Sync renders the templates with vars and updates the profile if needed.
+// It reports whether the profile was updated.
+func (p *ProfileSync) Sync(ctx context.Context, vars map[string]interface{}) (bool, error) {
+	account, err := p.Client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	data := &ProfileData{Account: account, Vars: vars}
+
+	var profile Profile
+	changed := false
+	if p.Note != "" {
+		note, err := renderProfileTemplate("note", p.Note, data)
+		if err != nil {
+			return false, err
+		}
+		current := account.Note
+		if account.Source != nil && account.Source.Note != nil {
+			current = *account.Source.Note
+		}
+		if note != current {
+			profile.Note = &note
+			changed = true
+		}
+	}
+	if p.Fields != nil {
+		fields := make([]Field, len(p.Fields))
+		for i, ft := range p.Fields {
+			name, err := renderProfileTemplate("field name", ft.Name, data)
+			if err != nil {
+				return false, err
+			}
+			value, err := renderProfileTemplate("field value", ft.Value, data)
+			if err != nil {
+				return false, err
+			}
+			fields[i] = Field{Name: name, Value: value}
+		}
+		current := account.Fields
+		if account.Source != nil && account.Source.Fields != nil {
+			current = *account.Source.Fields
+		}
+		if !sameFields(fields, current) {
+			profile.Fields = &fields
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if _, err := p.Client.AccountUpdate(ctx, &profile); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func renderProfileTemplate(name, text string, data *ProfileData) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func sameFields(a, b []Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
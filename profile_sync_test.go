@@ -0,0 +1,56 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProfileSync(t *testing.T) {
+	var updates []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/verify_credentials":
+			fmt.Fprintln(w, `{"id": "1", "statuses_count": 42, "note": "<p>42 posts</p>", "source": {"note": "42 posts", "fields": [{"name": "Project", "value": "go-mastodon"}]}}`)
+		case "/api/v1/accounts/update_credentials":
+			r.ParseForm()
+			updates = append(updates, r.Form.Encode())
+			fmt.Fprintln(w, `{"id": "1"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	p := &ProfileSync{
+		Client: NewClient(&Config{Server: ts.URL}),
+		Note:   "{{.Account.StatusesCount}} posts",
+		Fields: []FieldTemplate{{Name: "Project", Value: "{{.Vars.project}}"}},
+	}
+	updated, err := p.Sync(context.Background(), map[string]interface{}{"project": "go-mastodon"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if updated || len(updates) != 0 {
+		t.Fatalf("profile should not be updated: %v", updates)
+	}
+
+	updated, err = p.Sync(context.Background(), map[string]interface{}{"project": "tut"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !updated || len(updates) != 1 {
+		t.Fatalf("profile should be updated once: %v", updates)
+	}
+	want := "fields_attributes%5B0%5D%5Bname%5D=Project&fields_attributes%5B0%5D%5Bvalue%5D=tut"
+	if updates[0] != want {
+		t.Fatalf("want %q but %q", want, updates[0])
+	}
+
+	_, err = p.Sync(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package mastodon
+
+import (
+	"io"
+	"net/http"
+)
+
+// ProgressFunc is called with the number of bytes sent so far out of
+// total while uploading. Total is -1 if unknown. A retried upload starts
+// over from zero.
+type ProgressFunc func(sent, total int64)
+
+type progressReader struct {
+	io.ReadCloser
+	sent, total int64
+	progress    ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// trackProgress makes the body of req call progress as it is read.
+func trackProgress(req *http.Request, progress ProgressFunc) {
+	total := req.ContentLength
+	if total == 0 {
+		total = -1
+	}
+	if req.Body != nil {
+		req.Body = &progressReader{ReadCloser: req.Body, total: total, progress: progress}
+	}
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return &progressReader{ReadCloser: body, total: total, progress: progress}, nil
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUploadMediaProgress(t *testing.T) {
+	var length int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("should not be fail: %v", err)
+		}
+		length = int64(len(body))
+		fmt.Fprintln(w, `{"id": "123"}`)
+	}))
+	defer ts.Close()
+
+	var sent, total int64
+	calls := 0
+	progress := func(s, t int64) {
+		calls++
+		sent, total = s, t
+	}
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	file := bytes.Repeat([]byte("x"), 1<<20)
+	a, err := client.UploadMediaFromReaderWithProgress(context.Background(), bytes.NewReader(file), progress)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if a.ID != "123" {
+		t.Fatalf("want %q but %q", "123", a.ID)
+	}
+	if calls < 2 {
+		t.Fatalf("progress should be called more than once: %d", calls)
+	}
+	if sent != length || total != length {
+		t.Fatalf("want %d/%d but %d/%d", length, length, sent, total)
+	}
+}
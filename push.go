@@ -0,0 +1,235 @@
+package mastodon
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// PushNotification holds the payload of a Web Push message sent by
+// Mastodon.
+type PushNotification struct {
+	AccessToken      string           `json:"access_token"`
+	PreferredLocale  string           `json:"preferred_locale"`
+	NotificationID   ID               `json:"notification_id"`
+	NotificationType NotificationType `json:"notification_type"`
+	Icon             string           `json:"icon"`
+	Title            string           `json:"title"`
+	Body             string           `json:"body"`
+}
+
+// Decrypt decrypts the Web Push message of r, as received by the push
+// endpoint, and decodes the notification.
+func (k *PushKeys) Decrypt(r *http.Request) (*PushNotification, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := k.DecryptPayload(r.Header, body)
+	if err != nil {
+		return nil, err
+	}
+	var n PushNotification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// DecryptPayload decrypts the body of a Web Push message with the headers
+// of its request. Both the aes128gcm encoding of RFC 8291 and the older
+// aesgcm encoding, with the Encryption and Crypto-Key headers, are
+// supported.
+func (k *PushKeys) DecryptPayload(header http.Header, body []byte) ([]byte, error) {
+	switch enc := strings.ToLower(header.Get("Content-Encoding")); enc {
+	case "aes128gcm":
+		return k.decryptAES128GCM(body)
+	case "aesgcm":
+		salt, err := decodePushParam(header.Get("Encryption"), "salt")
+		if err != nil {
+			return nil, err
+		}
+		dh, err := decodePushParam(header.Get("Crypto-Key"), "dh")
+		if err != nil {
+			return nil, err
+		}
+		return k.decryptAESGCM(salt, dh, body)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", enc)
+	}
+}
+
+func (k *PushKeys) decryptAES128GCM(body []byte) ([]byte, error) {
+	if len(body) < 21 {
+		return nil, errors.New("push payload too short")
+	}
+	salt := body[:16]
+	rs := int(binary.BigEndian.Uint32(body[16:20]))
+	idlen := int(body[20])
+	if len(body) < 21+idlen || rs < 18 {
+		return nil, errors.New("invalid push payload header")
+	}
+	senderKey := body[21 : 21+idlen]
+	body = body[21+idlen:]
+
+	secret, err := k.sharedSecret(senderKey)
+	if err != nil {
+		return nil, err
+	}
+	info := append([]byte("WebPush: info\x00"), k.publicKey()...)
+	info = append(info, senderKey...)
+	ikm := hkdf(k.Auth, secret, info, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	var plain []byte
+	for seq := 0; len(body) > 0; seq++ {
+		n := rs
+		if n > len(body) {
+			n = len(body)
+		}
+		record, err := gcm.Open(nil, recordNonce(nonce, seq), body[:n], nil)
+		if err != nil {
+			return nil, err
+		}
+		body = body[n:]
+
+		// Records end with a delimiter followed by zero padding: 2 for the
+		// last record and 1 for the others.
+		i := len(record) - 1
+		for i >= 0 && record[i] == 0 {
+			i--
+		}
+		if i < 0 || (len(body) == 0 && record[i] != 2) || (len(body) > 0 && record[i] != 1) {
+			return nil, errors.New("invalid push payload padding")
+		}
+		plain = append(plain, record[:i]...)
+	}
+	return plain, nil
+}
+
+func (k *PushKeys) decryptAESGCM(salt, senderKey, body []byte) ([]byte, error) {
+	secret, err := k.sharedSecret(senderKey)
+	if err != nil {
+		return nil, err
+	}
+	ikm := hkdf(k.Auth, secret, []byte("Content-Encoding: auth\x00"), 32)
+
+	pub := k.publicKey()
+	keyContext := []byte("P-256\x00")
+	keyContext = append(keyContext, byte(len(pub)>>8), byte(len(pub)))
+	keyContext = append(keyContext, pub...)
+	keyContext = append(keyContext, byte(len(senderKey)>>8), byte(len(senderKey)))
+	keyContext = append(keyContext, senderKey...)
+	cek := hkdf(salt, ikm, append([]byte("Content-Encoding: aesgcm\x00"), keyContext...), 16)
+	nonce := hkdf(salt, ikm, append([]byte("Content-Encoding: nonce\x00"), keyContext...), 12)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	// The default record size of 4096 bytes plus the tag.
+	const rs = 4096 + 16
+	var plain []byte
+	for seq := 0; len(body) > 0; seq++ {
+		n := rs
+		if n > len(body) {
+			n = len(body)
+		}
+		record, err := gcm.Open(nil, recordNonce(nonce, seq), body[:n], nil)
+		if err != nil {
+			return nil, err
+		}
+		body = body[n:]
+
+		// Records start with the length of the padding that follows.
+		if len(record) < 2 {
+			return nil, errors.New("invalid push payload padding")
+		}
+		pad := int(binary.BigEndian.Uint16(record))
+		if len(record) < 2+pad {
+			return nil, errors.New("invalid push payload padding")
+		}
+		plain = append(plain, record[2+pad:]...)
+	}
+	return plain, nil
+}
+
+func (k *PushKeys) publicKey() []byte {
+	pub := k.PrivateKey.PublicKey
+	return elliptic.Marshal(pub.Curve, pub.X, pub.Y)
+}
+
+// sharedSecret returns the ECDH secret of the private key and the public
+// key of the sender.
+func (k *PushKeys) sharedSecret(senderKey []byte) ([]byte, error) {
+	curve := k.PrivateKey.Curve
+	x, y := elliptic.Unmarshal(curve, senderKey)
+	if x == nil {
+		return nil, errors.New("invalid sender public key")
+	}
+	sx, _ := curve.ScalarMult(x, y, k.PrivateKey.D.Bytes())
+	secret := make([]byte, (curve.Params().BitSize+7)/8)
+	return sx.FillBytes(secret), nil
+}
+
+// hkdf derives length bytes from ikm with HKDF-SHA256 (RFC 5869). length
+// must not exceed the size of a SHA-256 hash.
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	prk := mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{1})
+	return mac.Sum(nil)[:length]
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// recordNonce returns the nonce of record seq, which is the base nonce
+// XORed with the sequence number.
+func recordNonce(nonce []byte, seq int) []byte {
+	n := make([]byte, len(nonce))
+	copy(n, nonce)
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], uint64(seq))
+	for i := range s {
+		n[len(n)-8+i] ^= s[i]
+	}
+	return n
+}
+
+// decodePushParam returns the base64url decoded value of name in a header
+// like `salt=...` or `dh=...;p256ecdsa=...`.
+func decodePushParam(header, name string) ([]byte, error) {
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ';' || r == ',' }) {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 && kv[0] == name {
+			v := strings.Trim(kv[1], `"`)
+			return base64.RawURLEncoding.DecodeString(strings.TrimRight(v, "="))
+		}
+	}
+	return nil, fmt.Errorf("missing %s in push headers", name)
+}
@@ -0,0 +1,130 @@
+package mastodon
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mustDecodePush(t *testing.T, s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The example of RFC 8291, Appendix A.
+func TestPushDecryptAES128GCM(t *testing.T) {
+	d := mustDecodePush(t, "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94")
+	priv := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	priv.Curve = elliptic.P256()
+	priv.X, priv.Y = priv.Curve.ScalarBaseMult(d)
+	keys := &PushKeys{PrivateKey: priv, Auth: mustDecodePush(t, "BTBZMqHH6r4Tts7J_aSIgg")}
+	if keys.P256dh() != "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4" {
+		t.Fatalf("unexpected public key: %s", keys.P256dh())
+	}
+
+	body := mustDecodePush(t, "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN")
+	header := http.Header{}
+	header.Set("Content-Encoding", "aes128gcm")
+	plain, err := keys.DecryptPayload(header, body)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := "When I grow up, I want to be a watermelon"
+	if string(plain) != want {
+		t.Fatalf("want %q but %q", want, plain)
+	}
+
+	body[len(body)-1] ^= 1
+	if _, err := keys.DecryptPayload(header, body); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+// encryptAESGCM encrypts plain for keys with the aesgcm encoding, like
+// Mastodon does.
+func encryptAESGCM(t *testing.T, keys *PushKeys, plain []byte) (salt, senderKey, body []byte) {
+	sender, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt = make([]byte, 16)
+	rand.Read(salt)
+	senderKey = elliptic.Marshal(sender.Curve, sender.X, sender.Y)
+
+	sx, _ := sender.Curve.ScalarMult(keys.PrivateKey.X, keys.PrivateKey.Y, sender.D.Bytes())
+	secret := sx.FillBytes(make([]byte, 32))
+	ikm := hkdf(keys.Auth, secret, []byte("Content-Encoding: auth\x00"), 32)
+	pub := keys.publicKey()
+	ctx := []byte("P-256\x00")
+	ctx = append(ctx, 0, byte(len(pub)))
+	ctx = append(ctx, pub...)
+	ctx = append(ctx, 0, byte(len(senderKey)))
+	ctx = append(ctx, senderKey...)
+	cek := hkdf(salt, ikm, append([]byte("Content-Encoding: aesgcm\x00"), ctx...), 16)
+	nonce := hkdf(salt, ikm, append([]byte("Content-Encoding: nonce\x00"), ctx...), 12)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := make([]byte, 2+3)
+	binary.BigEndian.PutUint16(record, 3)
+	record = append(record, plain...)
+	return salt, senderKey, gcm.Seal(nil, nonce, record, nil)
+}
+
+func TestPushDecryptAESGCM(t *testing.T) {
+	keys, err := GeneratePushKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt, senderKey, body := encryptAESGCM(t, keys, []byte(`{"notification_id": 42, "notification_type": "mention", "title": "You were mentioned by alice", "body": "@bob hi"}`))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := keys.Decrypt(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if n.NotificationID != "42" || n.NotificationType != NotificationTypeMention || n.Body != "@bob hi" {
+			http.Error(w, "unexpected notification", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Encoding", "aesgcm")
+	req.Header.Set("Encryption", "salt="+base64.RawURLEncoding.EncodeToString(salt))
+	req.Header.Set("Crypto-Key", "dh="+base64.RawURLEncoding.EncodeToString(senderKey)+";p256ecdsa=BOgus")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("want %v but %v: %s", http.StatusCreated, resp.StatusCode, b)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Encoding", "gzip")
+	if _, err := keys.DecryptPayload(header, body); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
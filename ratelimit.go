@@ -0,0 +1,80 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the server in the
+// X-RateLimit-* headers of a response.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int64
+	// Remaining is the number of requests left in the current window.
+	Remaining int64
+	// Reset is when the window ends and Remaining goes back to Limit.
+	Reset time.Time
+}
+
+// RateLimit returns the rate limit state of the last response carrying
+// rate limit headers to a request made with Config.AccessToken, or nil if
+// there was none yet.
+func (c *Client) RateLimit() *RateLimit {
+	return c.RateLimitContext(context.Background())
+}
+
+// RateLimitContext is like RateLimit for the requests made with the token
+// of ctx; see WithToken.
+func (c *Client) RateLimitContext(ctx context.Context) *RateLimit {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	rl := c.rateLimit
+	if token, ok := tokenFromContext(ctx); ok {
+		rl = c.rateLimits[token]
+	}
+	if rl == nil {
+		return nil
+	}
+	rl2 := *rl
+	return &rl2
+}
+
+func (c *Client) updateRateLimit(ctx context.Context, h http.Header) {
+	rl := parseRateLimit(h)
+	if rl == nil {
+		return
+	}
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	token, ok := tokenFromContext(ctx)
+	if !ok {
+		c.rateLimit = rl
+		return
+	}
+	if c.rateLimits == nil {
+		c.rateLimits = map[string]*RateLimit{}
+	}
+	c.rateLimits[token] = rl
+}
+
+func parseRateLimit(h http.Header) *RateLimit {
+	limit, err := strconv.ParseInt(h.Get("X-RateLimit-Limit"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.ParseInt(h.Get("X-RateLimit-Remaining"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	rl := &RateLimit{Limit: limit, Remaining: remaining}
+	if reset := h.Get("X-RateLimit-Reset"); reset != "" {
+		if t, err := time.Parse(time.RFC3339Nano, reset); err == nil {
+			rl.Reset = t
+		} else if ts, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			rl.Reset = time.Unix(ts, 0)
+		}
+	}
+	return rl
+}
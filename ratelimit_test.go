@@ -0,0 +1,63 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/accounts/verify_credentials" {
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Remaining", "299")
+			w.Header().Set("X-RateLimit-Reset", "2024-05-01T12:05:00.000Z")
+		}
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	if rl := client.RateLimit(); rl != nil {
+		t.Fatalf("want nil but %v", rl)
+	}
+	if _, err := client.GetAccountCurrentUser(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	rl := client.RateLimit()
+	if rl == nil {
+		t.Fatal("rate limit should be set")
+	}
+	if rl.Limit != 300 || rl.Remaining != 299 {
+		t.Fatalf("want 300/299 but %d/%d", rl.Limit, rl.Remaining)
+	}
+	want := time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC)
+	if !rl.Reset.Equal(want) {
+		t.Fatalf("want %v but %v", want, rl.Reset)
+	}
+
+	// Responses without headers keep the last state.
+	if _, err := client.GetAccount(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rl := client.RateLimit(); rl == nil || rl.Remaining != 299 {
+		t.Fatalf("want 299 but %v", rl)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	if rl := parseRateLimit(h); rl != nil {
+		t.Fatalf("want nil but %v", rl)
+	}
+	h.Set("X-RateLimit-Limit", "300")
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset", "1714565100")
+	rl := parseRateLimit(h)
+	if rl == nil || rl.Remaining != 0 || rl.Reset.Unix() != 1714565100 {
+		t.Fatalf("unexpected rate limit: %v", rl)
+	}
+}
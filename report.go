@@ -2,14 +2,25 @@ package mastodon
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // Report holds information for a mastodon report.
 type Report struct {
-	ID          int64 `json:"id"`
-	ActionTaken bool  `json:"action_taken"`
+	ID            ID             `json:"id"`
+	ActionTaken   bool           `json:"action_taken"`
+	ActionTakenAt *time.Time     `json:"action_taken_at"`
+	Category      ReportCategory `json:"category"`
+	Comment       string         `json:"comment"`
+	Forwarded     bool           `json:"forwarded"`
+	CreatedAt     time.Time      `json:"created_at"`
+	StatusIDs     []ID           `json:"status_ids"`
+	RuleIDs       []ID           `json:"rule_ids"`
+	TargetAccount *Account       `json:"target_account"`
 }
 
 // GetReports returns report of the current user.
@@ -24,12 +35,58 @@ func (c *Client) GetReports(ctx context.Context) ([]*Report, error) {
 
 // Report reports the report
 func (c *Client) Report(ctx context.Context, accountID ID, ids []ID, comment string) (*Report, error) {
+	return c.FileReport(ctx, &ReportParams{AccountID: accountID, StatusIDs: ids, Comment: comment})
+}
+
+// ReportParams holds the report to file with FileReport.
+type ReportParams struct {
+	AccountID ID
+	// StatusIDs are the statuses of the account to attach.
+	StatusIDs []ID
+	// Comment is the reason for the report, up to 1,000 characters.
+	Comment string
+	// Forward sends a copy of the report to the instance of a remote
+	// account, or the instances of ForwardToDomains.
+	Forward          bool
+	ForwardToDomains []string
+	// Category defaults to ReportCategoryOther.
+	Category ReportCategory
+	// RuleIDs are the broken rules of the instance, for
+	// ReportCategoryViolation.
+	RuleIDs []ID
+}
+
+// FileReport files the report p and returns it.
+func (c *Client) FileReport(ctx context.Context, p *ReportParams) (*Report, error) {
+	if p == nil || p.AccountID == "" {
+		return nil, errors.New("account ID can't be empty")
+	}
+	if p.Category != "" && !p.Category.Valid() {
+		return nil, fmt.Errorf("unknown report category %q", p.Category)
+	}
+	if len(p.RuleIDs) > 0 && p.Category != ReportCategoryViolation {
+		return nil, errors.New("rule IDs need the violation category")
+	}
+
 	params := url.Values{}
-	params.Set("account_id", string(accountID))
-	for _, id := range ids {
+	params.Set("account_id", string(p.AccountID))
+	for _, id := range p.StatusIDs {
 		params.Add("status_ids[]", string(id))
 	}
-	params.Set("comment", comment)
+	params.Set("comment", p.Comment)
+	if p.Forward {
+		params.Set("forward", "true")
+		for _, domain := range p.ForwardToDomains {
+			params.Add("forward_to_domains[]", domain)
+		}
+	}
+	if p.Category != "" {
+		params.Set("category", string(p.Category))
+	}
+	for _, id := range p.RuleIDs {
+		params.Add("rule_ids[]", string(id))
+	}
+
 	var report Report
 	err := c.doAPI(ctx, http.MethodPost, "/api/v1/reports", params, &report, nil)
 	if err != nil {
@@ -2,9 +2,11 @@ package mastodon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -31,11 +33,11 @@ func TestGetReports(t *testing.T) {
 	if len(rs) != 2 {
 		t.Fatalf("result should be two: %d", len(rs))
 	}
-	if rs[0].ID != 122 {
-		t.Fatalf("want %v but %v", 122, rs[0].ID)
+	if rs[0].ID != "122" {
+		t.Fatalf("want %v but %v", "122", rs[0].ID)
 	}
-	if rs[1].ID != 123 {
-		t.Fatalf("want %v but %v", 123, rs[1].ID)
+	if rs[1].ID != "123" {
+		t.Fatalf("want %v but %v", "123", rs[1].ID)
 	}
 }
 
@@ -71,7 +73,7 @@ func TestReport(t *testing.T) {
 	if err != nil {
 		t.Fatalf("should not be fail: %v", err)
 	}
-	if rp.ID != 1234 {
+	if rp.ID != "1234" {
 		t.Fatalf("want %q but %q", "1234", rp.ID)
 	}
 	if rp.ActionTaken {
@@ -81,10 +83,72 @@ func TestReport(t *testing.T) {
 	if err != nil {
 		t.Fatalf("should not be fail: %v", err)
 	}
-	if rp.ID != 1234 {
+	if rp.ID != "1234" {
 		t.Fatalf("want %q but %q", "1234", rp.ID)
 	}
 	if !rp.ActionTaken {
 		t.Fatalf("want %v but %v", false, rp.ActionTaken)
 	}
 }
+
+func TestFileReport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("account_id") != "123" ||
+			strings.Join(r.PostForm["status_ids[]"], ",") != "1,2" ||
+			r.PostForm.Get("comment") != "rude" ||
+			r.PostForm.Get("forward") != "true" ||
+			r.PostForm.Get("forward_to_domains[]") != "example.com" ||
+			strings.Join(r.PostForm["rule_ids[]"], ",") != "3,4" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "9", "category": %q, "comment": "rude", "forwarded": true, "status_ids": ["1", "2"], "rule_ids": ["3", "4"]}`, r.PostForm.Get("category"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	rp, err := client.FileReport(context.Background(), &ReportParams{
+		AccountID:        "123",
+		StatusIDs:        []ID{"1", "2"},
+		Comment:          "rude",
+		Forward:          true,
+		ForwardToDomains: []string{"example.com"},
+		Category:         ReportCategoryViolation,
+		RuleIDs:          []ID{"3", "4"},
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if rp.ID != "9" || rp.Category != ReportCategoryViolation || !rp.Forwarded || len(rp.RuleIDs) != 2 {
+		t.Fatalf("want %q but %q", ReportCategoryViolation, rp.Category)
+	}
+
+	for _, p := range []*ReportParams{
+		nil,
+		{},
+		{AccountID: "123", Category: "abuse"},
+		{AccountID: "123", Category: ReportCategorySpam, RuleIDs: []ID{"3"}},
+	} {
+		if _, err := client.FileReport(context.Background(), p); err == nil {
+			t.Fatalf("should be fail: %v", err)
+		}
+	}
+}
+
+func TestReportUnmarshalJSON(t *testing.T) {
+	var r Report
+	err := json.Unmarshal([]byte(`{"id": "42", "action_taken": true, "category": "spam", "status_ids": ["1", "2"], "target_account": {"id": "3", "acct": "spammer"}}`), &r)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if r.ID != "42" || !r.ActionTaken || r.Category != "spam" || len(r.StatusIDs) != 2 || r.TargetAccount.Acct != "spammer" {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	if err := json.Unmarshal([]byte(`{"id": 43}`), &r); err != nil || r.ID != "43" {
+		t.Fatalf("unexpected report: %+v %v", r, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id": "AbCdEf0123456789xY"}`), &r); err != nil || r.ID != "AbCdEf0123456789xY" {
+		t.Fatalf("unexpected report: %+v %v", r, err)
+	}
+}
@@ -0,0 +1,101 @@
+package mastodon
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CachedResponse is the body of a response with the validators to
+// revalidate it.
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+}
+
+// newCachedResponse returns the cached response for body, or nil if the
+// header has no validators.
+func newCachedResponse(h http.Header, body []byte) *CachedResponse {
+	r := &CachedResponse{
+		ETag:         h.Get("ETag"),
+		LastModified: h.Get("Last-Modified"),
+		Body:         body,
+	}
+	if r.ETag == "" && r.LastModified == "" {
+		return nil
+	}
+	return r
+}
+
+func (r *CachedResponse) setHeaders(h http.Header) {
+	if r == nil {
+		return
+	}
+	if r.ETag != "" {
+		h.Set("If-None-Match", r.ETag)
+	}
+	if r.LastModified != "" {
+		h.Set("If-Modified-Since", r.LastModified)
+	}
+}
+
+// ResponseCache stores cached responses by request URL. Set is called with
+// a nil response when a response can't be revalidated anymore.
+type ResponseCache interface {
+	Get(url string) *CachedResponse
+	Set(url string, r *CachedResponse)
+}
+
+// MemoryResponseCache is a ResponseCache kept in memory.
+type MemoryResponseCache struct {
+	mu        sync.Mutex
+	responses map[string]*CachedResponse
+}
+
+// NewMemoryResponseCache returns an empty MemoryResponseCache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{responses: map[string]*CachedResponse{}}
+}
+
+// Get returns the response cached for url, or nil.
+func (m *MemoryResponseCache) Get(url string) *CachedResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.responses[url]
+}
+
+// Set caches r for url, removing the cached response if r is nil.
+func (m *MemoryResponseCache) Set(url string, r *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r == nil {
+		delete(m.responses, url)
+		return
+	}
+	if m.responses == nil {
+		m.responses = map[string]*CachedResponse{}
+	}
+	m.responses[url] = r
+}
+
+// accountsResources are the endpoints under /api/v1/accounts/ which aren't
+// account profiles.
+var accountsResources = map[string]bool{
+	"verify_credentials": true,
+	"update_credentials": true,
+	"relationships":      true,
+	"familiar_followers": true,
+	"search":             true,
+	"lookup":             true,
+}
+
+// conditionalCached reports whether the responses of uri are revalidated
+// with a ResponseCache: the instance, custom emojis and account profiles.
+func conditionalCached(uri string) bool {
+	if lowBandwidthCached(uri) || uri == "/api/v1/custom_emojis" {
+		return true
+	}
+	id := strings.TrimPrefix(uri, "/api/v1/accounts/")
+	return id != uri && id != "" && !strings.ContainsAny(id, "/?") && !accountsResources[id]
+}
@@ -0,0 +1,105 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseCache(t *testing.T) {
+	requests, modified := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		modified++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintln(w, `{"id": "1234567", "username": "foo"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.ResponseCache = NewMemoryResponseCache()
+	for i := 0; i < 3; i++ {
+		a, err := client.GetAccount(context.Background(), "1234567")
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if a.Username != "foo" {
+			t.Fatalf("want %q but %q", "foo", a.Username)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("want %d requests but %d", 3, requests)
+	}
+	if modified != 1 {
+		t.Fatalf("want %d full response but %d", 1, modified)
+	}
+}
+
+func TestResponseCacheNotCached(t *testing.T) {
+	var conditional []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-Modified-Since"))
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		fmt.Fprintln(w, `[]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.ResponseCache = NewMemoryResponseCache()
+	for i := 0; i < 2; i++ {
+		_, err := client.GetTimelineHome(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+	}
+	for _, c := range conditional {
+		if c != "" {
+			t.Fatalf("timelines should not be revalidated: %q", c)
+		}
+	}
+}
+
+func TestConditionalCached(t *testing.T) {
+	tests := map[string]bool{
+		"/api/v1/instance":                    true,
+		"/api/v2/instance":                    true,
+		"/api/v1/instance/peers":              true,
+		"/api/v1/custom_emojis":               true,
+		"/api/v1/accounts/1234567":            true,
+		"/api/v1/accounts/1234567/statuses":   false,
+		"/api/v1/accounts/":                   false,
+		"/api/v1/accounts/verify_credentials": false,
+		"/api/v1/accounts/relationships":      false,
+		"/api/v1/accounts/search":             false,
+		"/api/v1/accounts/lookup":             false,
+		"/api/v1/accounts/lookup?acct=alice":  false,
+		"/api/v1/accounts/AbCdEf0123456789xY": true,
+		"/api/v1/timelines/home":              false,
+	}
+	for uri, want := range tests {
+		if got := conditionalCached(uri); got != want {
+			t.Fatalf("%s: want %v but %v", uri, want, got)
+		}
+	}
+}
+
+func TestMemoryResponseCache(t *testing.T) {
+	var m MemoryResponseCache
+	if r := m.Get("foo"); r != nil {
+		t.Fatalf("should be nil: %v", r)
+	}
+	m.Set("foo", &CachedResponse{ETag: `"v1"`})
+	if r := m.Get("foo"); r == nil || r.ETag != `"v1"` {
+		t.Fatalf("want %q but %v", `"v1"`, r)
+	}
+	m.Set("foo", nil)
+	if r := m.Get("foo"); r != nil {
+		t.Fatalf("should be nil: %v", r)
+	}
+}
@@ -0,0 +1,102 @@
+package mastodon
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests are retried when the server throttles
+// them or is temporarily unavailable.
+type RetryPolicy struct {
+	// MaxAttempts caps the attempts of a request, the first one included.
+	// One or less disables retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry when the server gives no
+	// Retry-After or X-RateLimit-Reset hint. It grows by half on each retry
+	// up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// ServerErrors enables retrying idempotent requests, and requests with
+	// an Idempotency-Key, on 502, 503 and 504. Since the backoff is shared
+	// with throttled requests, set a small MaxAttempts or MaxBackoff with
+	// it so requests to a server that is down fail quickly enough.
+	ServerErrors bool
+}
+
+// DefaultRetryPolicy is used by clients without a RetryPolicy. It only
+// retries throttled requests, backing off for up to an hour as the server
+// asks; errors of unavailable servers are returned at once.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 20,
+	Backoff:     time.Second,
+	MaxBackoff:  time.Hour,
+}
+
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return &DefaultRetryPolicy
+}
+
+// delay returns how long to wait before retrying req, and false if it must
+// not be retried.
+func (p *RetryPolicy) delay(req *http.Request, resp *http.Response, backoff time.Duration) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		if d, ok := retryAfter(resp.Header); ok {
+			return d, true
+		}
+		if rl := parseRateLimit(resp.Header); rl != nil && rl.Remaining == 0 && !rl.Reset.IsZero() {
+			if d := time.Until(rl.Reset); d > 0 {
+				return d, true
+			}
+		}
+		return backoff, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !p.ServerErrors || !idempotentMethod(req.Method) && req.Header.Get("Idempotency-Key") == "" {
+			return 0, false
+		}
+		if d, ok := retryAfter(resp.Header); ok {
+			return d, true
+		}
+		return backoff, true
+	}
+	return 0, false
+}
+
+func (p *RetryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	backoff = time.Duration(1.5 * float64(backoff))
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
+
+// retryAfter parses the Retry-After header, in seconds or as an HTTP date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
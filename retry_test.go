@@ -0,0 +1,138 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryTooManyRequests(t *testing.T) {
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.FormValue("status") != "foo" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `{"content": "foo"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}
+	status, err := client.PostStatus(context.Background(), &Toot{Status: "foo"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if status.Content != "foo" {
+		t.Fatalf("want %q but %q", "foo", status.Content)
+	}
+	if attempts != 3 {
+		t.Fatalf("want 3 attempts but %d", attempts)
+	}
+
+	attempts = 0
+	client.RetryPolicy.MaxAttempts = 2
+	_, err = client.PostStatus(context.Background(), &Toot{Status: "foo"})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("want 2 attempts but %d", attempts)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts%2 == 1 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, ServerErrors: true}
+	if _, err := client.GetAccount(context.Background(), "1"); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("want 2 attempts but %d", attempts)
+	}
+
+	// POST is not idempotent.
+	attempts = 0
+	if _, err := client.AccountFollow(context.Background(), "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("want 1 attempt but %d", attempts)
+	}
+
+	client.RetryPolicy.ServerErrors = false
+	attempts = 0
+	if _, err := client.GetAccount(context.Background(), "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	// The default policy doesn't retry server errors.
+	client.RetryPolicy = nil
+	attempts = 0
+	if _, err := client.GetAccount(context.Background(), "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("want 1 attempt but %d", attempts)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetAccount(ctx, "1")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("want %v but %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := &RetryPolicy{MaxBackoff: 2 * time.Second}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	if d, ok := p.delay(req, resp, time.Second); !ok || d != time.Second {
+		t.Fatalf("want %v but %v", time.Second, d)
+	}
+	resp.Header.Set("X-RateLimit-Limit", "300")
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano))
+	if d, ok := p.delay(req, resp, time.Second); !ok || d < 50*time.Second || d > time.Minute {
+		t.Fatalf("want about a minute but %v", d)
+	}
+	resp.Header.Set("Retry-After", "5")
+	if d, ok := p.delay(req, resp, time.Second); !ok || d != 5*time.Second {
+		t.Fatalf("want %v but %v", 5*time.Second, d)
+	}
+	resp.StatusCode = http.StatusNotFound
+	if _, ok := p.delay(req, resp, time.Second); ok {
+		t.Fatal("should not retry")
+	}
+	if d := p.nextBackoff(1500 * time.Millisecond); d != 2*time.Second {
+		t.Fatalf("want %v but %v", 2*time.Second, d)
+	}
+}
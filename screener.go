@@ -0,0 +1,198 @@
+package mastodon
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ScreenAction is the outcome of screening a follow request.
+type ScreenAction int
+
+// Screening outcomes. ScreenNone leaves the follow request pending.
+const (
+	ScreenNone ScreenAction = iota
+	ScreenApprove
+	ScreenReject
+	ScreenFlag
+)
+
+// ScreenRule decides what to do with a follow request from an account.
+// A rule returns ScreenNone when it has no opinion about the account.
+type ScreenRule interface {
+	Screen(account *Account) ScreenAction
+}
+
+// ScreenRuleFunc is an adapter to use an ordinary function as a ScreenRule.
+type ScreenRuleFunc func(account *Account) ScreenAction
+
+// Screen calls f(account).
+func (f ScreenRuleFunc) Screen(account *Account) ScreenAction { return f(account) }
+
+// MinAccountAge returns a rule that applies action to accounts created less
+// than age ago.
+func MinAccountAge(age time.Duration, action ScreenAction) ScreenRule {
+	return ScreenRuleFunc(func(account *Account) ScreenAction {
+		if time.Since(account.CreatedAt) < age {
+			return action
+		}
+		return ScreenNone
+	})
+}
+
+// MinFollowerRatio returns a rule that applies action to accounts whose
+// followers to following ratio is below ratio. Accounts that follow nobody
+// are never matched.
+func MinFollowerRatio(ratio float64, action ScreenAction) ScreenRule {
+	return ScreenRuleFunc(func(account *Account) ScreenAction {
+		if account.FollowingCount == 0 {
+			return ScreenNone
+		}
+		if float64(account.FollowersCount)/float64(account.FollowingCount) < ratio {
+			return action
+		}
+		return ScreenNone
+	})
+}
+
+// BioKeywords returns a rule that applies action to accounts whose note or
+// profile fields contain one of keywords. Matching is case-insensitive.
+func BioKeywords(keywords []string, action ScreenAction) ScreenRule {
+	return ScreenRuleFunc(func(account *Account) ScreenAction {
+		texts := []string{strings.ToLower(account.Note)}
+		for _, f := range account.Fields {
+			texts = append(texts, strings.ToLower(f.Name), strings.ToLower(f.Value))
+		}
+		for _, kw := range keywords {
+			kw = strings.ToLower(kw)
+			for _, text := range texts {
+				if strings.Contains(text, kw) {
+					return action
+				}
+			}
+		}
+		return ScreenNone
+	})
+}
+
+// DomainList returns a rule that applies action to remote accounts from one
+// of domains. Subdomains of a listed domain match as well.
+func DomainList(domains []string, action ScreenAction) ScreenRule {
+	return ScreenRuleFunc(func(account *Account) ScreenAction {
+		i := strings.LastIndex(account.Acct, "@")
+		if i < 0 {
+			return ScreenNone
+		}
+		domain := strings.ToLower(account.Acct[i+1:])
+		for _, d := range domains {
+			d = strings.ToLower(d)
+			if domain == d || strings.HasSuffix(domain, "."+d) {
+				return action
+			}
+		}
+		return ScreenNone
+	})
+}
+
+// FollowRequestScreener evaluates incoming follow requests against a list of
+// rules and authorizes, rejects, or flags them.
+//
+// Rules are evaluated in order and the first rule that returns an action
+// other than ScreenNone decides. If no rule decides, Default is used.
+type FollowRequestScreener struct {
+	Client  *Client
+	Rules   []ScreenRule
+	Default ScreenAction
+
+	// OnFlag is called for follow requests that are flagged. The request
+	// stays pending.
+	OnFlag func(account *Account)
+
+	// OnError is called by Run for errors that do not stop screening.
+	OnError func(err error)
+}
+
+// Screen returns the action for a follow request from account.
+func (s *FollowRequestScreener) Screen(account *Account) ScreenAction {
+	for _, r := range s.Rules {
+		if action := r.Screen(account); action != ScreenNone {
+			return action
+		}
+	}
+	return s.Default
+}
+
+// Process screens the follow request from account and applies the result.
+func (s *FollowRequestScreener) Process(ctx context.Context, account *Account) (ScreenAction, error) {
+	action := s.Screen(account)
+	var err error
+	switch action {
+	case ScreenApprove:
+		err = s.Client.FollowRequestAuthorize(ctx, account.ID)
+	case ScreenReject:
+		err = s.Client.FollowRequestReject(ctx, account.ID)
+	case ScreenFlag:
+		if s.OnFlag != nil {
+			s.OnFlag(account)
+		}
+	}
+	return action, err
+}
+
+// ScreenPending processes all follow requests that are currently pending.
+func (s *FollowRequestScreener) ScreenPending(ctx context.Context) error {
+	var pending []*Account
+	var pg Pagination
+	for {
+		accounts, err := s.Client.GetFollowRequests(ctx, &pg)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, accounts...)
+		if len(accounts) == 0 || pg.MaxID == "" {
+			break
+		}
+		pg.SinceID = ""
+		pg.MinID = ""
+	}
+
+	for _, account := range pending {
+		if _, err := s.Process(ctx, account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run processes pending follow requests and then screens new follow requests
+// as they arrive on the user stream, until ctx is canceled.
+func (s *FollowRequestScreener) Run(ctx context.Context) error {
+	if err := s.ScreenPending(ctx); err != nil {
+		return err
+	}
+
+	q, err := s.Client.StreamingUser(ctx)
+	if err != nil {
+		return err
+	}
+	for e := range q {
+		switch event := e.(type) {
+		case *NotificationEvent:
+			if event.Notification.Type != NotificationTypeFollowRequest {
+				continue
+			}
+			if _, err := s.Process(ctx, &event.Notification.Account); err != nil {
+				s.error(err)
+			}
+		case *ErrorEvent:
+			s.error(event)
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *FollowRequestScreener) error(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
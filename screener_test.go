@@ -0,0 +1,110 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScreenRules(t *testing.T) {
+	young := &Account{CreatedAt: time.Now().Add(-time.Hour)}
+	old := &Account{CreatedAt: time.Now().Add(-100 * 24 * time.Hour)}
+	if got := MinAccountAge(24*time.Hour, ScreenFlag).Screen(young); got != ScreenFlag {
+		t.Fatalf("want %v but %v", ScreenFlag, got)
+	}
+	if got := MinAccountAge(24*time.Hour, ScreenFlag).Screen(old); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+
+	ratio := MinFollowerRatio(0.1, ScreenReject)
+	if got := ratio.Screen(&Account{FollowersCount: 1, FollowingCount: 5000}); got != ScreenReject {
+		t.Fatalf("want %v but %v", ScreenReject, got)
+	}
+	if got := ratio.Screen(&Account{FollowersCount: 100, FollowingCount: 200}); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+	if got := ratio.Screen(&Account{}); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+
+	bio := BioKeywords([]string{"crypto"}, ScreenReject)
+	if got := bio.Screen(&Account{Note: "<p>Best CRYPTO deals</p>"}); got != ScreenReject {
+		t.Fatalf("want %v but %v", ScreenReject, got)
+	}
+	if got := bio.Screen(&Account{Fields: []Field{{Name: "Shop", Value: "crypto.example"}}}); got != ScreenReject {
+		t.Fatalf("want %v but %v", ScreenReject, got)
+	}
+	if got := bio.Screen(&Account{Note: "gardening"}); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+
+	domains := DomainList([]string{"spam.example"}, ScreenReject)
+	if got := domains.Screen(&Account{Acct: "foo@spam.example"}); got != ScreenReject {
+		t.Fatalf("want %v but %v", ScreenReject, got)
+	}
+	if got := domains.Screen(&Account{Acct: "foo@eu.SPAM.example"}); got != ScreenReject {
+		t.Fatalf("want %v but %v", ScreenReject, got)
+	}
+	if got := domains.Screen(&Account{Acct: "foo@notspam.example"}); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+	if got := domains.Screen(&Account{Acct: "foo"}); got != ScreenNone {
+		t.Fatalf("want %v but %v", ScreenNone, got)
+	}
+}
+
+func TestFollowRequestScreener(t *testing.T) {
+	var authorized, rejected []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/follow_requests":
+			fmt.Fprintln(w, `[{"id": "1", "acct": "good@friends.example"}, {"id": "2", "acct": "bad@spam.example"}, {"id": "3", "acct": "new", "note": "hello"}]`)
+		case "/api/v1/follow_requests/1/authorize", "/api/v1/follow_requests/3/authorize":
+			authorized = append(authorized, r.URL.Path)
+		case "/api/v1/follow_requests/2/reject":
+			rejected = append(rejected, r.URL.Path)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	var flagged []ID
+	s := &FollowRequestScreener{
+		Client: NewClient(&Config{Server: ts.URL}),
+		Rules: []ScreenRule{
+			DomainList([]string{"spam.example"}, ScreenReject),
+			BioKeywords([]string{"hello"}, ScreenFlag),
+		},
+		Default: ScreenApprove,
+		OnFlag:  func(a *Account) { flagged = append(flagged, a.ID) },
+	}
+	if got := s.Screen(&Account{Acct: "new", Note: "hello"}); got != ScreenFlag {
+		t.Fatalf("want %v but %v", ScreenFlag, got)
+	}
+
+	err := s.ScreenPending(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(authorized) != 1 || authorized[0] != "/api/v1/follow_requests/1/authorize" {
+		t.Fatalf("unexpected authorized requests: %v", authorized)
+	}
+	if len(rejected) != 1 {
+		t.Fatalf("unexpected rejected requests: %v", rejected)
+	}
+	if len(flagged) != 1 || flagged[0] != "3" {
+		t.Fatalf("unexpected flagged requests: %v", flagged)
+	}
+
+	action, err := s.Process(context.Background(), &Account{ID: "4", Acct: "other"})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if action != ScreenApprove {
+		t.Fatalf("want %v but %v", ScreenApprove, action)
+	}
+}
@@ -0,0 +1,300 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PreferenceChange is a preference that differs between two accounts.
+type PreferenceChange struct {
+	Name string
+	Old  string
+	New  string
+	// Settable reports whether Apply can set it; reading preferences can
+	// only be changed in the web interface.
+	Settable bool
+}
+
+// ListChange is a list of the old account missing on the new one, or
+// missing some of its accounts there.
+type ListChange struct {
+	Title string
+	// List is the list of the new account, nil if it must be created.
+	List *List
+	// Accounts are the addresses, user@domain, of the members missing from
+	// the list of the new account.
+	Accounts []string
+	// Skipped are the addresses Apply couldn't add, because they can't be
+	// found or aren't followed by the new account.
+	Skipped []string
+}
+
+// SettingsDiff lists what the new account of a migration misses from the
+// old account, besides follows.
+type SettingsDiff struct {
+	Preferences []*PreferenceChange
+	// Filters are the filters of the old account without an equivalent,
+	// same phrase and contexts, on the new one. Expired filters are left out.
+	Filters []*Filter
+	// Tags are the names of the tags followed by the old account only.
+	Tags  []string
+	Lists []*ListChange
+}
+
+// Empty reports whether there is nothing to migrate.
+func (d *SettingsDiff) Empty() bool {
+	return len(d.Preferences) == 0 && len(d.Filters) == 0 && len(d.Tags) == 0 && len(d.Lists) == 0
+}
+
+// CompareSettings compares the preferences, filters, followed tags and lists
+// of the accounts of two clients, typically of an account and the account it
+// moves to on another instance, and returns what the account of to misses.
+func CompareSettings(ctx context.Context, from, to *Client) (*SettingsDiff, error) {
+	d := &SettingsDiff{}
+	if err := d.comparePreferences(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if err := d.compareFilters(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if err := d.compareTags(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if err := d.compareLists(ctx, from, to); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *SettingsDiff) comparePreferences(ctx context.Context, from, to *Client) error {
+	a, err := from.GetPreferences(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := to.GetPreferences(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range []PreferenceChange{
+		{"posting:default:visibility", a.PostingDefaultVisibility, b.PostingDefaultVisibility, true},
+		{"posting:default:sensitive", fmt.Sprint(a.PostingDefaultSensitive), fmt.Sprint(b.PostingDefaultSensitive), true},
+		{"posting:default:language", a.PostingDefaultLanguage, b.PostingDefaultLanguage, true},
+		{"reading:expand:media", a.ReadingExpandMedia, b.ReadingExpandMedia, false},
+		{"reading:expand:spoilers", fmt.Sprint(a.ReadingExpandSpoilers), fmt.Sprint(b.ReadingExpandSpoilers), false},
+	} {
+		if p.Old != p.New {
+			p := p
+			d.Preferences = append(d.Preferences, &p)
+		}
+	}
+	return nil
+}
+
+func filterKey(f *Filter) string {
+	context := make([]string, len(f.Context))
+	for i, c := range f.Context {
+		context[i] = string(c)
+	}
+	sort.Strings(context)
+	return strings.ToLower(f.Phrase) + "\x00" + strings.Join(context, ",")
+}
+
+func (d *SettingsDiff) compareFilters(ctx context.Context, from, to *Client) error {
+	a, err := from.GetFilters(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := to.GetFilters(ctx)
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, f := range b {
+		have[filterKey(f)] = true
+	}
+	now := time.Now()
+	for _, f := range a {
+		if !have[filterKey(f)] && (f.ExpiresAt.IsZero() || f.ExpiresAt.After(now)) {
+			d.Filters = append(d.Filters, f)
+		}
+	}
+	return nil
+}
+
+func followedTags(ctx context.Context, c *Client) ([]string, error) {
+	var tags []string
+	err := walkPages(ctx, 0, &Pagination{Limit: 200}, func(pg *Pagination) (bool, error) {
+		page, err := c.TagsFollowed(ctx, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, t := range page {
+			tags = append(tags, t.Name)
+		}
+		return len(page) > 0, nil
+	})
+	return tags, err
+}
+
+func (d *SettingsDiff) compareTags(ctx context.Context, from, to *Client) error {
+	a, err := followedTags(ctx, from)
+	if err != nil {
+		return err
+	}
+	b, err := followedTags(ctx, to)
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, t := range b {
+		have[strings.ToLower(t)] = true
+	}
+	for _, t := range a {
+		if !have[strings.ToLower(t)] {
+			d.Tags = append(d.Tags, t)
+		}
+	}
+	return nil
+}
+
+// listMembers returns the addresses of the members of a list.
+func listMembers(ctx context.Context, c *Client, id ID, domain string) (map[string]bool, error) {
+	members := map[string]bool{}
+	err := walkPages(ctx, 0, &Pagination{Limit: 80}, func(pg *Pagination) (bool, error) {
+		accounts, err := c.GetListAccountsPaginated(ctx, id, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, a := range accounts {
+			acct := a.Acct
+			if !strings.Contains(acct, "@") {
+				acct += "@" + domain
+			}
+			members[strings.ToLower(acct)] = true
+		}
+		return len(accounts) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (d *SettingsDiff) compareLists(ctx context.Context, from, to *Client) error {
+	oldInstance, err := from.GetInstance(ctx)
+	if err != nil {
+		return err
+	}
+	newInstance, err := to.GetInstance(ctx)
+	if err != nil {
+		return err
+	}
+	a, err := from.GetLists(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := to.GetLists(ctx)
+	if err != nil {
+		return err
+	}
+	lists := map[string]*List{}
+	for _, l := range b {
+		lists[l.Title] = l
+	}
+	for _, l := range a {
+		members, err := listMembers(ctx, from, l.ID, oldInstance.URI)
+		if err != nil {
+			return err
+		}
+		have := map[string]bool{}
+		if nl, ok := lists[l.Title]; ok {
+			if have, err = listMembers(ctx, to, nl.ID, newInstance.URI); err != nil {
+				return err
+			}
+		}
+		change := &ListChange{Title: l.Title, List: lists[l.Title]}
+		for acct := range members {
+			if !have[acct] {
+				change.Accounts = append(change.Accounts, acct)
+			}
+		}
+		sort.Strings(change.Accounts)
+		if change.List == nil || len(change.Accounts) > 0 {
+			d.Lists = append(d.Lists, change)
+		}
+	}
+	return nil
+}
+
+// Apply migrates the diff to the new account, using its client to: it sets the
+// settable preferences, creates the filters, follows the tags, creates
+// the lists and adds their members. Members must be followed by the new
+// account; others are recorded in ListChange.Skipped.
+func (d *SettingsDiff) Apply(ctx context.Context, to *Client) error {
+	var source AccountSource
+	for _, p := range d.Preferences {
+		switch p.Name {
+		case "posting:default:visibility":
+			source.Privacy = String(p.Old)
+		case "posting:default:sensitive":
+			sensitive := p.Old == "true"
+			source.Sensitive = &sensitive
+		case "posting:default:language":
+			source.Language = String(p.Old)
+		}
+	}
+	if source.Privacy != nil || source.Sensitive != nil || source.Language != nil {
+		if _, err := to.AccountUpdate(ctx, &Profile{Source: &source}); err != nil {
+			return err
+		}
+	}
+	for _, f := range d.Filters {
+		if _, err := to.CreateFilter(ctx, f); err != nil {
+			return err
+		}
+	}
+	for _, t := range d.Tags {
+		if _, err := to.TagFollow(ctx, t); err != nil {
+			return err
+		}
+	}
+	for _, l := range d.Lists {
+		if l.List == nil {
+			list, err := to.CreateList(ctx, l.Title)
+			if err != nil {
+				return err
+			}
+			l.List = list
+		}
+		l.Skipped = nil
+		var ids []ID
+		for _, acct := range l.Accounts {
+			accounts, err := to.AccountsSearchResolve(ctx, acct, 1, true)
+			if err != nil {
+				return err
+			}
+			if len(accounts) == 0 {
+				l.Skipped = append(l.Skipped, acct)
+				continue
+			}
+			rels, err := to.GetAccountRelationships(ctx, []string{string(accounts[0].ID)})
+			if err != nil {
+				return err
+			}
+			if len(rels) == 0 || !rels[0].Following {
+				l.Skipped = append(l.Skipped, acct)
+				continue
+			}
+			ids = append(ids, accounts[0].ID)
+		}
+		if len(ids) > 0 {
+			if err := to.AddToList(ctx, l.List.ID, ids...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
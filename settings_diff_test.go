@@ -0,0 +1,134 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompareSettings(t *testing.T) {
+	oldServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/preferences":
+			fmt.Fprintln(w, `{"posting:default:visibility": "unlisted", "posting:default:sensitive": false, "posting:default:language": "en", "reading:expand:media": "show_all"}`)
+		case "/api/v1/filters":
+			fmt.Fprintln(w, `[{"id": "1", "phrase": "crypto", "context": ["home", "public"]}, {"id": "2", "phrase": "spoilers", "context": ["home"]}, {"id": "3", "phrase": "old", "context": ["home"], "expires_at": "2020-01-01T00:00:00Z"}]`)
+		case "/api/v1/followed_tags":
+			fmt.Fprintln(w, `[{"name": "golang"}, {"name": "Mastodon"}]`)
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "old.example"}`)
+		case "/api/v1/lists":
+			fmt.Fprintln(w, `[{"id": "1", "title": "Friends"}, {"id": "2", "title": "Work"}]`)
+		case "/api/v1/lists/1/accounts":
+			fmt.Fprintln(w, `[{"acct": "alice"}, {"acct": "bob@b.example"}]`)
+		case "/api/v1/lists/2/accounts":
+			fmt.Fprintln(w, `[{"acct": "carol@c.example"}]`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer oldServer.Close()
+
+	var calls []string
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/preferences":
+			fmt.Fprintln(w, `{"posting:default:visibility": "public", "posting:default:sensitive": false, "posting:default:language": "en", "reading:expand:media": "default"}`)
+		case "/api/v1/filters":
+			if r.Method == http.MethodPost {
+				calls = append(calls, "filter "+r.PostFormValue("phrase"))
+				fmt.Fprintln(w, `{"id": "9"}`)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "5", "phrase": "Crypto", "context": ["public", "home"]}]`)
+		case "/api/v1/followed_tags":
+			fmt.Fprintln(w, `[{"name": "mastodon"}]`)
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"uri": "new.example"}`)
+		case "/api/v1/lists":
+			if r.Method == http.MethodPost {
+				calls = append(calls, "list "+r.PostFormValue("title"))
+				fmt.Fprintln(w, `{"id": "8", "title": "Work"}`)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "7", "title": "Friends"}]`)
+		case "/api/v1/lists/7/accounts":
+			if r.Method == http.MethodPost {
+				r.ParseForm()
+				calls = append(calls, "add 7 "+strings.Join(r.PostForm["account_ids[]"], ","))
+				fmt.Fprintln(w, `{}`)
+				return
+			}
+			if r.FormValue("max_id") == "" {
+				w.Header().Set("Link", `<http://example.com/api/v1/lists/7/accounts?max_id=3>; rel="next"`)
+				fmt.Fprintln(w, `[{"id": "4", "acct": "dave@d.example"}]`)
+				return
+			}
+			fmt.Fprintln(w, `[{"id": "3", "acct": "bob@b.example"}]`)
+		case "/api/v1/accounts/update_credentials":
+			r.ParseForm()
+			calls = append(calls, "source "+r.Form.Get("source[privacy]"))
+			fmt.Fprintln(w, `{"id": "1"}`)
+		case "/api/v1/tags/golang/follow":
+			calls = append(calls, "tag golang")
+			fmt.Fprintln(w, `{"name": "golang"}`)
+		case "/api/v1/accounts/search":
+			switch r.URL.Query().Get("q") {
+			case "alice@old.example":
+				fmt.Fprintln(w, `[{"id": "11"}]`)
+			case "carol@c.example":
+				fmt.Fprintln(w, `[{"id": "12"}]`)
+			default:
+				fmt.Fprintln(w, `[]`)
+			}
+		case "/api/v1/accounts/relationships":
+			fmt.Fprintf(w, `[{"id": %q, "following": %t}]`, r.URL.Query().Get("id[]"), r.URL.Query().Get("id[]") == "11")
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer newServer.Close()
+
+	from := NewClient(&Config{Server: oldServer.URL, AccessToken: "zoo"})
+	to := NewClient(&Config{Server: newServer.URL, AccessToken: "zoo"})
+	d, err := CompareSettings(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(d.Preferences) != 2 || d.Preferences[0].Name != "posting:default:visibility" || !d.Preferences[0].Settable || d.Preferences[1].Settable {
+		t.Fatalf("unexpected preferences: %+v", d.Preferences)
+	}
+	if len(d.Filters) != 1 || d.Filters[0].Phrase != "spoilers" {
+		t.Fatalf("unexpected filters: %+v", d.Filters)
+	}
+	if !reflect.DeepEqual(d.Tags, []string{"golang"}) {
+		t.Fatalf("want %q but %q", []string{"golang"}, d.Tags)
+	}
+	if len(d.Lists) != 2 {
+		t.Fatalf("result should be two: %d", len(d.Lists))
+	}
+	if l := d.Lists[0]; l.List == nil || l.List.ID != "7" || !reflect.DeepEqual(l.Accounts, []string{"alice@old.example"}) {
+		t.Fatalf("unexpected list: %+v", l)
+	}
+	if l := d.Lists[1]; l.List != nil || !reflect.DeepEqual(l.Accounts, []string{"carol@c.example"}) {
+		t.Fatalf("unexpected list: %+v", l)
+	}
+	if d.Empty() {
+		t.Fatal("diff should not be empty")
+	}
+
+	if err := d.Apply(context.Background(), to); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	want := []string{"source unlisted", "filter spoilers", "tag golang", "add 7 11", "list Work"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("want %q but %q", want, calls)
+	}
+	if !reflect.DeepEqual(d.Lists[1].Skipped, []string{"carol@c.example"}) {
+		t.Fatalf("want carol skipped but %q", d.Lists[1].Skipped)
+	}
+}
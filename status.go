@@ -3,6 +3,8 @@ package mastodon
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -24,7 +26,7 @@ type Status struct {
 	Reblog             *Status        `json:"reblog"`
 	Content            string         `json:"content"`
 	CreatedAt          time.Time      `json:"created_at"`
-	EditedAt           time.Time      `json:"edited_at"`
+	EditedAt           NullTime       `json:"edited_at"`
 	Emojis             []Emoji        `json:"emojis"`
 	RepliesCount       int64          `json:"replies_count"`
 	ReblogsCount       int64          `json:"reblogs_count"`
@@ -35,7 +37,7 @@ type Status struct {
 	Muted              interface{}    `json:"muted"`
 	Sensitive          bool           `json:"sensitive"`
 	SpoilerText        string         `json:"spoiler_text"`
-	Visibility         string         `json:"visibility"`
+	Visibility         Visibility     `json:"visibility"`
 	MediaAttachments   []Attachment   `json:"media_attachments"`
 	Mentions           []Mention      `json:"mentions"`
 	Tags               []Tag          `json:"tags"`
@@ -45,6 +47,13 @@ type Status struct {
 	Language           string         `json:"language"`
 	Pinned             interface{}    `json:"pinned"`
 	Filtered           []FilterResult `json:"filtered"`
+
+	// Pleroma holds the pleroma object Pleroma and Akkoma add to statuses,
+	// with fields like emoji_reactions and local.
+	Pleroma json.RawMessage `json:"pleroma,omitempty"`
+	// Extra holds the members of the JSON object without a field, like
+	// the additions of other servers, when Client.ExtraFields is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // StatusHistory is a struct to hold status history data.
@@ -66,18 +75,18 @@ type Context struct {
 
 // Card holds information for a mastodon card.
 type Card struct {
-	URL          string `json:"url"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	Image        string `json:"image"`
-	Type         string `json:"type"`
-	AuthorName   string `json:"author_name"`
-	AuthorURL    string `json:"author_url"`
-	ProviderName string `json:"provider_name"`
-	ProviderURL  string `json:"provider_url"`
-	HTML         string `json:"html"`
-	Width        int64  `json:"width"`
-	Height       int64  `json:"height"`
+	URL          string   `json:"url"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Image        string   `json:"image"`
+	Type         CardType `json:"type"`
+	AuthorName   string   `json:"author_name"`
+	AuthorURL    string   `json:"author_url"`
+	ProviderName string   `json:"provider_name"`
+	ProviderURL  string   `json:"provider_url"`
+	HTML         string   `json:"html"`
+	Width        int64    `json:"width"`
+	Height       int64    `json:"height"`
 }
 
 // Source holds source properties so a status can be edited.
@@ -101,12 +110,44 @@ type Media struct {
 	Thumbnail   io.Reader
 	Description string
 	Focus       string
+
+	// Progress, if set, is called as the upload is sent.
+	Progress ProgressFunc
+
+	// fileName is the name of File when it was an *os.File wrapped to
+	// sniff its type.
+	fileName string
+}
+
+// MediaUpdate holds the attributes to update on a media attachment.
+type MediaUpdate struct {
+	// If it is nil it will not be updated.
+	// If it is empty, update it with empty.
+	Description *string
+	// Focus is "x,y" with both coordinates between -1.0 and 1.0. If it is
+	// empty it will not be updated.
+	Focus string
+	// Thumbnail replaces the preview image, usually of audio and video. If
+	// it is nil it will not be updated.
+	Thumbnail io.Reader
+}
+
+// multipartForm is a request body sent as multipart/form-data.
+type multipartForm interface {
+	bodyAndContentType() (io.Reader, string, error)
 }
 
+// TagData holds the additional tags of a hashtag timeline, for
+// GetTimelineHashtagMultiple.
 type TagData struct {
-	Any  []string
-	All  []string
+	// Any returns the statuses with any of these tags too.
+	Any []string
+	// All only returns the statuses with all of these tags too.
+	All []string
+	// None skips the statuses with any of these tags.
 	None []string
+	// OnlyMedia only returns statuses with media attachments.
+	OnlyMedia bool
 }
 
 func (m *Media) bodyAndContentType() (io.Reader, string, error) {
@@ -114,7 +155,9 @@ func (m *Media) bodyAndContentType() (io.Reader, string, error) {
 	mw := multipart.NewWriter(&buf)
 
 	fileName := "upload"
-	if f, ok := m.File.(*os.File); ok {
+	if m.fileName != "" {
+		fileName = m.fileName
+	} else if f, ok := m.File.(*os.File); ok {
 		fileName = f.Name()
 	}
 	file, err := mw.CreateFormFile("file", fileName)
@@ -166,6 +209,88 @@ func (m *Media) bodyAndContentType() (io.Reader, string, error) {
 	return &buf, mw.FormDataContentType(), nil
 }
 
+func (u *MediaUpdate) bodyAndContentType() (io.Reader, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	if u.Description != nil {
+		if err := mw.WriteField("description", *u.Description); err != nil {
+			return nil, "", err
+		}
+	}
+	if u.Focus != "" {
+		if err := mw.WriteField("focus", u.Focus); err != nil {
+			return nil, "", err
+		}
+	}
+	if u.Thumbnail != nil {
+		thumbName := "upload"
+		if f, ok := u.Thumbnail.(*os.File); ok {
+			thumbName = f.Name()
+		}
+		thumb, err := mw.CreateFormFile("thumbnail", thumbName)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(thumb, u.Thumbnail); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, mw.FormDataContentType(), nil
+}
+
+// ApplicationFilter selects statuses by the client application they were
+// posted with. Names are compared case-insensitively against
+// Status.Application.Name; for reblogs the application of the reblogged
+// status is used.
+//
+// If Include is not empty, only statuses posted with one of the listed
+// applications match. Statuses posted with an application listed in Exclude
+// never match. Statuses without application information only match when
+// Include is empty.
+type ApplicationFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Match reports whether the status passes the filter.
+func (f *ApplicationFilter) Match(s *Status) bool {
+	if s.Reblog != nil {
+		s = s.Reblog
+	}
+	name := s.Application.Name
+	for _, ex := range f.Exclude {
+		if name != "" && strings.EqualFold(name, ex) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, in := range f.Include {
+		if name != "" && strings.EqualFold(name, in) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the statuses that pass the filter.
+func (f *ApplicationFilter) Filter(statuses []*Status) []*Status {
+	var filtered []*Status
+	for _, s := range statuses {
+		if f.Match(s) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
 // GetFavourites returns the favorite list of the current user.
 func (c *Client) GetFavourites(ctx context.Context, pg *Pagination) ([]*Status, error) {
 	var statuses []*Status
@@ -258,8 +383,21 @@ func (c *Client) GetFavouritedBy(ctx context.Context, id ID, pg *Pagination) ([]
 
 // Reblog reblogs the toot of id and returns status of reblog.
 func (c *Client) Reblog(ctx context.Context, id ID) (*Status, error) {
+	return c.ReblogWithVisibility(ctx, id, "")
+}
+
+// ReblogWithVisibility reblogs the toot of id with visibility, which is
+// public, unlisted or private; empty is public.
+func (c *Client) ReblogWithVisibility(ctx context.Context, id ID, visibility Visibility) (*Status, error) {
+	var params url.Values
+	if visibility != "" {
+		if visibility == VisibilityDirect {
+			return nil, fmt.Errorf("visibility %q can't be used to reblog", visibility)
+		}
+		params = url.Values{"visibility": {string(visibility)}}
+	}
 	var status Status
-	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/statuses/%s/reblog", id), nil, &status, nil)
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/statuses/%s/reblog", id), params, &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -316,6 +454,67 @@ func (c *Client) Unbookmark(ctx context.Context, id ID) (*Status, error) {
 	return &status, nil
 }
 
+// Pin pins the toot of id to the profile of the current user. The number of
+// pinned toots is limited; see ProfileFeatures to check the limit first.
+func (c *Client) Pin(ctx context.Context, id ID) (*Status, error) {
+	var status Status
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/statuses/%s/pin", id), nil, &status, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Unpin unpins the toot of id from the profile of the current user.
+func (c *Client) Unpin(ctx context.Context, id ID) (*Status, error) {
+	var status Status
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/statuses/%s/unpin", id), nil, &status, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Translation holds a status translated by the server.
+type Translation struct {
+	Content                string                  `json:"content"`
+	SpoilerText            string                  `json:"spoiler_text"`
+	Poll                   *TranslationPoll        `json:"poll"`
+	MediaAttachments       []TranslationAttachment `json:"media_attachments"`
+	DetectedSourceLanguage string                  `json:"detected_source_language"`
+	Provider               string                  `json:"provider"`
+}
+
+// TranslationPoll holds the translated options of a poll.
+type TranslationPoll struct {
+	ID      ID `json:"id"`
+	Options []struct {
+		Title string `json:"title"`
+	} `json:"options"`
+}
+
+// TranslationAttachment holds the translated description of a media attachment.
+type TranslationAttachment struct {
+	ID          ID     `json:"id"`
+	Description string `json:"description"`
+}
+
+// TranslateStatus translates the status of id into lang. If lang is empty,
+// the language of the current user is used.
+func (c *Client) TranslateStatus(ctx context.Context, id ID, lang string) (*Translation, error) {
+	params := url.Values{}
+	if lang != "" {
+		params.Set("lang", lang)
+	}
+
+	var translation Translation
+	err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("/api/v1/statuses/%s/translate", id), params, &translation, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
 // GetTimelineHome return statuses from home timeline.
 func (c *Client) GetTimelineHome(ctx context.Context, pg *Pagination) ([]*Status, error) {
 	var statuses []*Status
@@ -328,9 +527,34 @@ func (c *Client) GetTimelineHome(ctx context.Context, pg *Pagination) ([]*Status
 
 // GetTimelinePublic return statuses from public timeline.
 func (c *Client) GetTimelinePublic(ctx context.Context, isLocal bool, pg *Pagination) ([]*Status, error) {
+	return c.GetTimelinePublicWithOptions(ctx, &PublicTimelineOptions{Local: isLocal}, pg)
+}
+
+// PublicTimelineOptions filters the statuses returned by
+// GetTimelinePublicWithOptions.
+type PublicTimelineOptions struct {
+	// Local only returns statuses of the instance.
+	Local bool
+	// Remote only returns statuses of other instances.
+	Remote bool
+	// OnlyMedia only returns statuses with media attachments.
+	OnlyMedia bool
+}
+
+// GetTimelinePublicWithOptions returns statuses from the public timeline
+// filtered by opts, which may be nil.
+func (c *Client) GetTimelinePublicWithOptions(ctx context.Context, opts *PublicTimelineOptions, pg *Pagination) ([]*Status, error) {
 	params := url.Values{}
-	if isLocal {
-		params.Set("local", "t")
+	if opts != nil {
+		if opts.Local {
+			params.Set("local", "true")
+		}
+		if opts.Remote {
+			params.Set("remote", "true")
+		}
+		if opts.OnlyMedia {
+			params.Set("only_media", "true")
+		}
 	}
 
 	var statuses []*Status
@@ -356,7 +580,8 @@ func (c *Client) GetTimelineHashtag(ctx context.Context, tag string, isLocal boo
 	return statuses, nil
 }
 
-// GetTimelineHashtag return statuses from tagged timeline.
+// GetTimelineHashtagMultiple return statuses from tagged timeline with the
+// additional tags of td.
 func (c *Client) GetTimelineHashtagMultiple(ctx context.Context, tag string, isLocal bool, td *TagData, pg *Pagination) ([]*Status, error) {
 	params := url.Values{}
 	if isLocal {
@@ -372,6 +597,9 @@ func (c *Client) GetTimelineHashtagMultiple(ctx context.Context, tag string, isL
 		for _, v := range td.None {
 			params.Add("none[]", v)
 		}
+		if td.OnlyMedia {
+			params.Set("only_media", "true")
+		}
 	}
 
 	var statuses []*Status
@@ -392,6 +620,20 @@ func (c *Client) GetTimelineList(ctx context.Context, id ID, pg *Pagination) ([]
 	return statuses, nil
 }
 
+// GetTimelineLink returns the statuses about the trending link u, since
+// Mastodon 4.3.
+func (c *Client) GetTimelineLink(ctx context.Context, u string, pg *Pagination) ([]*Status, error) {
+	params := url.Values{}
+	params.Set("url", u)
+
+	var statuses []*Status
+	err := c.doAPI(ctx, http.MethodGet, "/api/v1/timelines/link", params, &statuses, pg)
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
 // GetTimelineMedia return statuses from media timeline.
 // NOTE: This is an experimental feature of pawoo.net.
 func (c *Client) GetTimelineMedia(ctx context.Context, isLocal bool, pg *Pagination) ([]*Status, error) {
@@ -409,9 +651,29 @@ func (c *Client) GetTimelineMedia(ctx context.Context, isLocal bool, pg *Paginat
 	return statuses, nil
 }
 
+// PostStatusOptions are options to post a status.
+type PostStatusOptions struct {
+	// IdempotencyKey makes the server return the status posted with the same
+	// key in the last hour, if any, instead of posting it again. Empty is a
+	// new key, which only protects the retries of this call.
+	IdempotencyKey string
+}
+
 // PostStatus post the toot.
 func (c *Client) PostStatus(ctx context.Context, toot *Toot) (*Status, error) {
-	return c.postStatus(ctx, toot, false, ID("none"))
+	return c.PostStatusWithOptions(ctx, toot, nil)
+}
+
+// PostStatusWithOptions posts the toot with opts, which may be nil.
+func (c *Client) PostStatusWithOptions(ctx context.Context, toot *Toot, opts *PostStatusOptions) (*Status, error) {
+	key := ""
+	if opts != nil {
+		key = opts.IdempotencyKey
+	}
+	if key == "" {
+		key = NewIdempotencyKey()
+	}
+	return c.postStatus(withHeader(ctx, "Idempotency-Key", key), toot, false, ID("none"))
 }
 
 // UpdateStatus updates the toot.
@@ -444,10 +706,10 @@ func (c *Client) postStatus(ctx context.Context, toot *Toot, update bool, update
 		}
 	}
 	if toot.Visibility != "" {
-		params.Set("visibility", fmt.Sprint(toot.Visibility))
+		params.Set("visibility", string(toot.Visibility))
 	}
 	if toot.Language != "" {
-		params.Set("language", fmt.Sprint(toot.Language))
+		params.Set("language", toot.Language)
 	}
 	if toot.Sensitive {
 		params.Set("sensitive", "true")
@@ -455,6 +717,18 @@ func (c *Client) postStatus(ctx context.Context, toot *Toot, update bool, update
 	if toot.SpoilerText != "" {
 		params.Set("spoiler_text", toot.SpoilerText)
 	}
+	if update {
+		// Encode sorts the keys, so the attributes of each media are
+		// grouped by index rather than by order.
+		for i, m := range toot.MediaAttributes {
+			prefix := fmt.Sprintf("media_attributes[%d]", i)
+			params.Set(prefix+"[id]", string(m.ID))
+			params.Set(prefix+"[description]", m.Description)
+			if m.Focus != "" {
+				params.Set(prefix+"[focus]", m.Focus)
+			}
+		}
+	}
 
 	var status Status
 	var err error
@@ -508,6 +782,12 @@ func (c *Client) UploadMediaFromReader(ctx context.Context, reader io.Reader) (*
 	return c.UploadMediaFromMedia(ctx, &Media{File: reader})
 }
 
+// UploadMediaFromReaderWithProgress uploads a media attachment from an
+// io.Reader, calling progress as it is sent.
+func (c *Client) UploadMediaFromReaderWithProgress(ctx context.Context, reader io.Reader, progress ProgressFunc) (*Attachment, error) {
+	return c.UploadMediaFromMedia(ctx, &Media{File: reader, Progress: progress})
+}
+
 // UploadMediaFromMedia uploads a media attachment from a Media struct.
 func (c *Client) UploadMediaFromMedia(ctx context.Context, media *Media) (*Attachment, error) {
 	var attachment Attachment
@@ -517,6 +797,34 @@ func (c *Client) UploadMediaFromMedia(ctx context.Context, media *Media) (*Attac
 	return &attachment, nil
 }
 
+// GetMedia returns the media attachment of id. Only attachments of the
+// current user that aren't attached to a status yet can be fetched.
+func (c *Client) GetMedia(ctx context.Context, id ID) (*Attachment, error) {
+	var attachment Attachment
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("/api/v1/media/%s", url.PathEscape(string(id))), nil, &attachment, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// UpdateMediaAttachment updates the description, focal point and thumbnail
+// of the media attachment of id, which must not be attached to a status
+// yet. To update media attached to a status, edit the status with
+// Toot.MediaAttributes instead.
+func (c *Client) UpdateMediaAttachment(ctx context.Context, id ID, params *MediaUpdate) (*Attachment, error) {
+	if params == nil {
+		return nil, errors.New("params can't be nil")
+	}
+
+	var attachment Attachment
+	err := c.doAPI(ctx, http.MethodPut, fmt.Sprintf("/api/v1/media/%s", url.PathEscape(string(id))), params, &attachment, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
 // GetTimelineDirect return statuses from direct timeline.
 func (c *Client) GetTimelineDirect(ctx context.Context, pg *Pagination) ([]*Status, error) {
 	params := url.Values{}
@@ -0,0 +1,274 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// StatusBuilder composes a status and checks it against the limits of the
+// instance before posting it, instead of having the server reject it.
+type StatusBuilder struct {
+	// Limits are the limits of the instance, from InstanceInfo. Zero
+	// limits aren't checked.
+	Limits InstanceLimits
+
+	toot  Toot
+	media []*Media
+}
+
+// NewStatusBuilder returns a StatusBuilder checking statuses against limits.
+func NewStatusBuilder(limits InstanceLimits) *StatusBuilder {
+	return &StatusBuilder{Limits: limits}
+}
+
+// Text sets the text of the status.
+func (b *StatusBuilder) Text(text string) *StatusBuilder {
+	b.toot.Status = text
+	return b
+}
+
+// SpoilerText sets the content warning of the status.
+func (b *StatusBuilder) SpoilerText(text string) *StatusBuilder {
+	b.toot.SpoilerText = text
+	return b
+}
+
+// Sensitive marks the media of the status as sensitive.
+func (b *StatusBuilder) Sensitive(sensitive bool) *StatusBuilder {
+	b.toot.Sensitive = sensitive
+	return b
+}
+
+// Visibility sets the visibility of the status.
+func (b *StatusBuilder) Visibility(visibility Visibility) *StatusBuilder {
+	b.toot.Visibility = visibility
+	return b
+}
+
+// Language sets the ISO 639 language code of the status.
+func (b *StatusBuilder) Language(language string) *StatusBuilder {
+	b.toot.Language = language
+	return b
+}
+
+// InReplyTo makes the status a reply to the status id.
+func (b *StatusBuilder) InReplyTo(id ID) *StatusBuilder {
+	b.toot.InReplyToID = id
+	return b
+}
+
+// MediaIDs attaches media already uploaded.
+func (b *StatusBuilder) MediaIDs(ids ...ID) *StatusBuilder {
+	b.toot.MediaIDs = append(b.toot.MediaIDs, ids...)
+	return b
+}
+
+// Media attaches media to upload when the status is posted. The first 512
+// bytes of their files are read to check their type.
+func (b *StatusBuilder) Media(media ...*Media) *StatusBuilder {
+	for _, m := range media {
+		// Sniffing replaces the file with a reader putting back the bytes
+		// it read, so it is done on a copy.
+		m := *m
+		b.media = append(b.media, &m)
+	}
+	return b
+}
+
+// Poll attaches a poll ending after expiresIn.
+func (b *StatusBuilder) Poll(expiresIn time.Duration, multiple bool, options ...string) *StatusBuilder {
+	b.toot.Poll = &TootPoll{
+		Options:          options,
+		ExpiresInSeconds: int64(expiresIn / time.Second),
+		Multiple:         multiple,
+	}
+	return b
+}
+
+// HideTotals hides the votes of the poll until it ends.
+func (b *StatusBuilder) HideTotals(hide bool) *StatusBuilder {
+	if b.toot.Poll != nil {
+		b.toot.Poll.HideTotals = hide
+	}
+	return b
+}
+
+// StatusValidationError is a problem found in a status by
+// StatusBuilder.Validate.
+type StatusValidationError struct {
+	// Field is the parameter of the status at fault, like "status",
+	// "language", "media_ids" or "poll".
+	Field   string
+	Message string
+	// Limit is the limit of the instance that Value exceeds, if any.
+	Limit int
+	Value int
+}
+
+func (e *StatusValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// StatusValidationErrors holds all the problems found in a status.
+type StatusValidationErrors []*StatusValidationError
+
+func (e StatusValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the status against the limits of the instance. It returns
+// StatusValidationErrors if the server would reject the status.
+func (b *StatusBuilder) Validate() error {
+	var errs StatusValidationErrors
+	add := func(field, message string, limit, value int) {
+		errs = append(errs, &StatusValidationError{Field: field, Message: message, Limit: limit, Value: value})
+	}
+	l := b.Limits
+	toot := &b.toot
+
+	media := len(toot.MediaIDs) + len(b.media)
+	if strings.TrimSpace(toot.Status) == "" && media == 0 && toot.Poll == nil {
+		add("status", "can't be empty", 0, 0)
+	}
+	n := StatusLength(toot.Status, l.CharactersReservedPerURL) + utf8.RuneCountInString(toot.SpoilerText)
+	if l.MaxCharacters > 0 && n > l.MaxCharacters {
+		add("status", fmt.Sprintf("is %d characters long, over the limit of %d", n, l.MaxCharacters), l.MaxCharacters, n)
+	}
+
+	if toot.Language != "" && !ValidLanguage(toot.Language) {
+		add("language", fmt.Sprintf("%q isn't an ISO 639 code", toot.Language), 0, 0)
+	}
+
+	if l.MaxMediaAttachments > 0 && media > l.MaxMediaAttachments {
+		add("media_ids", fmt.Sprintf("has %d attachments, over the limit of %d", media, l.MaxMediaAttachments), l.MaxMediaAttachments, media)
+	}
+	for _, m := range b.media {
+		typ, err := m.detectContentType()
+		if err != nil {
+			add("media_ids", err.Error(), 0, 0)
+		} else if typ != "" && len(l.SupportedMimeTypes) > 0 && !containsString(l.SupportedMimeTypes, typ) {
+			add("media_ids", fmt.Sprintf("type %s isn't supported", typ), 0, 0)
+		}
+	}
+
+	if p := toot.Poll; p != nil {
+		if media > 0 {
+			add("poll", "can't be attached with media", 0, 0)
+		}
+		if len(p.Options) < 2 {
+			add("poll", "needs at least two options", 2, len(p.Options))
+		}
+		if l.MaxPollOptions > 0 && len(p.Options) > l.MaxPollOptions {
+			add("poll", fmt.Sprintf("has %d options, over the limit of %d", len(p.Options), l.MaxPollOptions), l.MaxPollOptions, len(p.Options))
+		}
+		for _, o := range p.Options {
+			if n := utf8.RuneCountInString(o); l.MaxPollOptionCharacters > 0 && n > l.MaxPollOptionCharacters {
+				add("poll", fmt.Sprintf("option %q is %d characters long, over the limit of %d", o, n, l.MaxPollOptionCharacters), l.MaxPollOptionCharacters, n)
+			}
+		}
+		expiresIn := int(p.ExpiresInSeconds)
+		if l.MinPollExpiration > 0 && expiresIn < l.MinPollExpiration {
+			add("poll", fmt.Sprintf("expires in %ds, under the minimum of %ds", expiresIn, l.MinPollExpiration), l.MinPollExpiration, expiresIn)
+		}
+		if l.MaxPollExpiration > 0 && expiresIn > l.MaxPollExpiration {
+			add("poll", fmt.Sprintf("expires in %ds, over the maximum of %ds", expiresIn, l.MaxPollExpiration), l.MaxPollExpiration, expiresIn)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Toot validates the status and returns it. Media to upload are not
+// included.
+func (b *StatusBuilder) Toot() (*Toot, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	toot := b.toot
+	return &toot, nil
+}
+
+// Post validates the status, uploads its media and posts it with c.
+func (b *StatusBuilder) Post(ctx context.Context, c *Client) (*Status, error) {
+	toot, err := b.Toot()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range b.media {
+		a, err := c.UploadMediaFromMedia(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		toot.MediaIDs = append(toot.MediaIDs, a.ID)
+	}
+	return c.PostStatus(ctx, toot)
+}
+
+var (
+	reStatusURL     = regexp.MustCompile(`https?://[^\s<>"]+`)
+	reRemoteMention = regexp.MustCompile(`(^|[^\w/])@(\w+)@[\w.-]+\w`)
+)
+
+// StatusLength returns the length of text as counted by Mastodon: URLs count
+// as charactersReservedPerURL characters, 23 if zero, and mentions of
+// remote accounts count without their domain.
+func StatusLength(text string, charactersReservedPerURL int) int {
+	if charactersReservedPerURL <= 0 {
+		charactersReservedPerURL = 23
+	}
+	text = reRemoteMention.ReplaceAllString(text, "$1@$2")
+	urls := reStatusURL.FindAllString(text, -1)
+	text = reStatusURL.ReplaceAllString(text, "")
+	return utf8.RuneCountInString(text) + len(urls)*charactersReservedPerURL
+}
+
+// detectContentType returns the MIME type of the file of m, or "" if it
+// isn't recognized. It reads the first 512 bytes of the file, which are all
+// http.DetectContentType considers, and puts them back in front of it.
+func (m *Media) detectContentType() (string, error) {
+	if m.File == nil {
+		return "", fmt.Errorf("media has no file")
+	}
+	b := make([]byte, 512)
+	n, err := io.ReadFull(m.File, b)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	b = b[:n]
+	if f, ok := m.File.(*os.File); ok {
+		m.fileName = f.Name()
+	}
+	m.File = io.MultiReader(bytes.NewReader(b), m.File)
+	typ := http.DetectContentType(b)
+	if i := strings.IndexByte(typ, ';'); i >= 0 {
+		typ = typ[:i]
+	}
+	if typ == "application/octet-stream" || strings.HasPrefix(typ, "text/") {
+		return "", nil
+	}
+	return typ, nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestStatusLength(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"hello", 5},
+		{"héllo 🐘", 7},
+		{"see https://example.com/a/very/long/path/that/goes/on?and=on", 4 + 23},
+		{"hi @foo@example.social and @bar", len("hi @foo and @bar")},
+		{"mail me@example.com", len("mail me@example.com")},
+	}
+	for _, test := range tests {
+		if got := StatusLength(test.text, 0); got != test.want {
+			t.Fatalf("want %d but %d for %q", test.want, got, test.text)
+		}
+	}
+	if got := StatusLength("http://a.b", 5); got != 5 {
+		t.Fatalf("want %d but %d", 5, got)
+	}
+}
+
+func TestStatusBuilderValidate(t *testing.T) {
+	limits := InstanceLimits{
+		MaxCharacters:           10,
+		MaxMediaAttachments:     1,
+		MaxPollOptions:          2,
+		MaxPollOptionCharacters: 3,
+		MinPollExpiration:       300,
+		MaxPollExpiration:       3600,
+		SupportedMimeTypes:      []string{"image/jpeg"},
+	}
+
+	if err := NewStatusBuilder(limits).Text("https://example.com/" + strings.Repeat("a", 50)).Validate(); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	} else if errs := err.(StatusValidationErrors); len(errs) != 1 || errs[0].Field != "status" || errs[0].Value != 23 {
+		t.Fatalf("want %q but %v", "status", err)
+	}
+	if err := NewStatusBuilder(InstanceLimits{}).Text("https://example.com/" + strings.Repeat("a", 50)).Validate(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if err := NewStatusBuilder(limits).Validate(); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if err := NewStatusBuilder(limits).Text("hej").Language("se-SV").Visibility("local").Validate(); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	} else if errs := err.(StatusValidationErrors); len(errs) != 1 || errs[0].Field != "language" {
+		t.Fatalf("want %q but %v", "language", err)
+	}
+	if err := NewStatusBuilder(limits).Text("hej").Language("pt-BR").Validate(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	err := NewStatusBuilder(limits).
+		Text("ok").
+		MediaIDs("1").
+		Media(&Media{File: bytes.NewReader(pngHeader)}).
+		Poll(time.Minute, false, "yes", "no", "maybe").
+		Validate()
+	errs, ok := err.(StatusValidationErrors)
+	if !ok {
+		t.Fatalf("want StatusValidationErrors but %T: %v", err, err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Field+" "+e.Message)
+	}
+	want := []string{
+		"media_ids has 2 attachments, over the limit of 1",
+		"media_ids type image/png isn't supported",
+		"poll can't be attached with media",
+		"poll has 3 options, over the limit of 2",
+		`poll option "maybe" is 5 characters long, over the limit of 3`,
+		"poll expires in 60s, under the minimum of 300s",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("want %q but %q", want, got)
+	}
+	if !strings.HasPrefix(err.Error(), "media_ids: has 2 attachments") {
+		t.Fatalf("want %q but %q", "media_ids: has 2 attachments", err.Error())
+	}
+
+	toot, err := NewStatusBuilder(limits).Text("vote").Poll(time.Hour, true, "yes", "no").HideTotals(true).Toot()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if toot.Poll.ExpiresInSeconds != 3600 || !toot.Poll.Multiple || !toot.Poll.HideTotals {
+		t.Fatalf("want %d but %d", 3600, toot.Poll.ExpiresInSeconds)
+	}
+}
+
+func TestStatusBuilderPost(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/media":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fh := r.MultipartForm.File["file"]
+			if len(fh) != 1 || fh[0].Filename != "cat.png" || fh[0].Size != 1024 {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `{"id": "55"}`)
+			return
+		case "/api/v1/statuses":
+			if r.FormValue("media_ids[]") != "55" || r.FormValue("spoiler_text") != "cw" {
+				http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+				return
+			}
+			json.NewEncoder(w).Encode(&Status{ID: "1", Content: r.FormValue("status")})
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "mastodon-media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "cat.png")
+	if err := ioutil.WriteFile(name, append(pngHeader, make([]byte, 1024-len(pngHeader))...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	limits := InstanceLimits{MaxCharacters: 500, SupportedMimeTypes: []string{"image/png"}}
+	media := &Media{File: f, Description: "a png"}
+	s, err := NewStatusBuilder(limits).
+		Text("look").
+		SpoilerText("cw").
+		Media(media).
+		Post(context.Background(), client)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if media.File != f {
+		t.Fatalf("media of the caller should not be changed: %T", media.File)
+	}
+	if s.Content != "look" {
+		t.Fatalf("want %q but %q", "look", s.Content)
+	}
+
+	_, err = NewStatusBuilder(InstanceLimits{MaxCharacters: 3}).Text("too long").Post(context.Background(), client)
+	if _, ok := err.(StatusValidationErrors); !ok {
+		t.Fatalf("want StatusValidationErrors but %T: %v", err, err)
+	}
+}
@@ -0,0 +1,372 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // decode gif previews
+	_ "image/jpeg" // decode jpeg previews
+	"image/png"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Font draws the text of a status image. Implement it on top of a
+// golang.org/x/image/font.Face to render with TrueType fonts.
+type Font interface {
+	// Measure returns the width of s in pixels.
+	Measure(s string) int
+	// LineHeight returns the height of a line in pixels.
+	LineHeight() int
+	// Draw draws s with the top left corner of its line at pt.
+	Draw(dst draw.Image, pt image.Point, s string, c color.Color)
+}
+
+// BitmapFont is a built-in 5x7 pixel font covering printable ASCII. Other
+// runes are drawn as '?'.
+type BitmapFont struct {
+	// Scale multiplies the size of the glyphs. Zero means 1.
+	Scale int
+}
+
+func (f *BitmapFont) scale() int {
+	if f.Scale <= 0 {
+		return 1
+	}
+	return f.Scale
+}
+
+// Measure implements Font.
+func (f *BitmapFont) Measure(s string) int {
+	return utf8.RuneCountInString(s) * 6 * f.scale()
+}
+
+// LineHeight implements Font.
+func (f *BitmapFont) LineHeight() int {
+	return 10 * f.scale()
+}
+
+// Draw implements Font.
+func (f *BitmapFont) Draw(dst draw.Image, pt image.Point, s string, c color.Color) {
+	scale := f.scale()
+	src := image.NewUniform(c)
+	x := pt.X
+	for _, r := range s {
+		if r < ' ' || r > '~' {
+			r = '?'
+		}
+		glyph := bitmapGlyphs[r-' ']
+		for col, bits := range glyph {
+			for row := 0; row < 7; row++ {
+				if bits&(1<<uint(row)) == 0 {
+					continue
+				}
+				px := image.Rect(0, 0, scale, scale).Add(image.Pt(x+col*scale, pt.Y+(row+1)*scale))
+				draw.Draw(dst, px, src, image.Point{}, draw.Over)
+			}
+		}
+		x += 6 * scale
+	}
+}
+
+// bitmapGlyphs holds the columns of the printable ASCII glyphs, the least
+// significant bit being the top row.
+var bitmapGlyphs = [95][5]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00}, {0x00, 0x00, 0x5f, 0x00, 0x00}, {0x00, 0x07, 0x00, 0x07, 0x00}, {0x14, 0x7f, 0x14, 0x7f, 0x14},
+	{0x24, 0x2a, 0x7f, 0x2a, 0x12}, {0x23, 0x13, 0x08, 0x64, 0x62}, {0x36, 0x49, 0x55, 0x22, 0x50}, {0x00, 0x05, 0x03, 0x00, 0x00},
+	{0x00, 0x1c, 0x22, 0x41, 0x00}, {0x00, 0x41, 0x22, 0x1c, 0x00}, {0x14, 0x08, 0x3e, 0x08, 0x14}, {0x08, 0x08, 0x3e, 0x08, 0x08},
+	{0x00, 0x50, 0x30, 0x00, 0x00}, {0x08, 0x08, 0x08, 0x08, 0x08}, {0x00, 0x60, 0x60, 0x00, 0x00}, {0x20, 0x10, 0x08, 0x04, 0x02},
+	{0x3e, 0x51, 0x49, 0x45, 0x3e}, {0x00, 0x42, 0x7f, 0x40, 0x00}, {0x42, 0x61, 0x51, 0x49, 0x46}, {0x21, 0x41, 0x45, 0x4b, 0x31},
+	{0x18, 0x14, 0x12, 0x7f, 0x10}, {0x27, 0x45, 0x45, 0x45, 0x39}, {0x3c, 0x4a, 0x49, 0x49, 0x30}, {0x01, 0x71, 0x09, 0x05, 0x03},
+	{0x36, 0x49, 0x49, 0x49, 0x36}, {0x06, 0x49, 0x49, 0x29, 0x1e}, {0x00, 0x36, 0x36, 0x00, 0x00}, {0x00, 0x56, 0x36, 0x00, 0x00},
+	{0x08, 0x14, 0x22, 0x41, 0x00}, {0x14, 0x14, 0x14, 0x14, 0x14}, {0x00, 0x41, 0x22, 0x14, 0x08}, {0x02, 0x01, 0x51, 0x09, 0x06},
+	{0x32, 0x49, 0x79, 0x41, 0x3e}, {0x7e, 0x11, 0x11, 0x11, 0x7e}, {0x7f, 0x49, 0x49, 0x49, 0x36}, {0x3e, 0x41, 0x41, 0x41, 0x22},
+	{0x7f, 0x41, 0x41, 0x22, 0x1c}, {0x7f, 0x49, 0x49, 0x49, 0x41}, {0x7f, 0x09, 0x09, 0x09, 0x01}, {0x3e, 0x41, 0x49, 0x49, 0x7a},
+	{0x7f, 0x08, 0x08, 0x08, 0x7f}, {0x00, 0x41, 0x7f, 0x41, 0x00}, {0x20, 0x40, 0x41, 0x3f, 0x01}, {0x7f, 0x08, 0x14, 0x22, 0x41},
+	{0x7f, 0x40, 0x40, 0x40, 0x40}, {0x7f, 0x02, 0x0c, 0x02, 0x7f}, {0x7f, 0x04, 0x08, 0x10, 0x7f}, {0x3e, 0x41, 0x41, 0x41, 0x3e},
+	{0x7f, 0x09, 0x09, 0x09, 0x06}, {0x3e, 0x41, 0x51, 0x21, 0x5e}, {0x7f, 0x09, 0x19, 0x29, 0x46}, {0x46, 0x49, 0x49, 0x49, 0x31},
+	{0x01, 0x01, 0x7f, 0x01, 0x01}, {0x3f, 0x40, 0x40, 0x40, 0x3f}, {0x1f, 0x20, 0x40, 0x20, 0x1f}, {0x3f, 0x40, 0x38, 0x40, 0x3f},
+	{0x63, 0x14, 0x08, 0x14, 0x63}, {0x07, 0x08, 0x70, 0x08, 0x07}, {0x61, 0x51, 0x49, 0x45, 0x43}, {0x00, 0x7f, 0x41, 0x41, 0x00},
+	{0x02, 0x04, 0x08, 0x10, 0x20}, {0x00, 0x41, 0x41, 0x7f, 0x00}, {0x04, 0x02, 0x01, 0x02, 0x04}, {0x40, 0x40, 0x40, 0x40, 0x40},
+	{0x00, 0x01, 0x02, 0x04, 0x00}, {0x20, 0x54, 0x54, 0x54, 0x78}, {0x7f, 0x48, 0x44, 0x44, 0x38}, {0x38, 0x44, 0x44, 0x44, 0x20},
+	{0x38, 0x44, 0x44, 0x48, 0x7f}, {0x38, 0x54, 0x54, 0x54, 0x18}, {0x08, 0x7e, 0x09, 0x01, 0x02}, {0x0c, 0x52, 0x52, 0x52, 0x3e},
+	{0x7f, 0x08, 0x04, 0x04, 0x78}, {0x00, 0x44, 0x7d, 0x40, 0x00}, {0x20, 0x40, 0x44, 0x3d, 0x00}, {0x00, 0x7f, 0x10, 0x28, 0x44},
+	{0x00, 0x41, 0x7f, 0x40, 0x00}, {0x7c, 0x04, 0x18, 0x04, 0x78}, {0x7c, 0x08, 0x04, 0x04, 0x78}, {0x38, 0x44, 0x44, 0x44, 0x38},
+	{0x7c, 0x14, 0x14, 0x14, 0x08}, {0x08, 0x14, 0x14, 0x18, 0x7c}, {0x7c, 0x08, 0x04, 0x04, 0x08}, {0x48, 0x54, 0x54, 0x54, 0x20},
+	{0x04, 0x3f, 0x44, 0x40, 0x20}, {0x3c, 0x40, 0x40, 0x20, 0x7c}, {0x1c, 0x20, 0x40, 0x20, 0x1c}, {0x3c, 0x40, 0x30, 0x40, 0x3c},
+	{0x44, 0x28, 0x10, 0x28, 0x44}, {0x0c, 0x50, 0x50, 0x50, 0x3c}, {0x44, 0x64, 0x54, 0x4c, 0x44}, {0x00, 0x08, 0x36, 0x41, 0x00},
+	{0x00, 0x00, 0x7f, 0x00, 0x00}, {0x00, 0x41, 0x36, 0x08, 0x00}, {0x10, 0x08, 0x08, 0x10, 0x08},
+}
+
+// Theme holds the colors of a status image.
+type Theme struct {
+	Background color.Color
+	Foreground color.Color
+	Muted      color.Color
+	Accent     color.Color
+}
+
+// Themes for StatusRenderer.
+var (
+	LightTheme = Theme{
+		Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Foreground: color.RGBA{0x28, 0x2c, 0x37, 0xff},
+		Muted:      color.RGBA{0x60, 0x69, 0x84, 0xff},
+		Accent:     color.RGBA{0x63, 0x64, 0xff, 0xff},
+	}
+	DarkTheme = Theme{
+		Background: color.RGBA{0x19, 0x1b, 0x22, 0xff},
+		Foreground: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Muted:      color.RGBA{0x9b, 0xaa, 0xc8, 0xff},
+		Accent:     color.RGBA{0x85, 0x8a, 0xfa, 0xff},
+	}
+)
+
+// StatusRenderer lays out a status into an image, for "share as image"
+// features: the author, the content as text, the media as a grid and the
+// counts.
+type StatusRenderer struct {
+	// Client fetches the avatar and media previews when set, unless it is
+	// in low bandwidth mode. Otherwise placeholders are drawn, using the
+	// blurhash of media, without any request. Sensitive media are always
+	// drawn from their blurhash.
+	Client *Client
+	// Font defaults to a BitmapFont with a scale of 2.
+	Font Font
+	// Theme defaults to LightTheme.
+	Theme *Theme
+	// Width is the width of the image in pixels, 600 by default.
+	Width int
+}
+
+// RenderPNG renders the status and writes it to w as PNG.
+func (r *StatusRenderer) RenderPNG(ctx context.Context, w io.Writer, s *Status) error {
+	img, err := r.Render(ctx, s)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// Render renders the status. Boosts render the boosted status.
+func (r *StatusRenderer) Render(ctx context.Context, s *Status) (image.Image, error) {
+	if s.Reblog != nil {
+		s = s.Reblog
+	}
+	font := r.Font
+	if font == nil {
+		font = &BitmapFont{Scale: 2}
+	}
+	theme := r.Theme
+	if theme == nil {
+		theme = &LightTheme
+	}
+	width := r.Width
+	if width <= 0 {
+		width = 600
+	}
+	lh := font.LineHeight()
+	pad, gap := lh, lh/2
+	inner := width - 2*pad
+	avatar := 2 * lh
+	if inner <= avatar+gap {
+		return nil, fmt.Errorf("width %d is too small", width)
+	}
+
+	text := statusText(s.Content)
+	if s.SpoilerText != "" {
+		text = "CW: " + s.SpoilerText + "\n\n" + text
+	}
+	lines := wrapText(font, text, inner)
+
+	media := s.MediaAttachments
+	if len(media) > 4 {
+		media = media[:4]
+	}
+	gridHeight := 0
+	if len(media) > 0 {
+		gridHeight = inner * 9 / 16
+	}
+
+	height := pad + avatar + gap + len(lines)*lh + gap + lh + pad
+	if gridHeight > 0 {
+		height += gap + gridHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Point{}, draw.Src)
+
+	// Author.
+	y := pad
+	avatarRect := image.Rect(pad, y, pad+avatar, y+avatar)
+	if av := r.fetchImage(ctx, s.Account.AvatarStatic); av != nil {
+		drawCover(img, avatarRect, av)
+	} else {
+		draw.Draw(img, avatarRect, image.NewUniform(theme.Accent), image.Point{}, draw.Src)
+		initial := strings.ToUpper(firstRune(s.Account.Username))
+		font.Draw(img, image.Pt(pad+(avatar-font.Measure(initial))/2, y+(avatar-lh)/2), initial, theme.Background)
+	}
+	name := s.Account.DisplayName
+	if name == "" {
+		name = s.Account.Username
+	}
+	textX := pad + avatar + gap
+	font.Draw(img, image.Pt(textX, y), name, theme.Foreground)
+	font.Draw(img, image.Pt(textX, y+lh), "@"+s.Account.Acct, theme.Muted)
+	y += avatar + gap
+
+	// Content.
+	for _, line := range lines {
+		font.Draw(img, image.Pt(pad, y), line, theme.Foreground)
+		y += lh
+	}
+
+	// Media.
+	if gridHeight > 0 {
+		y += gap
+		cols := 1
+		if len(media) > 1 {
+			cols = 2
+		}
+		rows := (len(media) + cols - 1) / cols
+		cellWidth := (inner - gap*(cols-1)) / cols
+		cellHeight := (gridHeight - gap*(rows-1)) / rows
+		for i, a := range media {
+			col, row := i%cols, i/cols
+			w := cellWidth
+			if i == len(media)-1 && col == 0 {
+				// The last cell of an odd count takes the whole row.
+				w = inner
+			}
+			x0, y0 := pad+col*(cellWidth+gap), y+row*(cellHeight+gap)
+			cell := image.Rect(x0, y0, x0+w, y0+cellHeight)
+			var src image.Image
+			if !s.Sensitive {
+				src = r.fetchImage(ctx, a.PreviewURL)
+			}
+			if src == nil && a.Blurhash != "" {
+				src, _ = DecodeBlurhash(a.Blurhash, cell.Dx(), cell.Dy())
+			}
+			if src == nil {
+				src = image.NewUniform(theme.Muted)
+			}
+			drawCover(img, cell, src)
+		}
+		y += gridHeight
+	}
+
+	// Footer.
+	y += gap
+	footer := fmt.Sprintf("%s  %d replies  %d boosts  %d favourites",
+		s.CreatedAt.UTC().Format("2006-01-02 15:04"), s.RepliesCount, s.ReblogsCount, s.FavouritesCount)
+	font.Draw(img, image.Pt(pad, y), footer, theme.Muted)
+	return img, nil
+}
+
+// fetchImage returns nil when there is no client or the image cannot be
+// fetched, so a placeholder is drawn instead.
+func (r *StatusRenderer) fetchImage(ctx context.Context, u string) image.Image {
+	if r.Client == nil || r.Client.LowBandwidth || u == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := r.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// drawCover scales src to cover dst, cropping what overflows.
+func drawCover(dst draw.Image, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if sb.Empty() {
+		// Uniform images have infinite bounds.
+		draw.Draw(dst, rect, src, image.Point{}, draw.Src)
+		return
+	}
+	scale := float64(sb.Dx()) / float64(rect.Dx())
+	if s := float64(sb.Dy()) / float64(rect.Dy()); s < scale {
+		scale = s
+	}
+	offX := (float64(sb.Dx()) - float64(rect.Dx())*scale) / 2
+	offY := (float64(sb.Dy()) - float64(rect.Dy())*scale) / 2
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			sx := sb.Min.X + int(offX+float64(x)*scale)
+			sy := sb.Min.Y + int(offY+float64(y)*scale)
+			dst.Set(rect.Min.X+x, rect.Min.Y+y, src.At(sx, sy))
+		}
+	}
+}
+
+var (
+	htmlBreakRegexp     = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlParagraphRegexp = regexp.MustCompile(`(?i)</p>`)
+	htmlTagRegexp       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// statusText converts the HTML content of a status to plain text.
+func statusText(content string) string {
+	content = htmlBreakRegexp.ReplaceAllString(content, "\n")
+	content = htmlParagraphRegexp.ReplaceAllString(content, "\n\n")
+	content = htmlTagRegexp.ReplaceAllString(content, "")
+	return strings.TrimSpace(html.UnescapeString(content))
+}
+
+// wrapText breaks text into lines no wider than width.
+func wrapText(font Font, text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for font.Measure(word) > width {
+				// Break words longer than a line.
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				n := 1
+				for n < utf8.RuneCountInString(word) && font.Measure(string([]rune(word)[:n+1])) <= width {
+					n++
+				}
+				lines = append(lines, string([]rune(word)[:n]))
+				word = string([]rune(word)[n:])
+			}
+			if line == "" {
+				line = word
+			} else if font.Measure(line+" "+word) <= width {
+				line += " " + word
+			} else {
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func firstRune(s string) string {
+	r, _ := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return ""
+	}
+	return string(r)
+}
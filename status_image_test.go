@@ -0,0 +1,100 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusRendererRender(t *testing.T) {
+	red := image.NewRGBA(image.Rect(0, 0, 16, 9))
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 16; x++ {
+			red.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/preview.png" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		png.Encode(w, red)
+	}))
+	defer ts.Close()
+
+	s := &Status{
+		Account:          Account{Username: "foo", Acct: "foo@example.com", DisplayName: "Foo"},
+		Content:          "<p>Hello &amp; welcome<br>to the fediverse</p><p>bye</p>",
+		MediaAttachments: []Attachment{{PreviewURL: ts.URL + "/preview.png", Blurhash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj"}},
+	}
+	r := &StatusRenderer{Width: 320}
+	img, err := r.Render(context.Background(), s)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if img.Bounds().Dx() != 320 {
+		t.Fatalf("want %d but %d", 320, img.Bounds().Dx())
+	}
+	if requests != 0 {
+		t.Fatalf("should not fetch without client: %d", requests)
+	}
+
+	r.Client = NewClient(&Config{Server: ts.URL})
+	img, err = r.Render(context.Background(), &Status{Reblog: s})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("want 1 request but %d", requests)
+	}
+	// The middle of the media grid is above the footer.
+	b := img.Bounds()
+	lh := (&BitmapFont{Scale: 2}).LineHeight()
+	pr, pg, pb, _ := img.At(b.Dx()/2, b.Dy()-lh-lh/2-lh-20).RGBA()
+	if pr != 0xffff || pg != 0 || pb != 0 {
+		t.Fatalf("want red media but %x %x %x", pr, pg, pb)
+	}
+
+	var buf bytes.Buffer
+	if err := r.RenderPNG(context.Background(), &buf, s); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	r.Width = 10
+	if _, err := r.Render(context.Background(), s); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	got := statusText(`<p>Hello &amp; <a href="https://example.com">welcome</a><br/>again</p><p>bye</p>`)
+	want := "Hello & welcome\nagain\n\nbye"
+	if got != want {
+		t.Fatalf("want %q but %q", want, got)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	font := &BitmapFont{}
+	lines := wrapText(font, "aaa bbb ccc\n\n"+strings.Repeat("d", 7), font.Measure("aaa bbb"))
+	want := []string{"aaa bbb", "ccc", "", "ddddddd"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Fatalf("want %q but %q", want, lines)
+	}
+	lines = wrapText(font, strings.Repeat("e", 10), font.Measure("eeee"))
+	want = []string{"eeee", "eeee", "ee"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Fatalf("want %q but %q", want, lines)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -135,6 +136,51 @@ func TestGetStatus(t *testing.T) {
 	}
 }
 
+func TestApplicationFilter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"id": "1", "application": {"name": "Web", "website": null}}, {"id": "2", "application": {"name": "Crossposter", "website": "https://crossposter.example"}}, {"id": "3", "application": null}, {"id": "4", "reblog": {"id": "5", "application": {"name": "crossposter"}}}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:       ts.URL,
+		ClientID:     "foo",
+		ClientSecret: "bar",
+		AccessToken:  "zoo",
+	})
+	statuses, err := client.GetTimelineHome(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if statuses[1].Application.Name != "Crossposter" {
+		t.Fatalf("want %q but %q", "Crossposter", statuses[1].Application.Name)
+	}
+	if statuses[1].Application.Website != "https://crossposter.example" {
+		t.Fatalf("want %q but %q", "https://crossposter.example", statuses[1].Application.Website)
+	}
+
+	tests := []struct {
+		filter ApplicationFilter
+		want   []ID
+	}{
+		{ApplicationFilter{}, []ID{"1", "2", "3", "4"}},
+		{ApplicationFilter{Include: []string{"Crossposter"}}, []ID{"2", "4"}},
+		{ApplicationFilter{Exclude: []string{"CROSSPOSTER"}}, []ID{"1", "3"}},
+		{ApplicationFilter{Include: []string{"Web", "Crossposter"}, Exclude: []string{"Web"}}, []ID{"2", "4"}},
+	}
+	for _, tt := range tests {
+		got := tt.filter.Filter(statuses)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%+v: want %d statuses but %d", tt.filter, len(tt.want), len(got))
+		}
+		for i := range got {
+			if got[i].ID != tt.want[i] {
+				t.Fatalf("%+v: want %q but %q", tt.filter, tt.want[i], got[i].ID)
+			}
+		}
+	}
+}
+
 func TestGetStatusCard(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/statuses/1234567/card" {
@@ -360,7 +406,7 @@ func TestReblog(t *testing.T) {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
-		fmt.Fprintln(w, `{"content": "zzz"}`)
+		fmt.Fprintf(w, `{"content": "zzz", "visibility": %q}`, r.FormValue("visibility"))
 	}))
 	defer ts.Close()
 
@@ -381,6 +427,17 @@ func TestReblog(t *testing.T) {
 	if status.Content != "zzz" {
 		t.Fatalf("want %q but %q", "zzz", status.Content)
 	}
+	status, err = client.ReblogWithVisibility(context.Background(), "1234567", VisibilityPrivate)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if status.Visibility != VisibilityPrivate {
+		t.Fatalf("want %q but %q", VisibilityPrivate, status.Visibility)
+	}
+	_, err = client.ReblogWithVisibility(context.Background(), "1234567", VisibilityDirect)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
 }
 
 func TestUnreblog(t *testing.T) {
@@ -558,6 +615,31 @@ func TestGetTimelinePublic(t *testing.T) {
 	}
 }
 
+func TestGetTimelinePublicWithOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("local") != "" || q.Get("remote") != "true" || q.Get("only_media") != "true" {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `[{"content": "foo"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	_, err := client.GetTimelinePublicWithOptions(context.Background(), nil, nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	tl, err := client.GetTimelinePublicWithOptions(context.Background(), &PublicTimelineOptions{Remote: true, OnlyMedia: true}, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tl) != 1 || tl[0].Content != "foo" {
+		t.Fatalf("want %q but %v", "foo", tl)
+	}
+}
+
 func TestGetTimelineDirect(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, `[{"id": "4", "unread":false, "last_status" : {"content": "zzz"}}, {"id": "3", "unread":true, "last_status" : {"content": "bar"}}]`)
@@ -662,13 +744,63 @@ func TestGetTimelineHashtagMultiple(t *testing.T) {
 	}
 }
 
+func TestGetTimelineHashtagMultipleTagData(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/tag/zzz" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		if strings.Join(q["any[]"], ",") != "a,b" || q.Get("all[]") != "c" || q.Get("none[]") != "d" || q.Get("only_media") != "true" || q.Get("local") != "t" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `[{"content": "zzz"},{"content": "yyy"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:       ts.URL,
+		ClientID:     "foo",
+		ClientSecret: "bar",
+		AccessToken:  "zoo",
+	})
+	_, err := client.GetTimelineHashtagMultiple(context.Background(), "zzz", true, nil, nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	tags, err := client.GetTimelineHashtagMultiple(context.Background(), "zzz", true, &TagData{
+		Any:       []string{"a", "b"},
+		All:       []string{"c"},
+		None:      []string{"d"},
+		OnlyMedia: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("should have %q entries but %q", "2", len(tags))
+	}
+	if tags[0].Content != "zzz" {
+		t.Fatalf("want %q but %q", "zzz", tags[0].Content)
+	}
+	if tags[1].Content != "yyy" {
+		t.Fatalf("want %q but %q", "yyy", tags[1].Content)
+	}
+}
+
 func TestGetTimelineList(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/timelines/list/1" {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
-		fmt.Fprintln(w, `[{"content": "zzz"},{"content": "yyy"}]`)
+		if r.URL.Query().Get("max_id") == "2" {
+			fmt.Fprintln(w, `[{"id": "1", "content": "xxx"}]`)
+			return
+		}
+		w.Header().Set("Link", `<http://example.com/api/v1/timelines/list/1?max_id=2>; rel="next", <http://example.com/api/v1/timelines/list/1?min_id=3>; rel="prev"`)
+		fmt.Fprintln(w, `[{"id": "3", "content": "zzz"},{"id": "2", "content": "yyy"}]`)
 	}))
 	defer ts.Close()
 
@@ -695,6 +827,48 @@ func TestGetTimelineList(t *testing.T) {
 	if tags[1].Content != "yyy" {
 		t.Fatalf("want %q but %q", "zzz", tags[1].Content)
 	}
+	var pg Pagination
+	if _, err := client.GetTimelineList(context.Background(), "1", &pg); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if pg.MaxID != "2" || pg.MinID != "3" {
+		t.Fatalf("want %q but %q", "2", pg.MaxID)
+	}
+	pg.MinID = ""
+	tags, err = client.GetTimelineList(context.Background(), "1", &pg)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Content != "xxx" {
+		t.Fatalf("want %q but %v", "xxx", tags)
+	}
+}
+
+func TestGetTimelineLink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/link" || r.URL.Query().Get("url") != "https://example.com/a?b=c" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `[{"content": "foo"}, {"content": "bar"}]`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	_, err := client.GetTimelineLink(context.Background(), "https://example.com/", nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	tl, err := client.GetTimelineLink(context.Background(), "https://example.com/a?b=c", nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(tl) != 2 {
+		t.Fatalf("result should be two: %d", len(tl))
+	}
+	if tl[0].Content != "foo" {
+		t.Fatalf("want %q but %q", "foo", tl[0].Content)
+	}
 }
 
 func TestGetTimelineMedia(t *testing.T) {
@@ -863,6 +1037,115 @@ func TestUploadMedia(t *testing.T) {
 
 }
 
+func TestUpdateMediaAttachment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/media/123" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintln(w, `{"id": "123", "description": "old"}`)
+		case http.MethodPut:
+			if r.PostFormValue("description") != "A cat" || r.PostFormValue("focus") != "0.5,-0.2" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			preview := ""
+			if f, _, err := r.FormFile("thumbnail"); err == nil {
+				b, _ := ioutil.ReadAll(f)
+				preview = string(b)
+			}
+			fmt.Fprintf(w, `{"id": "123", "description": "A cat", "preview_url": %q, "meta": {"focus": {"x": 0.5, "y": -0.2}}}`, preview)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	attachment, err := client.GetMedia(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if attachment.Description != "old" {
+		t.Fatalf("want %q but %q", "old", attachment.Description)
+	}
+	_, err = client.UpdateMediaAttachment(context.Background(), "123", nil)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	description := "A cat"
+	_, err = client.UpdateMediaAttachment(context.Background(), "123", &MediaUpdate{Description: &description})
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	attachment, err = client.UpdateMediaAttachment(context.Background(), "123", &MediaUpdate{Description: &description, Focus: "0.5,-0.2"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if attachment.Description != "A cat" {
+		t.Fatalf("want %q but %q", "A cat", attachment.Description)
+	}
+	attachment, err = client.UpdateMediaAttachment(context.Background(), "123", &MediaUpdate{
+		Description: &description,
+		Focus:       "0.5,-0.2",
+		Thumbnail:   strings.NewReader("thumb"),
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if attachment.PreviewURL != "thumb" {
+		t.Fatalf("want %q but %q", "thumb", attachment.PreviewURL)
+	}
+}
+
+func TestUpdateStatusMediaAttributes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/statuses/1" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		r.ParseForm()
+		want := map[string]string{
+			"media_attributes[0][id]":          "7",
+			"media_attributes[0][description]": "A cat",
+			"media_attributes[0][focus]":       "0,0",
+			"media_attributes[1][id]":          "8",
+			"media_attributes[1][description]": "Another cat",
+		}
+		for k, v := range want {
+			if got := r.PostForm[k]; len(got) != 1 || got[0] != v {
+				http.Error(w, fmt.Sprintf("%s: want %q but %q", k, v, got), http.StatusBadRequest)
+				return
+			}
+		}
+		if _, ok := r.PostForm["media_attributes[1][focus]"]; ok {
+			http.Error(w, "unexpected focus of the second media", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.UpdateStatus(context.Background(), &Toot{
+		Status:   "cats",
+		MediaIDs: []ID{"7", "8"},
+		MediaAttributes: []TootMediaAttribute{
+			{ID: "7", Description: "A cat", Focus: "0,0"},
+			{ID: "8", Description: "Another cat"},
+		},
+	}, "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
+
 func TestGetConversations(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/conversations" {
@@ -945,3 +1228,82 @@ func TestMarkConversationsAsRead(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func TestTranslateStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/statuses/1234567/translate" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if r.FormValue("lang") != "en" {
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+		fmt.Fprintln(w, `{"content": "<p>Hello</p>", "spoiler_text": "", "media_attachments": [{"id": "1", "description": "A cat"}], "poll": null, "detected_source_language": "ja", "provider": "DeepL.com"}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:       ts.URL,
+		ClientID:     "foo",
+		ClientSecret: "bar",
+		AccessToken:  "zoo",
+	})
+	_, err := client.TranslateStatus(context.Background(), "123", "en")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	tr, err := client.TranslateStatus(context.Background(), "1234567", "en")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if tr.Content != "<p>Hello</p>" {
+		t.Fatalf("want %q but %q", "<p>Hello</p>", tr.Content)
+	}
+	if tr.DetectedSourceLanguage != "ja" {
+		t.Fatalf("want %q but %q", "ja", tr.DetectedSourceLanguage)
+	}
+	if len(tr.MediaAttachments) != 1 || tr.MediaAttachments[0].Description != "A cat" {
+		t.Fatalf("unexpected media attachments: %v", tr.MediaAttachments)
+	}
+	if tr.Provider != "DeepL.com" {
+		t.Fatalf("want %q but %q", "DeepL.com", tr.Provider)
+	}
+}
+
+func TestPin(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/statuses/1234567/pin":
+			fmt.Fprintln(w, `{"id": "1234567", "pinned": true}`)
+		case "/api/v1/statuses/1234567/unpin":
+			fmt.Fprintln(w, `{"id": "1234567", "pinned": false}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{
+		Server:      ts.URL,
+		AccessToken: "zoo",
+	})
+	_, err := client.Pin(context.Background(), "123")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	status, err := client.Pin(context.Background(), "1234567")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if status.Pinned != true {
+		t.Fatalf("want %v but %v", true, status.Pinned)
+	}
+	status, err = client.Unpin(context.Background(), "1234567")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if status.Pinned != false {
+		t.Fatalf("want %v but %v", false, status.Pinned)
+	}
+}
@@ -0,0 +1,102 @@
+package mastodon
+
+import "context"
+
+// Handler holds the callbacks called by Client.Stream for each event of a
+// stream. Events without a callback are ignored.
+type Handler struct {
+	OnStatus         func(s *Status)
+	OnStatusUpdate   func(s *Status)
+	OnNotification   func(n *Notification)
+	OnDelete         func(id ID)
+	OnConversation   func(c *Conversation)
+	OnFiltersChanged func()
+	OnError          func(err error)
+	// OnEvent is called with the events no other callback handles, like
+	// ConnectEvent and AnnouncementEvent.
+	OnEvent func(e Event)
+}
+
+// Handle calls the callback of h for e.
+func (h *Handler) Handle(e Event) {
+	switch e := e.(type) {
+	case *UpdateEvent:
+		if h.OnStatus != nil {
+			h.OnStatus(e.Status)
+			return
+		}
+	case *StatusUpdateEvent:
+		if h.OnStatusUpdate != nil {
+			h.OnStatusUpdate(e.Status)
+			return
+		}
+	case *NotificationEvent:
+		if h.OnNotification != nil {
+			h.OnNotification(e.Notification)
+			return
+		}
+	case *DeleteEvent:
+		if h.OnDelete != nil {
+			h.OnDelete(e.ID)
+			return
+		}
+	case *ConversationEvent:
+		if h.OnConversation != nil {
+			h.OnConversation(e.Conversation)
+			return
+		}
+	case *FiltersChangedEvent:
+		if h.OnFiltersChanged != nil {
+			h.OnFiltersChanged()
+			return
+		}
+	case *ErrorEvent:
+		if h.OnError != nil {
+			h.OnError(e.err)
+			return
+		}
+	}
+	if h.OnEvent != nil {
+		h.OnEvent(e)
+	}
+}
+
+func (s StreamSpec) param() string {
+	if s.List != "" {
+		return string(s.List)
+	}
+	return s.Tag
+}
+
+// Stream reads the stream of spec with server-sent events, calling the
+// callbacks of h for its events, until ctx is done. Once Capabilities
+// detected GoToSocial, which has no server-sent events, it uses WebSocket.
+func (c *Client) Stream(ctx context.Context, spec StreamSpec, h Handler) error {
+	if c.ServerSoftware() == SoftwareGoToSocial {
+		// GoToSocial only streams over WebSocket.
+		return c.NewWSClient().Stream(ctx, spec, h)
+	}
+	p, params := eventStream(spec.Stream, spec.param())
+	q, err := c.streaming(ctx, p, params)
+	if err != nil {
+		return err
+	}
+	return handleStream(ctx, q, h)
+}
+
+// Stream reads the stream of spec using WebSocket, calling the callbacks of
+// h for its events, until ctx is done or the server refuses the stream.
+func (c *WSClient) Stream(ctx context.Context, spec StreamSpec, h Handler) error {
+	q, err := c.streamingWS(ctx, spec.Stream, spec.param())
+	if err != nil {
+		return err
+	}
+	return handleStream(ctx, q, h)
+}
+
+func handleStream(ctx context.Context, q chan Event, h Handler) error {
+	for e := range q {
+		h.Handle(e)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,162 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestClientStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/streaming/hashtag/local" || r.URL.Query().Get("tag") != "golang" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `event: update
+data: {"content": "foo"}
+
+event: status.update
+data: {"content": "bar"}
+
+event: notification
+data: {"id": "1"}
+
+event: delete
+data: 123
+
+event: filters_changed
+data: undefined
+
+event: announcement.delete
+data: 8
+
+event: update
+data: <html>
+
+`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []string
+	err := client.Stream(ctx, StreamSpec{Stream: "hashtag:local", Tag: "golang"}, Handler{
+		OnStatus: func(s *Status) {
+			got = append(got, "status "+s.Content)
+		},
+		OnStatusUpdate: func(s *Status) {
+			got = append(got, "edit "+s.Content)
+		},
+		OnNotification: func(n *Notification) {
+			got = append(got, "notification "+string(n.ID))
+		},
+		OnDelete: func(id ID) {
+			got = append(got, "delete "+string(id))
+		},
+		OnFiltersChanged: func() {
+			got = append(got, "filters")
+		},
+		OnEvent: func(e Event) {
+			if d, ok := e.(*AnnouncementDeleteEvent); ok {
+				got = append(got, "announcement "+string(d.ID))
+			}
+		},
+		OnError: func(err error) {
+			got = append(got, "error")
+			cancel()
+		},
+	})
+	if err != context.Canceled {
+		t.Fatalf("want %v but %v", context.Canceled, err)
+	}
+	want := "status foo,edit bar,notification 1,delete 123,filters,announcement 8,error"
+	if !strings.HasPrefix(strings.Join(got, ","), want) {
+		t.Fatalf("want %q but %q", want, strings.Join(got, ","))
+	}
+}
+
+func TestWSClientStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("stream") != "list" || r.URL.Query().Get("list") != "123" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		u := websocket.Upgrader{}
+		conn, err := u.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"update","payload":"{\"content\":\"foo\"}"}`))
+		time.Sleep(10 * time.Second)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL}).NewWSClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	connected := false
+	var content string
+	err := client.Stream(ctx, StreamSpec{Stream: "list", List: "123"}, Handler{
+		OnStatus: func(s *Status) {
+			content = s.Content
+			cancel()
+		},
+		OnEvent: func(e Event) {
+			if _, ok := e.(*ConnectEvent); ok {
+				connected = true
+			}
+		},
+	})
+	if err != context.Canceled {
+		t.Fatalf("want %v but %v", context.Canceled, err)
+	}
+	if !connected || content != "foo" {
+		t.Fatalf("want %q but %q, connected: %t", "foo", content, connected)
+	}
+}
+
+func TestClientStreamGoToSocial(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) || r.URL.Query().Get("stream") != "user" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		u := websocket.Upgrader{}
+		conn, err := u.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"update","payload":"{\"content\":\"foo\"}"}`))
+		time.Sleep(10 * time.Second)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL})
+	client.software = SoftwareGoToSocial
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var content string
+	err := client.Stream(ctx, StreamSpec{Stream: "user"}, Handler{
+		OnStatus: func(s *Status) {
+			content = s.Content
+			cancel()
+		},
+	})
+	if err != context.Canceled {
+		t.Fatalf("want %v but %v", context.Canceled, err)
+	}
+	if content != "foo" {
+		t.Fatalf("want %q but %q", "foo", content)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // UpdateEvent is a struct for passing status event to app.
@@ -20,12 +22,18 @@ type UpdateEvent struct {
 
 func (e *UpdateEvent) event() {}
 
-// UpdateEditEvent is a struct for passing status edit event to app.
-type UpdateEditEvent struct {
+// StatusUpdateEvent is a struct for passing status edit event to app. The
+// status replaces the one with the same ID, keeping its place in timelines.
+type StatusUpdateEvent struct {
 	Status *Status `json:"status"`
 }
 
-func (e *UpdateEditEvent) event() {}
+func (e *StatusUpdateEvent) event() {}
+
+// UpdateEditEvent is the former name of StatusUpdateEvent.
+//
+// Deprecated: Use StatusUpdateEvent.
+type UpdateEditEvent = StatusUpdateEvent
 
 // NotificationEvent is a struct for passing notification event to app.
 type NotificationEvent struct {
@@ -46,24 +54,82 @@ type ConversationEvent struct {
 
 func (e *ConversationEvent) event() {}
 
+// FiltersChangedEvent is sent when the filters of the user changed, which
+// should be fetched again.
+type FiltersChangedEvent struct{}
+
+func (e *FiltersChangedEvent) event() {}
+
+// AnnouncementEvent is a struct for passing a published or updated
+// announcement to app.
+type AnnouncementEvent struct {
+	Announcement *Announcement `json:"announcement"`
+}
+
+func (e *AnnouncementEvent) event() {}
+
+// AnnouncementReactionEvent is a struct for passing a changed reaction to an
+// announcement to app.
+type AnnouncementReactionEvent struct {
+	Reaction *AnnouncementReaction `json:"reaction"`
+}
+
+func (e *AnnouncementReactionEvent) event() {}
+
+// AnnouncementDeleteEvent is a struct for passing the deletion of an
+// announcement to app.
+type AnnouncementDeleteEvent struct{ ID ID }
+
+func (e *AnnouncementDeleteEvent) event() {}
+
 // ErrorEvent is a struct for passing errors to app.
 type ErrorEvent struct{ err error }
 
 func (e *ErrorEvent) event()        {}
 func (e *ErrorEvent) Error() string { return e.err.Error() }
 
+// ConnectEvent is sent when a WebSocket stream is connected, and again each
+// time it reconnects.
+type ConnectEvent struct{}
+
+func (e *ConnectEvent) event() {}
+
+// DisconnectEvent is sent when the connection of a WebSocket stream is lost.
+// The stream reconnects until its context is done.
+type DisconnectEvent struct{ Err error }
+
+func (e *DisconnectEvent) event() {}
+
 // Event is an interface passing events to app.
 type Event interface {
 	event()
 }
 
+// handleReader sends the server-sent events read from r to q. Data lines
+// are joined until a blank line, or the next event line, dispatches them.
+// Comments, like the heartbeats of Mastodon, are skipped.
 func handleReader(q chan Event, r io.Reader) error {
 	var name string
+	var data []string
+	dispatch := func() {
+		if len(data) == 0 {
+			return
+		}
+		e, err := decodeEvent(name, strings.Join(data, "\n"))
+		data = data[:0]
+		if err != nil {
+			q <- &ErrorEvent{err}
+		} else if e != nil {
+			q <- e
+		}
+	}
+
 	var lineBuf bytes.Buffer
 	br := bufio.NewReader(r)
 	for {
 		line, isPrefix, err := br.ReadLine()
 		if err != nil {
+			dispatch()
 			if errors.Is(err, io.EOF) {
 				return nil
 			}
@@ -79,52 +145,142 @@ func handleReader(q chan Event, r io.Reader) error {
 			lineBuf.Reset()
 		}
 
-		token := strings.SplitN(string(line), ":", 2)
+		s := string(line)
+		if strings.TrimSpace(s) == "" {
+			dispatch()
+			name = ""
+			continue
+		}
+		if strings.HasPrefix(s, ":") {
+			// Comment.
+			continue
+		}
+		token := strings.SplitN(s, ":", 2)
 		if len(token) != 2 {
 			continue
 		}
 		switch strings.TrimSpace(token[0]) {
 		case "event":
+			dispatch()
 			name = strings.TrimSpace(token[1])
 		case "data":
-			var err error
-			switch name {
-			case "update":
-				var status Status
-				err = json.Unmarshal([]byte(token[1]), &status)
-				if err == nil {
-					q <- &UpdateEvent{&status}
-				}
-			case "status.update":
-				var status Status
-				err = json.Unmarshal([]byte(token[1]), &status)
-				if err == nil {
-					q <- &UpdateEditEvent{&status}
-				}
-			case "notification":
-				var notification Notification
-				err = json.Unmarshal([]byte(token[1]), &notification)
-				if err == nil {
-					q <- &NotificationEvent{&notification}
-				}
-			case "conversation":
-				var conversation Conversation
-				err = json.Unmarshal([]byte(token[1]), &conversation)
-				if err == nil {
-					q <- &ConversationEvent{&conversation}
-				}
-			case "delete":
-				q <- &DeleteEvent{ID: ID(strings.TrimSpace(token[1]))}
-			}
-			if err != nil {
-				q <- &ErrorEvent{err}
-			}
+			data = append(data, strings.TrimPrefix(token[1], " "))
+		}
+	}
+}
+
+// decodeEvent returns the event name with the payload data, or nil if the
+// event isn't known.
+func decodeEvent(name, data string) (Event, error) {
+	switch name {
+	case "update":
+		var status Status
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			return nil, err
+		}
+		return &UpdateEvent{Status: &status}, nil
+	case "status.update":
+		var status Status
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			return nil, err
+		}
+		return &StatusUpdateEvent{Status: &status}, nil
+	case "notification":
+		var notification Notification
+		if err := json.Unmarshal([]byte(data), &notification); err != nil {
+			return nil, err
+		}
+		return &NotificationEvent{Notification: &notification}, nil
+	case "conversation":
+		var conversation Conversation
+		if err := json.Unmarshal([]byte(data), &conversation); err != nil {
+			return nil, err
+		}
+		return &ConversationEvent{Conversation: &conversation}, nil
+	case "delete":
+		return &DeleteEvent{ID: ID(strings.TrimSpace(data))}, nil
+	case "filters_changed":
+		return &FiltersChangedEvent{}, nil
+	case "announcement":
+		var announcement Announcement
+		if err := json.Unmarshal([]byte(data), &announcement); err != nil {
+			return nil, err
 		}
+		return &AnnouncementEvent{Announcement: &announcement}, nil
+	case "announcement.reaction":
+		var reaction AnnouncementReaction
+		if err := json.Unmarshal([]byte(data), &reaction); err != nil {
+			return nil, err
+		}
+		return &AnnouncementReactionEvent{Reaction: &reaction}, nil
+	case "announcement.delete":
+		return &AnnouncementDeleteEvent{ID: ID(strings.TrimSpace(data))}, nil
+	}
+	return nil, nil
+}
+
+// StreamingURL returns the base URL of the streaming API: Config.StreamingServer
+// if set, else the URL the instance advertises, else Config.Server. Many
+// instances serve streaming from another host than the API. The URL found
+// from the instance is kept for the next calls.
+func (c *Client) StreamingURL(ctx context.Context) string {
+	if c.Config.StreamingServer != "" {
+		return c.Config.StreamingServer
+	}
+	c.streamingMu.Lock()
+	defer c.streamingMu.Unlock()
+	if c.streamingURL != "" {
+		return c.streamingURL
+	}
+	info, err := c.GetInstanceInfo(ctx)
+	if err != nil {
+		return c.Config.Server
+	}
+	c.streamingURL = c.Config.Server
+	u, err := url.Parse(info.StreamingURL)
+	if info.StreamingURL == "" || err != nil || u.Host == "" {
+		return c.streamingURL
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	c.streamingURL = u.String()
+	return c.streamingURL
+}
+
+// StreamingHealth checks that the streaming service of the instance is up.
+func (c *Client) StreamingHealth(ctx context.Context) error {
+	u, err := url.Parse(c.StreamingURL(ctx))
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "/api/v1/streaming/health")
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
 	}
+	req = req.WithContext(ctx)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseAPIError("bad streaming health", resp)
+	}
+	return nil
 }
 
 func (c *Client) streaming(ctx context.Context, p string, params url.Values) (chan Event, error) {
-	u, err := url.Parse(c.Config.Server)
+	u, err := url.Parse(c.StreamingURL(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -137,20 +293,23 @@ func (c *Client) streaming(ctx context.Context, p string, params url.Values) (ch
 	}
 	req = req.WithContext(ctx)
 
-	if c.Config.AccessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Config.AccessToken)
+	if token := c.accessToken(ctx); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	q := make(chan Event)
 	go func() {
 		defer close(q)
-		for {
+		for connects := 0; ; connects++ {
 			select {
 			case <-ctx.Done():
 				return
 			default:
 			}
 
+			if connects > 0 {
+				c.logDebug(ctx, "mastodon: reconnecting stream", "path", u.Path, "reconnects", connects)
+			}
 			c.doStreaming(req, q)
 		}
 	}()
@@ -170,17 +329,73 @@ func (c *Client) doStreaming(req *http.Request, q chan Event) {
 		return
 	}
 
-	err = handleReader(q, resp.Body)
+	body := io.ReadCloser(resp.Body)
+	if timeout := c.streamIdleTimeout(); timeout > 0 {
+		body = newIdleReader(resp.Body, timeout)
+		defer body.Close()
+	}
+	err = handleReader(q, body)
 	if err != nil {
 		q <- &ErrorEvent{err}
 	}
 }
 
+// ErrStreamIdle is the error of a stream that received nothing for longer
+// than Client.StreamIdleTimeout, and is reconnected.
+var ErrStreamIdle = errors.New("stream idle timeout")
+
+func (c *Client) streamIdleTimeout() time.Duration {
+	if c.StreamIdleTimeout == 0 {
+		return time.Minute
+	}
+	return c.StreamIdleTimeout
+}
+
+// idleReader closes a reader which returned nothing for longer than
+// timeout, making reads fail with ErrStreamIdle.
+type idleReader struct {
+	rc      io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	idle    int32
+}
+
+func newIdleReader(rc io.ReadCloser, timeout time.Duration) *idleReader {
+	r := &idleReader{rc: rc, timeout: timeout}
+	r.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&r.idle, 1)
+		rc.Close()
+	})
+	return r
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	if err != nil && atomic.LoadInt32(&r.idle) == 1 {
+		err = ErrStreamIdle
+	}
+	return n, err
+}
+
+func (r *idleReader) Close() error {
+	r.timer.Stop()
+	return r.rc.Close()
+}
+
 // StreamingUser returns a channel to read events on home.
 func (c *Client) StreamingUser(ctx context.Context) (chan Event, error) {
 	return c.streaming(ctx, "user", nil)
 }
 
+// StreamingUserNotification returns a channel to read the notifications of
+// the user.
+func (c *Client) StreamingUserNotification(ctx context.Context) (chan Event, error) {
+	return c.streaming(ctx, "user/notification", nil)
+}
+
 // StreamingPublic returns a channel to read events on public.
 func (c *Client) StreamingPublic(ctx context.Context, isLocal bool) (chan Event, error) {
 	p := "public"
@@ -191,6 +406,28 @@ func (c *Client) StreamingPublic(ctx context.Context, isLocal bool) (chan Event,
 	return c.streaming(ctx, p, nil)
 }
 
+// StreamingPublicMedia returns a channel to read events on public with
+// media attachments.
+func (c *Client) StreamingPublicMedia(ctx context.Context, isLocal bool) (chan Event, error) {
+	p := "public"
+	if isLocal {
+		p = path.Join(p, "local")
+	}
+
+	return c.streaming(ctx, path.Join(p, "media"), nil)
+}
+
+// StreamingPublicRemote returns a channel to read events on public from
+// other servers, only with media attachments if onlyMedia is set.
+func (c *Client) StreamingPublicRemote(ctx context.Context, onlyMedia bool) (chan Event, error) {
+	p := "public/remote"
+	if onlyMedia {
+		p = path.Join(p, "media")
+	}
+
+	return c.streaming(ctx, p, nil)
+}
+
 // StreamingHashtag returns a channel to read events on tagged timeline.
 func (c *Client) StreamingHashtag(ctx context.Context, tag string, isLocal bool) (chan Event, error) {
 	params := url.Values{}
@@ -0,0 +1,271 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamSpec names a stream, for Client.Stream or a MultiplexedStream.
+type StreamSpec struct {
+	// Stream is the name of the stream, like "user", "public:local",
+	// "hashtag" or "list".
+	Stream string
+	// Tag is the hashtag of hashtag streams.
+	Tag string
+	// List is the list of list streams.
+	List ID
+}
+
+func (s StreamSpec) key() string {
+	return s.Stream + "\x00" + strings.ToLower(s.Tag) + string(s.List)
+}
+
+func (s StreamSpec) frame(typ string) map[string]string {
+	f := map[string]string{"type": typ, "stream": s.Stream}
+	if s.Tag != "" {
+		f["tag"] = s.Tag
+	}
+	if s.List != "" {
+		f["list"] = string(s.List)
+	}
+	return f
+}
+
+// streamKey returns the key of the subscription to the stream names of an
+// event.
+func streamKey(names []string) string {
+	key := names[0] + "\x00"
+	if len(names) > 1 {
+		key += strings.ToLower(names[1])
+	}
+	return key
+}
+
+// MultiplexedStream reads many streams over a single WebSocket connection,
+// routing the events of each stream to the channel of its subscription.
+// Every channel must be read, since a channel left unread holds up the
+// others.
+type MultiplexedStream struct {
+	client *WSClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+	subs map[string]*streamSubscriber
+}
+
+type streamSubscriber struct {
+	sub  StreamSpec
+	q    chan Event
+	done chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *streamSubscriber) send(ctx context.Context, e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.q <- e:
+	case <-s.done:
+	case <-ctx.Done():
+	}
+}
+
+func (s *streamSubscriber) close() {
+	close(s.done)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.q)
+}
+
+// Multiplex opens a stream to which many streams can be subscribed. It
+// reconnects like the other WebSocket streams, subscribing again to the
+// streams, until ctx is done or Close is called.
+func (c *WSClient) Multiplex(ctx context.Context) (*MultiplexedStream, error) {
+	if !webSocketSupported {
+		return nil, errors.New("multiplexed streams need WebSocket support")
+	}
+	u, err := c.streamingWSURL(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := &MultiplexedStream{
+		client: c,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		subs:   map[string]*streamSubscriber{},
+	}
+	go func() {
+		defer m.closeAll()
+		m.client.reconnect(ctx, "multiplex", func() error {
+			return m.handle(ctx, u.String())
+		})
+	}()
+	return m, nil
+}
+
+// Subscribe subscribes to the stream of sub and returns the channel of its
+// events, which also gets the ConnectEvent, DisconnectEvent and ErrorEvent
+// of the connection. The channel is closed by Unsubscribe or when the
+// stream ends.
+func (m *MultiplexedStream) Subscribe(sub StreamSpec) (chan Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subs == nil {
+		return nil, errors.New("stream is closed")
+	}
+	key := sub.key()
+	if _, ok := m.subs[key]; ok {
+		return nil, errors.New("already subscribed to " + sub.Stream)
+	}
+	if m.conn != nil {
+		if err := m.conn.WriteJSON(sub.frame("subscribe")); err != nil {
+			return nil, err
+		}
+	}
+	s := &streamSubscriber{sub: sub, q: make(chan Event), done: make(chan struct{})}
+	m.subs[key] = s
+	return s.q, nil
+}
+
+// Unsubscribe unsubscribes from the stream of sub and closes its channel.
+func (m *MultiplexedStream) Unsubscribe(sub StreamSpec) error {
+	m.mu.Lock()
+	key := sub.key()
+	s, ok := m.subs[key]
+	if !ok {
+		m.mu.Unlock()
+		return errors.New("not subscribed to " + sub.Stream)
+	}
+	delete(m.subs, key)
+	var err error
+	if m.conn != nil {
+		err = m.conn.WriteJSON(sub.frame("unsubscribe"))
+	}
+	m.mu.Unlock()
+
+	s.close()
+	return err
+}
+
+// Close closes the connection and the channels of all subscriptions.
+func (m *MultiplexedStream) Close() error {
+	m.cancel()
+	<-m.done
+	return nil
+}
+
+func (m *MultiplexedStream) closeAll() {
+	m.mu.Lock()
+	subs := m.subs
+	m.subs = nil
+	m.mu.Unlock()
+	for _, s := range subs {
+		s.close()
+	}
+	close(m.done)
+}
+
+func (m *MultiplexedStream) subscribers(names []string) []*streamSubscriber {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(names) > 0 {
+		if s, ok := m.subs[streamKey(names)]; ok {
+			return []*streamSubscriber{s}
+		}
+		return nil
+	}
+	subs := make([]*streamSubscriber, 0, len(m.subs))
+	for _, s := range m.subs {
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+// send sends e to the subscription to the stream names, or to all
+// subscriptions if names is empty.
+func (m *MultiplexedStream) send(names []string, e Event) {
+	for _, s := range m.subscribers(names) {
+		s.send(m.ctx, e)
+	}
+}
+
+func (m *MultiplexedStream) handle(ctx context.Context, rawurl string) error {
+	conn, err := m.client.dialRedirect(rawurl)
+	if err != nil {
+		m.send(nil, &ErrorEvent{err: err})
+		return err
+	}
+	defer conn.Close()
+
+	m.mu.Lock()
+	for _, s := range m.subs {
+		if err = conn.WriteJSON(s.sub.frame("subscribe")); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		m.conn = conn
+	}
+	m.mu.Unlock()
+	if err != nil {
+		m.send(nil, &DisconnectEvent{Err: err})
+		return nil
+	}
+	defer func() {
+		m.mu.Lock()
+		m.conn = nil
+		m.mu.Unlock()
+	}()
+	m.send(nil, &ConnectEvent{})
+
+	// Close the WebSocket when the context is canceled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			m.send(nil, &DisconnectEvent{Err: err})
+			return nil
+		}
+
+		var s Stream
+		if err := json.Unmarshal(msg, &s); err != nil {
+			m.send(nil, &ErrorEvent{err: err})
+			continue
+		}
+		e, err := s.decode()
+		if err != nil {
+			m.send(s.Stream, &ErrorEvent{err})
+		} else if e != nil {
+			m.send(s.Stream, e)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestMultiplexedStream(t *testing.T) {
+	var mu sync.Mutex
+	var frames []map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/streaming" || r.URL.Query().Get("stream") != "" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		u := websocket.Upgrader{}
+		conn, err := u.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var f map[string]string
+			if err := conn.ReadJSON(&f); err != nil {
+				return
+			}
+			mu.Lock()
+			frames = append(frames, f)
+			mu.Unlock()
+			if f["type"] != "subscribe" {
+				continue
+			}
+			switch f["stream"] {
+			case "hashtag":
+				conn.WriteMessage(websocket.TextMessage,
+					[]byte(`{"stream":["hashtag","golang"],"event":"update","payload":"{\"content\":\"foo\"}"}`))
+			case "list":
+				conn.WriteMessage(websocket.TextMessage,
+					[]byte(`{"stream":["list","123"],"event":"delete","payload":"456"}`))
+			}
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL}).NewWSClient()
+	m, err := client.Multiplex(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	defer m.Close()
+
+	hashtag := StreamSpec{Stream: "hashtag", Tag: "GoLang"}
+	tags, err := m.Subscribe(hashtag)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	_, err = m.Subscribe(hashtag)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	e := nextStreamEvent(t, tags)
+	if u, ok := e.(*UpdateEvent); !ok || u.Status.Content != "foo" {
+		t.Fatalf("want update %q but %#v", "foo", e)
+	}
+
+	list, err := m.Subscribe(StreamSpec{Stream: "list", List: "123"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	e = nextStreamEvent(t, list)
+	if d, ok := e.(*DeleteEvent); !ok || d.ID != "456" {
+		t.Fatalf("want delete %q but %#v", "456", e)
+	}
+
+	err = m.Unsubscribe(hashtag)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if _, ok := <-tags; ok {
+		t.Fatalf("channel should be closed")
+	}
+	err = m.Unsubscribe(hashtag)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		mu.Lock()
+		n := len(frames)
+		mu.Unlock()
+		if n >= 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.Close()
+	for range list {
+	}
+	_, err = m.Subscribe(hashtag)
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(frames) != 3 {
+		t.Fatalf("want %d frames but %v", 3, frames)
+	}
+	if frames[0]["type"] != "subscribe" || frames[0]["tag"] != "GoLang" {
+		t.Fatalf("want subscribe to %q but %v", "GoLang", frames[0])
+	}
+	if frames[1]["stream"] != "list" || frames[1]["list"] != "123" {
+		t.Fatalf("want subscribe to list %q but %v", "123", frames[1])
+	}
+	if frames[2]["type"] != "unsubscribe" || frames[2]["stream"] != "hashtag" {
+		t.Fatalf("want unsubscribe from hashtag but %v", frames[2])
+	}
+}
+
+// nextStreamEvent returns the next event of q that isn't about the
+// connection.
+func nextStreamEvent(t *testing.T, q chan Event) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-q:
+			if _, ok := e.(*ConnectEvent); !ok {
+				return e
+			}
+		case <-timeout:
+			t.Fatalf("should receive an event")
+		}
+	}
+}
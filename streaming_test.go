@@ -44,7 +44,7 @@ data: {"id":"819516","unread":true,"accounts":[{"id":"108892712797543112","usern
 			t.Errorf("should not be fail: %v", err)
 		}
 	}()
-	var passUpdate, passUpdateLarge, passNotification, passDelete, passError bool
+	var passUpdate, passUpdateLarge, passStatusUpdate, passNotification, passDelete, passError bool
 	for e := range q {
 		switch event := e.(type) {
 		case *UpdateEvent:
@@ -55,13 +55,10 @@ data: {"id":"819516","unread":true,"accounts":[{"id":"108892712797543112","usern
 			} else {
 				t.Fatalf("bad update content: %q", event.Status.Content)
 			}
-		case *UpdateEditEvent:
-			if event.Status.Content == "foo" {
-				passUpdate = true
-			} else if event.Status.Content == largeContent {
-				passUpdateLarge = true
-			} else {
-				t.Fatalf("bad update content: %q", event.Status.Content)
+		case *StatusUpdateEvent:
+			passStatusUpdate = true
+			if event.Status.Content != "foo" {
+				t.Fatalf("want %q but %q", "foo", event.Status.Content)
 			}
 		case *ConversationEvent:
 			passNotification = true
@@ -85,10 +82,10 @@ data: {"id":"819516","unread":true,"accounts":[{"id":"108892712797543112","usern
 			}
 		}
 	}
-	if !passUpdate || !passUpdateLarge || !passNotification || !passDelete || !passError {
+	if !passUpdate || !passUpdateLarge || !passStatusUpdate || !passNotification || !passDelete || !passError {
 		t.Fatalf("have not passed through somewhere: "+
-			"update: %t, update (large): %t, notification: %t, delete: %t, error: %t",
-			passUpdate, passUpdateLarge, passNotification, passDelete, passError)
+			"update: %t, update (large): %t, status.update: %t, notification: %t, delete: %t, error: %t",
+			passUpdate, passUpdateLarge, passStatusUpdate, passNotification, passDelete, passError)
 	}
 	wg.Wait()
 }
@@ -97,6 +94,10 @@ func TestStreaming(t *testing.T) {
 	var isEnd bool
 	canErr := true
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/streaming" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
 		if isEnd {
 			return
 		} else if canErr {
@@ -142,7 +143,7 @@ data: {"content": "foo"}
 			if event.Status.Content != "foo" {
 				t.Fatalf("want %q but %q", "foo", event.Status.Content)
 			}
-		case *UpdateEditEvent:
+		case *StatusUpdateEvent:
 			cnt++
 			passUpdate = true
 			if event.Status.Content != "foo" {
@@ -414,3 +415,245 @@ data: {"content": "foo"}
 		t.Fatalf("want %q but %q", "foo", events[0].(*UpdateEvent).Status.Content)
 	}
 }
+
+func TestStreamingURL(t *testing.T) {
+	var streaming string
+	var instanceCalls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/instance":
+			instanceCalls++
+			if streaming == "" {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, `{"configuration": {"urls": {"streaming": %q}}}`, streaming)
+		case "/api/v1/instance":
+			fmt.Fprintln(w, `{"urls": {"streaming_api": "wss://v1.example.com"}}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(&Config{Server: ts.URL})
+	if got := c.StreamingURL(context.Background()); got != "https://v1.example.com" {
+		t.Fatalf("want %q but %q", "https://v1.example.com", got)
+	}
+	streaming = "wss://streaming.example.com"
+	if got := c.StreamingURL(context.Background()); got != "https://v1.example.com" {
+		t.Fatalf("want %q but %q", "https://v1.example.com", got)
+	}
+	if instanceCalls != 1 {
+		t.Fatalf("the streaming URL should be discovered once: %d", instanceCalls)
+	}
+	c = NewClient(&Config{Server: ts.URL})
+	if got := c.StreamingURL(context.Background()); got != "https://streaming.example.com" {
+		t.Fatalf("want %q but %q", "https://streaming.example.com", got)
+	}
+	c.Config.StreamingServer = "http://other.example.com"
+	if got := c.StreamingURL(context.Background()); got != "http://other.example.com" {
+		t.Fatalf("want %q but %q", "http://other.example.com", got)
+	}
+
+	c = NewClient(&Config{Server: "http://127.0.0.1:0"})
+	if got := c.StreamingURL(context.Background()); got != "http://127.0.0.1:0" {
+		t.Fatalf("want %q but %q", "http://127.0.0.1:0", got)
+	}
+}
+
+func TestStreamingPaths(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		fmt.Fprintln(w, `
+event: update
+data: {"content": "foo"}
+		`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL})
+	tests := []struct {
+		path   string
+		stream func(ctx context.Context) (chan Event, error)
+	}{
+		{"/api/v1/streaming/user/notification", client.StreamingUserNotification},
+		{"/api/v1/streaming/public/media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingPublicMedia(ctx, false)
+		}},
+		{"/api/v1/streaming/public/local/media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingPublicMedia(ctx, true)
+		}},
+		{"/api/v1/streaming/public/remote", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingPublicRemote(ctx, false)
+		}},
+		{"/api/v1/streaming/public/remote/media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingPublicRemote(ctx, true)
+		}},
+	}
+	for _, tt := range tests {
+		mu.Lock()
+		paths = nil
+		mu.Unlock()
+		ctx, cancel := context.WithCancel(context.Background())
+		q, err := tt.stream(ctx)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if e, ok := (<-q).(*UpdateEvent); !ok || e.Status.Content != "foo" {
+			t.Fatalf("want update %q but %#v", "foo", e)
+		}
+		cancel()
+		for range q {
+		}
+		mu.Lock()
+		found := false
+		for _, p := range paths {
+			found = found || p == tt.path
+		}
+		if !found {
+			t.Fatalf("want %q but %q", tt.path, paths)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestStreamingHealth(t *testing.T) {
+	healthy := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/streaming/health" || !healthy {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "OK")
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: "https://example.com", StreamingServer: ts.URL})
+	client.RetryPolicy = &RetryPolicy{}
+	err := client.StreamingHealth(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	healthy = false
+	err = client.StreamingHealth(context.Background())
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+}
+
+func TestHandleReaderAnnouncements(t *testing.T) {
+	q := make(chan Event)
+	r := strings.NewReader(`
+event: filters_changed
+data: undefined
+event: announcement
+data: {"id": "8", "content": "<p>Maintenance tonight</p>"}
+event: announcement.reaction
+data: {"name": "bongoCat", "count": 2, "announcement_id": "8"}
+event: announcement.delete
+data: 8
+event: unknown
+data: {}
+`)
+	go func() {
+		defer close(q)
+		if err := handleReader(q, r); err != nil {
+			t.Errorf("should not be fail: %v", err)
+		}
+	}()
+	events := []Event{}
+	for e := range q {
+		events = append(events, e)
+	}
+	if len(events) != 4 {
+		t.Fatalf("result should be four: %d", len(events))
+	}
+	if _, ok := events[0].(*FiltersChangedEvent); !ok {
+		t.Fatalf("want filters_changed but %#v", events[0])
+	}
+	if e, ok := events[1].(*AnnouncementEvent); !ok || e.Announcement.ID != "8" {
+		t.Fatalf("want announcement %q but %#v", "8", events[1])
+	}
+	if e, ok := events[2].(*AnnouncementReactionEvent); !ok || e.Reaction.AnnouncementID != "8" || e.Reaction.Count != 2 {
+		t.Fatalf("want reaction to %q but %#v", "8", events[2])
+	}
+	if e, ok := events[3].(*AnnouncementDeleteEvent); !ok || e.ID != "8" {
+		t.Fatalf("want deletion of %q but %#v", "8", events[3])
+	}
+}
+
+func TestHandleReaderMultiline(t *testing.T) {
+	q := make(chan Event)
+	r := strings.NewReader(":thump\n\nevent: update\ndata: {\"content\":\ndata: \"foo\"}\n:thump\n\nevent: delete\ndata: 123")
+	go func() {
+		defer close(q)
+		if err := handleReader(q, r); err != nil {
+			t.Errorf("should not be fail: %v", err)
+		}
+	}()
+	events := []Event{}
+	for e := range q {
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("result should be two: %d", len(events))
+	}
+	if e, ok := events[0].(*UpdateEvent); !ok || e.Status.Content != "foo" {
+		t.Fatalf("want update %q but %#v", "foo", events[0])
+	}
+	if e, ok := events[1].(*DeleteEvent); !ok || e.ID != "123" {
+		t.Fatalf("want deletion of %q but %#v", "123", events[1])
+	}
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	var mu sync.Mutex
+	connects := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connects++
+		n := connects
+		mu.Unlock()
+		fmt.Fprintf(w, "event: update\ndata: {\"content\": \"%d\"}\n\n", n)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL})
+	client.StreamIdleTimeout = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q, err := client.StreamingUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	var got []string
+	for e := range q {
+		switch e := e.(type) {
+		case *UpdateEvent:
+			got = append(got, e.Status.Content)
+			if e.Status.Content == "2" {
+				cancel()
+			}
+		case *ErrorEvent:
+			if len(got) == 1 {
+				if e.err != ErrStreamIdle {
+					t.Fatalf("want %v but %v", ErrStreamIdle, e.err)
+				}
+				got = append(got, "idle")
+			}
+		}
+	}
+	if want := "1,idle,2"; strings.Join(got, ",") != want {
+		t.Fatalf("want %q but %q", want, strings.Join(got, ","))
+	}
+}
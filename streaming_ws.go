@@ -3,18 +3,30 @@ package mastodon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-// WSClient is a WebSocket client.
+// WSClient is a WebSocket client. When built for js/wasm, it reads streams
+// with server-sent events, which browsers support through the fetch API.
 type WSClient struct {
 	websocket.Dialer
 	client *Client
+
+	// ReconnectBackoff is the delay before reconnecting a stream whose
+	// connection failed. It doubles, with jitter, after each failed attempt
+	// up to MaxReconnectBackoff, and is reset once connected. Zero means 1s
+	// and 5m.
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
 }
 
 // NewWSClient return WebSocket client.
@@ -22,6 +34,9 @@ func (c *Client) NewWSClient() *WSClient { return &WSClient{client: c} }
 
 // Stream is a struct of data that flows in streaming.
 type Stream struct {
+	// Stream names the stream of the event on multiplexed connections, like
+	// ["hashtag", "golang"].
+	Stream  []string    `json:"stream,omitempty"`
 	Event   string      `json:"event"`
 	Payload interface{} `json:"payload"`
 }
@@ -31,6 +46,12 @@ func (c *WSClient) StreamingWSUser(ctx context.Context) (chan Event, error) {
 	return c.streamingWS(ctx, "user", "")
 }
 
+// StreamingWSUserNotification return channel to read the notifications of
+// the user using WebSocket.
+func (c *WSClient) StreamingWSUserNotification(ctx context.Context) (chan Event, error) {
+	return c.streamingWS(ctx, "user:notification", "")
+}
+
 // StreamingWSPublic return channel to read events on public using WebSocket.
 func (c *WSClient) StreamingWSPublic(ctx context.Context, isLocal bool) (chan Event, error) {
 	s := "public"
@@ -41,6 +62,29 @@ func (c *WSClient) StreamingWSPublic(ctx context.Context, isLocal bool) (chan Ev
 	return c.streamingWS(ctx, s, "")
 }
 
+// StreamingWSPublicMedia return channel to read events on public with media
+// attachments using WebSocket.
+func (c *WSClient) StreamingWSPublicMedia(ctx context.Context, isLocal bool) (chan Event, error) {
+	s := "public"
+	if isLocal {
+		s += ":local"
+	}
+
+	return c.streamingWS(ctx, s+":media", "")
+}
+
+// StreamingWSPublicRemote return channel to read events on public from
+// other servers using WebSocket, only with media attachments if onlyMedia
+// is set.
+func (c *WSClient) StreamingWSPublicRemote(ctx context.Context, onlyMedia bool) (chan Event, error) {
+	s := "public:remote"
+	if onlyMedia {
+		s += ":media"
+	}
+
+	return c.streamingWS(ctx, s, "")
+}
+
 // StreamingWSHashtag return channel to read events on tagged timeline using WebSocket.
 func (c *WSClient) StreamingWSHashtag(ctx context.Context, tag string, isLocal bool) (chan Event, error) {
 	s := "hashtag"
@@ -62,107 +106,187 @@ func (c *WSClient) StreamingWSDirect(ctx context.Context) (chan Event, error) {
 }
 
 func (c *WSClient) streamingWS(ctx context.Context, stream, tag string) (chan Event, error) {
-	params := url.Values{}
-	params.Set("access_token", c.client.Config.AccessToken)
-	params.Set("stream", stream)
-	if tag != "" {
-		params.Set("tag", tag)
+	if !webSocketSupported {
+		p, params := eventStream(stream, tag)
+		return c.client.streaming(ctx, p, params)
 	}
 
-	u, err := changeWebSocketScheme(c.client.Config.Server)
+	_, params := eventStream(stream, tag)
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("stream", stream)
+	u, err := c.streamingWSURL(ctx, params)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "/api/v1/streaming")
-	u.RawQuery = params.Encode()
 
 	q := make(chan Event)
 	go func() {
 		defer close(q)
-		for {
-			err := c.handleWS(ctx, u.String(), q)
-			if err != nil {
-				return
-			}
+		err := c.reconnect(ctx, stream, func() error {
+			return c.handleWS(ctx, u.String(), q)
+		})
+		if err != nil {
+			q <- &ErrorEvent{err: err}
 		}
 	}()
 
 	return q, nil
 }
 
+// reconnect calls connect until ctx is done or the server refuses the
+// connection for good, waiting with a backoff after failures. connect
+// returns nil when the connection was lost after connecting. The error of
+// ctx is returned if it was done while waiting.
+func (c *WSClient) reconnect(ctx context.Context, stream string, connect func() error) error {
+	backoff := c.reconnectBackoff()
+	for connects := 0; ; connects++ {
+		if connects > 0 {
+			c.client.logDebug(ctx, "mastodon: reconnecting stream", "stream", stream, "reconnects", connects)
+		}
+		err := connect()
+		if ctx.Err() != nil || permanentWSError(err) {
+			return nil
+		}
+		if err == nil {
+			// The connection was lost after connecting.
+			backoff = c.reconnectBackoff()
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if max := c.maxReconnectBackoff(); backoff > max {
+			backoff = max
+		}
+	}
+}
+
+// streamingWSURL returns the WebSocket URL of the streaming API with params
+// and the access token.
+func (c *WSClient) streamingWSURL(ctx context.Context, params url.Values) (*url.URL, error) {
+	u, err := changeWebSocketScheme(c.client.StreamingURL(ctx))
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/api/v1/streaming")
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("access_token", c.client.accessToken(ctx))
+	u.RawQuery = params.Encode()
+	return u, nil
+}
+
+func (c *WSClient) reconnectBackoff() time.Duration {
+	if c.ReconnectBackoff <= 0 {
+		return time.Second
+	}
+	return c.ReconnectBackoff
+}
+
+func (c *WSClient) maxReconnectBackoff() time.Duration {
+	if c.MaxReconnectBackoff <= 0 {
+		return 5 * time.Minute
+	}
+	return c.MaxReconnectBackoff
+}
+
+// permanentWSError reports whether the server refused the connection for a
+// reason reconnecting won't fix, like an invalid token.
+func permanentWSError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// eventStream returns the path and parameters of the server-sent events
+// stream of a WebSocket stream.
+func eventStream(stream, tag string) (string, url.Values) {
+	var params url.Values
+	if tag != "" {
+		params = url.Values{}
+		if stream == "list" {
+			params.Set("list", tag)
+		} else {
+			params.Set("tag", tag)
+		}
+	}
+	return strings.Replace(stream, ":", "/", -1), params
+}
+
+// handleWS reads the stream at rawurl until the connection is lost, which
+// returns nil, or ctx is done.
 func (c *WSClient) handleWS(ctx context.Context, rawurl string, q chan Event) error {
 	conn, err := c.dialRedirect(rawurl)
 	if err != nil {
 		q <- &ErrorEvent{err: err}
+		return err
+	}
+	if ctx.Err() != nil {
+		conn.Close()
+		q <- &ErrorEvent{err: ctx.Err()}
 
 		// End.
-		return err
+		return ctx.Err()
 	}
+	q <- &ConnectEvent{}
 
 	// Close the WebSocket when the context is canceled.
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
 		conn.Close()
 	}()
 
 	for {
-		select {
-		case <-ctx.Done():
+		_, msg, err := conn.ReadMessage()
+		if ctx.Err() != nil {
 			q <- &ErrorEvent{err: ctx.Err()}
 
 			// End.
 			return ctx.Err()
-		default:
 		}
-
-		var s Stream
-		err := conn.ReadJSON(&s)
 		if err != nil {
-			q <- &ErrorEvent{err: err}
+			q <- &DisconnectEvent{Err: err}
 
 			// Reconnect.
-			break
+			return nil
 		}
 
-		err = nil
-		switch s.Event {
-		case "update":
-			var status Status
-			err = json.Unmarshal([]byte(s.Payload.(string)), &status)
-			if err == nil {
-				q <- &UpdateEvent{Status: &status}
-			}
-		case "status.update":
-			var status Status
-			err = json.Unmarshal([]byte(s.Payload.(string)), &status)
-			if err == nil {
-				q <- &UpdateEditEvent{Status: &status}
-			}
-		case "notification":
-			var notification Notification
-			err = json.Unmarshal([]byte(s.Payload.(string)), &notification)
-			if err == nil {
-				q <- &NotificationEvent{Notification: &notification}
-			}
-		case "conversation":
-			var conversation Conversation
-			err = json.Unmarshal([]byte(s.Payload.(string)), &conversation)
-			if err == nil {
-				q <- &ConversationEvent{Conversation: &conversation}
-			}
-		case "delete":
-			if f, ok := s.Payload.(float64); ok {
-				q <- &DeleteEvent{ID: ID(fmt.Sprint(int64(f)))}
-			} else {
-				q <- &DeleteEvent{ID: ID(strings.TrimSpace(s.Payload.(string)))}
-			}
+		var s Stream
+		if err := json.Unmarshal(msg, &s); err != nil {
+			q <- &ErrorEvent{err: err}
+			continue
 		}
+		e, err := s.decode()
 		if err != nil {
 			q <- &ErrorEvent{err}
+		} else if e != nil {
+			q <- e
 		}
 	}
+}
 
-	return nil
+// decode returns the event of s, or nil if the event isn't known.
+func (s *Stream) decode() (Event, error) {
+	if f, ok := s.Payload.(float64); ok {
+		// Old servers sent numeric IDs.
+		return decodeEvent(s.Event, fmt.Sprint(int64(f)))
+	}
+	payload, _ := s.Payload.(string)
+	return decodeEvent(s.Event, payload)
 }
 
 func (c *WSClient) dialRedirect(rawurl string) (conn *websocket.Conn, err error) {
@@ -191,6 +315,9 @@ func (c *WSClient) dial(rawurl string) (*websocket.Conn, string, error) {
 
 		return nil, u.String(), nil
 	}
+	if err == websocket.ErrBadHandshake {
+		return nil, "", parseAPIError("bad handshake", resp)
+	}
 
 	return conn, "", err
 }
@@ -0,0 +1,8 @@
+//go:build js
+// +build js
+
+package mastodon
+
+// Browsers don't allow raw TCP connections, so WebSocket streams are read
+// as server-sent events with the fetch API instead.
+const webSocketSupported = false
@@ -2,8 +2,11 @@ package mastodon
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -155,29 +158,29 @@ func wsTest(t *testing.T, q chan Event, cancel func()) {
 	if len(events) != 8 {
 		t.Fatalf("result should be 8: %d", len(events))
 	}
-	if events[0].(*UpdateEvent).Status.Content != "foo" {
-		t.Fatalf("want %q but %q", "foo", events[0].(*UpdateEvent).Status.Content)
+	if _, ok := events[0].(*ConnectEvent); !ok {
+		t.Fatalf("should be connected: %#v", events[0])
 	}
-	if events[1].(*UpdateEditEvent).Status.Content != "bar" {
-		t.Fatalf("want %q but %q", "bar", events[1].(*UpdateEditEvent).Status.Content)
+	if events[1].(*UpdateEvent).Status.Content != "foo" {
+		t.Fatalf("want %q but %q", "foo", events[1].(*UpdateEvent).Status.Content)
 	}
-	if events[2].(*NotificationEvent).Notification.ID != "123" {
-		t.Fatalf("want %q but %q", "123", events[2].(*NotificationEvent).Notification.ID)
+	if events[2].(*StatusUpdateEvent).Status.Content != "bar" {
+		t.Fatalf("want %q but %q", "bar", events[2].(*StatusUpdateEvent).Status.Content)
 	}
-	if events[3].(*DeleteEvent).ID != "1234567" {
-		t.Fatalf("want %q but %q", "1234567", events[3].(*DeleteEvent).ID)
+	if events[3].(*NotificationEvent).Notification.ID != "123" {
+		t.Fatalf("want %q but %q", "123", events[3].(*NotificationEvent).Notification.ID)
 	}
-	if events[4].(*ConversationEvent).Conversation.ID != "819516" {
-		t.Fatalf("want %q but %q", "819516", events[4].(*ConversationEvent).Conversation.ID)
+	if events[4].(*DeleteEvent).ID != "1234567" {
+		t.Fatalf("want %q but %q", "1234567", events[4].(*DeleteEvent).ID)
 	}
-	if errorEvent, ok := events[5].(*ErrorEvent); !ok {
-		t.Fatalf("should be fail: %v", errorEvent.err)
+	if events[5].(*ConversationEvent).Conversation.ID != "819516" {
+		t.Fatalf("want %q but %q", "819516", events[5].(*ConversationEvent).Conversation.ID)
 	}
 	if errorEvent, ok := events[6].(*ErrorEvent); !ok {
 		t.Fatalf("should be fail: %v", errorEvent.err)
 	}
-	if errorEvent, ok := events[7].(*ErrorEvent); !ok {
-		t.Fatalf("should be fail: %v", errorEvent.err)
+	if errorEvent, ok := events[7].(*ErrorEvent); !ok || errorEvent.err != context.Canceled {
+		t.Fatalf("should be canceled: %#v", events[7])
 	}
 }
 
@@ -263,19 +266,110 @@ func TestHandleWS(t *testing.T) {
 		t.Fatalf("should be fail: %v", err)
 	}
 
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		e := <-q
-		if errorEvent, ok := e.(*ErrorEvent); !ok {
-			t.Errorf("should be fail: %v", errorEvent.err)
+		if e, ok := (<-q).(*ConnectEvent); !ok {
+			t.Errorf("should be connected: %#v", e)
+		}
+		if e, ok := (<-q).(*ErrorEvent); !ok {
+			t.Errorf("should be fail: %#v", e)
+		}
+		cancel()
+		if e, ok := (<-q).(*ErrorEvent); !ok || e.err != context.Canceled {
+			t.Errorf("should be canceled: %#v", e)
 		}
 	}()
-	client.handleWS(context.Background(), "ws://"+ts.Listener.Addr().String(), q)
+	err = client.handleWS(ctx, "ws://"+ts.Listener.Addr().String(), q)
+	if err != context.Canceled {
+		t.Fatalf("want %v but %v", context.Canceled, err)
+	}
 
 	wg.Wait()
 }
 
+func TestStreamingWSReconnect(t *testing.T) {
+	var mu sync.Mutex
+	connects := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connects++
+		n := connects
+		mu.Unlock()
+		if n == 2 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		u := websocket.Upgrader{}
+		conn, err := u.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"event":"update","payload":"{\"content\":\"%d\"}"}`, n)))
+		if n == 3 {
+			time.Sleep(10 * time.Second)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL}).NewWSClient()
+	client.ReconnectBackoff = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q, err := client.StreamingWSUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	var types []string
+	for e := range q {
+		switch e := e.(type) {
+		case *ConnectEvent:
+			types = append(types, "connect")
+		case *DisconnectEvent:
+			types = append(types, "disconnect")
+		case *UpdateEvent:
+			types = append(types, "update "+e.Status.Content)
+			if e.Status.Content == "3" {
+				cancel()
+			}
+		case *ErrorEvent:
+			types = append(types, "error")
+		}
+	}
+	want := "connect,update 1,disconnect,error,connect,update 3,error"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("want %q but %q", want, got)
+	}
+}
+
+func TestStreamingWSUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid token"}`, http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL}).NewWSClient()
+	q, err := client.StreamingWSUser(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	events := []Event{}
+	for e := range q {
+		events = append(events, e)
+	}
+	if len(events) != 1 {
+		t.Fatalf("result should be one: %d", len(events))
+	}
+	var apiErr *APIError
+	if e, ok := events[0].(*ErrorEvent); !ok || !errors.As(e.err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want %d but %#v", http.StatusUnauthorized, events[0])
+	}
+}
+
 func TestDialRedirect(t *testing.T) {
 	client := NewClient(&Config{}).NewWSClient()
 	_, err := client.dialRedirect(":")
@@ -339,3 +433,82 @@ func TestChangeWebSocketScheme(t *testing.T) {
 		t.Fatalf("want %q but %q", "wss", u.Scheme)
 	}
 }
+
+func TestEventStream(t *testing.T) {
+	tests := []struct {
+		stream, tag string
+		path, query string
+	}{
+		{"user", "", "user", ""},
+		{"public:local", "", "public/local", ""},
+		{"hashtag:local", "gopher", "hashtag/local", "tag=gopher"},
+		{"list", "123", "list", "list=123"},
+	}
+	for _, tt := range tests {
+		p, params := eventStream(tt.stream, tt.tag)
+		if p != tt.path {
+			t.Fatalf("want %q but %q", tt.path, p)
+		}
+		if q := params.Encode(); q != tt.query {
+			t.Fatalf("want %q but %q", tt.query, q)
+		}
+	}
+}
+
+func TestStreamingWSStreams(t *testing.T) {
+	var mu sync.Mutex
+	var streams []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		streams = append(streams, r.URL.Query().Get("stream"))
+		mu.Unlock()
+		u := websocket.Upgrader{}
+		conn, err := u.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		time.Sleep(10 * time.Second)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, StreamingServer: ts.URL}).NewWSClient()
+	tests := []struct {
+		stream string
+		open   func(ctx context.Context) (chan Event, error)
+	}{
+		{"user:notification", client.StreamingWSUserNotification},
+		{"public:media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingWSPublicMedia(ctx, false)
+		}},
+		{"public:local:media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingWSPublicMedia(ctx, true)
+		}},
+		{"public:remote", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingWSPublicRemote(ctx, false)
+		}},
+		{"public:remote:media", func(ctx context.Context) (chan Event, error) {
+			return client.StreamingWSPublicRemote(ctx, true)
+		}},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+		q, err := tt.open(ctx)
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if e, ok := (<-q).(*ConnectEvent); !ok {
+			t.Fatalf("should be connected: %#v", e)
+		}
+		cancel()
+		for range q {
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, tt := range tests {
+		if streams[i] != tt.stream {
+			t.Fatalf("want %q but %q", tt.stream, streams[i])
+		}
+	}
+}
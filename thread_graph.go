@@ -0,0 +1,165 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ThreadParticipant is an account taking part in a thread.
+type ThreadParticipant struct {
+	ID   ID
+	Acct string
+	// Account is nil for accounts only mentioned in the thread.
+	Account *Account
+	// Statuses is the number of statuses posted in the thread.
+	Statuses int
+	// Mentioned is the number of statuses of others mentioning the account.
+	Mentioned int
+}
+
+// ThreadEdge counts the interactions of an account with another in a
+// thread.
+type ThreadEdge struct {
+	From ID
+	To   ID
+	// Replies is the number of statuses of From replying to To.
+	Replies int
+	// Mentions is the number of statuses of From mentioning To.
+	Mentions int
+}
+
+// ThreadGraph is the graph of the participants of a thread: who replied to
+// and mentioned whom.
+type ThreadGraph struct {
+	Participants map[ID]*ThreadParticipant
+	// Edges are sorted by decreasing replies and mentions.
+	Edges []*ThreadEdge
+}
+
+// GetThreadGraph fetches the thread of the status id and builds its graph.
+// Only the statuses visible to the current user are included.
+func (c *Client) GetThreadGraph(ctx context.Context, id ID) (*ThreadGraph, error) {
+	status, err := c.GetStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := c.GetStatusContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	statuses := append(append(thread.Ancestors, status), thread.Descendants...)
+	return NewThreadGraph(statuses), nil
+}
+
+// NewThreadGraph builds the graph of the statuses of a thread. Replies of
+// an account to itself are not counted.
+func NewThreadGraph(statuses []*Status) *ThreadGraph {
+	g := &ThreadGraph{Participants: map[ID]*ThreadParticipant{}}
+	edges := map[[2]ID]*ThreadEdge{}
+	edge := func(from, to ID) *ThreadEdge {
+		e, ok := edges[[2]ID{from, to}]
+		if !ok {
+			e = &ThreadEdge{From: from, To: to}
+			edges[[2]ID{from, to}] = e
+		}
+		return e
+	}
+	participant := func(id ID, acct string) *ThreadParticipant {
+		p, ok := g.Participants[id]
+		if !ok {
+			p = &ThreadParticipant{ID: id, Acct: acct}
+			g.Participants[id] = p
+		}
+		return p
+	}
+
+	for _, s := range statuses {
+		author := participant(s.Account.ID, s.Account.Acct)
+		account := s.Account
+		author.Account = &account
+		author.Statuses++
+
+		to := interfaceID(s.InReplyToAccountID)
+		if to != "" && to != s.Account.ID {
+			edge(s.Account.ID, to).Replies++
+		}
+		for _, m := range s.Mentions {
+			if m.ID == s.Account.ID {
+				continue
+			}
+			participant(m.ID, m.Acct).Mentioned++
+			edge(s.Account.ID, m.ID).Mentions++
+		}
+	}
+	// Replies to statuses outside of the visible thread.
+	for _, e := range edges {
+		participant(e.To, "")
+	}
+
+	for _, e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Replies != b.Replies {
+			return a.Replies > b.Replies
+		}
+		if a.Mentions != b.Mentions {
+			return a.Mentions > b.Mentions
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return g
+}
+
+// Replies returns the number of replies of from to to.
+func (g *ThreadGraph) Replies(from, to ID) int {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return e.Replies
+		}
+	}
+	return 0
+}
+
+// Posters returns the participants who posted in the thread, the most
+// active first, excluding the accounts of exclude. Use it to mute a thread
+// for its participants except yourself.
+func (g *ThreadGraph) Posters(exclude ...ID) []*ThreadParticipant {
+	skip := map[ID]bool{}
+	for _, id := range exclude {
+		skip[id] = true
+	}
+	var r []*ThreadParticipant
+	for _, p := range g.Participants {
+		if p.Statuses > 0 && !skip[p.ID] {
+			r = append(r, p)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Statuses != r[j].Statuses {
+			return r[i].Statuses > r[j].Statuses
+		}
+		return r[i].ID.Less(r[j].ID)
+	})
+	return r
+}
+
+// interfaceID converts the ID of an interface{} field of Status, which is
+// nil, a string or a number, to an ID.
+func interfaceID(v interface{}) ID {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return ID(v)
+	case float64:
+		return ID(fmt.Sprintf("%.0f", v))
+	default:
+		return ID(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,53 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetThreadGraph(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/statuses/2":
+			fmt.Fprintln(w, `{"id": "2", "account": {"id": "b", "acct": "bob"}, "in_reply_to_id": "1", "in_reply_to_account_id": "a", "mentions": [{"id": "a", "acct": "alice"}]}`)
+		case "/api/v1/statuses/2/context":
+			fmt.Fprintln(w, `{"ancestors": [{"id": "1", "account": {"id": "a", "acct": "alice"}, "in_reply_to_account_id": "z"}], "descendants": [
+				{"id": "3", "account": {"id": "a", "acct": "alice"}, "in_reply_to_id": "2", "in_reply_to_account_id": "b", "mentions": [{"id": "b", "acct": "bob"}, {"id": "c", "acct": "carol@example.com"}]},
+				{"id": "4", "account": {"id": "b", "acct": "bob"}, "in_reply_to_id": "3", "in_reply_to_account_id": "a", "mentions": [{"id": "a", "acct": "alice"}, {"id": "c", "acct": "carol@example.com"}]},
+				{"id": "5", "account": {"id": "b", "acct": "bob"}, "in_reply_to_id": "4", "in_reply_to_account_id": "b"}
+			]}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "zoo"})
+	if _, err := client.GetThreadGraph(context.Background(), "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	g, err := client.GetThreadGraph(context.Background(), "2")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(g.Participants) != 4 {
+		t.Fatalf("want 4 participants but %d", len(g.Participants))
+	}
+	if g.Replies("b", "a") != 2 || g.Replies("a", "b") != 1 || g.Replies("b", "b") != 0 || g.Replies("a", "z") != 1 {
+		t.Fatalf("unexpected edges: %+v", g.Edges)
+	}
+	if e := g.Edges[0]; e.From != "b" || e.To != "a" || e.Mentions != 2 {
+		t.Fatalf("unexpected first edge: %+v", e)
+	}
+	carol := g.Participants["c"]
+	if carol.Account != nil || carol.Acct != "carol@example.com" || carol.Mentioned != 2 || carol.Statuses != 0 {
+		t.Fatalf("unexpected participant: %+v", carol)
+	}
+	posters := g.Posters("a")
+	if len(posters) != 1 || posters[0].ID != "b" || posters[0].Statuses != 3 {
+		t.Fatalf("unexpected posters: %v", posters)
+	}
+}
@@ -0,0 +1,94 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a day, like the last_status_at of accounts. Servers send it as a
+// date, like "2022-10-31", or as a timestamp; null and "" leave it zero.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	t, err := parseTime(data)
+	if err != nil {
+		return err
+	}
+	if !t.IsZero() {
+		y, m, day := t.Date()
+		t = time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+	}
+	d.Time = t
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Format(dateLayout))
+}
+
+// String returns the date in YYYY-MM-DD format, or "" if it is zero.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+// NullTime is a timestamp which may be missing, like the edited_at of
+// statuses. Servers send it as null, "", an RFC 3339 timestamp, a date or
+// Unix seconds, as a number or a string; null and "" leave it zero.
+type NullTime struct {
+	time.Time
+}
+
+func (t *NullTime) UnmarshalJSON(data []byte) error {
+	v, err := parseTime(data)
+	if err != nil {
+		return err
+	}
+	t.Time = v
+	return nil
+}
+
+func (t NullTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+// parseTime parses the forms of timestamps accepted by NullTime.
+func parseTime(data []byte) (time.Time, error) {
+	s := string(data)
+	if s == "null" {
+		return time.Time{}, nil
+	}
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return time.Time{}, err
+		}
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, dateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s", data)
+}
@@ -0,0 +1,89 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	var a Account
+	if err := json.Unmarshal([]byte(`{"id": "1", "last_status_at": "2022-10-31"}`), &a); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if a.LastStatusAt.String() != "2022-10-31" {
+		t.Fatalf("want %q but %q", "2022-10-31", a.LastStatusAt)
+	}
+	// Mastodon before 3.1 sent a timestamp.
+	if err := json.Unmarshal([]byte(`{"last_status_at": "2019-04-01T12:34:56.000Z"}`), &a); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !a.LastStatusAt.Equal(time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("want %v but %v", "2019-04-01", a.LastStatusAt)
+	}
+	for _, s := range []string{`null`, `""`} {
+		var d Date
+		if err := json.Unmarshal([]byte(s), &d); err != nil || !d.IsZero() {
+			t.Fatalf("want zero date but %v: %v", d, err)
+		}
+	}
+	var d Date
+	if err := json.Unmarshal([]byte(`"yesterday"`), &d); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+
+	b, err := json.Marshal(struct{ A, B Date }{A: Date{time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if string(b) != `{"A":"2022-10-31","B":null}` {
+		t.Fatalf("want %q but %q", `{"A":"2022-10-31","B":null}`, b)
+	}
+}
+
+func TestNullTime(t *testing.T) {
+	want := time.Date(2022, 11, 11, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+		{`"2022-11-11T00:00:00Z"`, want},
+		{`"2022-11-11T00:00:00.000Z"`, want},
+		{`"2022-11-11"`, want},
+		{`1668124800`, want},
+		{`"1668124800"`, want},
+	}
+	for _, tt := range tests {
+		var v NullTime
+		if err := json.Unmarshal([]byte(tt.s), &v); err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if !v.Equal(tt.want) {
+			t.Fatalf("want %v but %v for %s", tt.want, v, tt.s)
+		}
+	}
+
+	var s Status
+	if err := json.Unmarshal([]byte(`{"id": "1", "edited_at": null}`), &s); err != nil || !s.EditedAt.IsZero() {
+		t.Fatalf("want zero time but %v: %v", s.EditedAt, err)
+	}
+	b, err := json.Marshal(struct{ A, B NullTime }{A: NullTime{want}})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if string(b) != `{"A":"2022-11-11T00:00:00Z","B":null}` {
+		t.Fatalf("want %q but %q", `{"A":"2022-11-11T00:00:00Z","B":null}`, b)
+	}
+}
+
+func TestUnixtimeNull(t *testing.T) {
+	var v struct{ A, B, C Unixtime }
+	if err := json.Unmarshal([]byte(`{"A": null, "B": "", "C": "1516579200"}`), &v); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !time.Time(v.A).IsZero() || !time.Time(v.B).IsZero() || !time.Time(v.C).Equal(time.Unix(1516579200, 0)) {
+		t.Fatalf("unexpected times: %+v", v)
+	}
+}
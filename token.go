@@ -0,0 +1,41 @@
+package mastodon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+type tokenContextKey struct{}
+
+// WithToken returns a copy of ctx with which the requests of a Client use
+// token instead of Config.AccessToken. A single Client can then act for
+// many accounts, sharing its transport while keeping rate limits and
+// cached responses apart for each token.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+func tokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(string)
+	return token, ok
+}
+
+// accessToken returns the token to authenticate the requests made with ctx.
+func (c *Client) accessToken(ctx context.Context) string {
+	if token, ok := tokenFromContext(ctx); ok {
+		return token
+	}
+	return c.Config.AccessToken
+}
+
+// tokenCacheKey scopes the cache key to the token set with WithToken, if
+// any, without keeping the token itself in the key.
+func tokenCacheKey(ctx context.Context, key string) string {
+	token, ok := tokenFromContext(ctx)
+	if !ok {
+		return key
+	}
+	sum := sha256.Sum256([]byte(token))
+	return key + "#" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,89 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if auth == "Bearer bar" {
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Remaining", "1")
+		} else {
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Remaining", "299")
+		}
+		fmt.Fprintf(w, `{"username": %q}`, auth)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "foo"})
+	a, err := client.GetAccountCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if a.Username != "Bearer foo" {
+		t.Fatalf("want %q but %q", "Bearer foo", a.Username)
+	}
+	ctx := WithToken(context.Background(), "bar")
+	a, err = client.GetAccountCurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if a.Username != "Bearer bar" {
+		t.Fatalf("want %q but %q", "Bearer bar", a.Username)
+	}
+	if client.Config.AccessToken != "foo" {
+		t.Fatalf("config should not be changed: %q", client.Config.AccessToken)
+	}
+
+	if rl := client.RateLimit(); rl == nil || rl.Remaining != 299 {
+		t.Fatalf("want %d but %v", 299, rl)
+	}
+	if rl := client.RateLimitContext(ctx); rl == nil || rl.Remaining != 1 {
+		t.Fatalf("want %d but %v", 1, rl)
+	}
+	if rl := client.RateLimitContext(WithToken(context.Background(), "baz")); rl != nil {
+		t.Fatalf("should be nil: %v", rl)
+	}
+}
+
+func TestWithTokenCache(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.Header.Get("Authorization")+`"`)
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintf(w, `{"id": "1", "username": %q}`, r.Header.Get("Authorization"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL, AccessToken: "foo"})
+	client.ResponseCache = NewMemoryResponseCache()
+	for _, token := range []string{"foo", "bar", "foo", "bar"} {
+		a, err := client.GetAccount(WithToken(context.Background(), token), "1")
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if a.Username != "Bearer "+token {
+			t.Fatalf("want %q but %q", "Bearer "+token, a.Username)
+		}
+	}
+}
+
+func TestTokenCacheKey(t *testing.T) {
+	if k := tokenCacheKey(context.Background(), "/foo"); k != "/foo" {
+		t.Fatalf("want %q but %q", "/foo", k)
+	}
+	foo := tokenCacheKey(WithToken(context.Background(), "foo"), "/foo")
+	bar := tokenCacheKey(WithToken(context.Background(), "bar"), "/foo")
+	if foo == "/foo" || foo == bar {
+		t.Fatalf("keys should be different: %q %q", foo, bar)
+	}
+}
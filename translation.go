@@ -0,0 +1,126 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Translator translates statuses.
+type Translator interface {
+	Translate(ctx context.Context, status *Status, lang string) (*Translation, error)
+}
+
+// TranslatorFunc is an adapter to use an ordinary function as a Translator.
+type TranslatorFunc func(ctx context.Context, status *Status, lang string) (*Translation, error)
+
+// Translate calls f(ctx, status, lang).
+func (f TranslatorFunc) Translate(ctx context.Context, status *Status, lang string) (*Translation, error) {
+	return f(ctx, status, lang)
+}
+
+// LanguageSupporter is implemented by translators that only handle some
+// language pairs. Translators that don't implement it are always tried.
+// source is empty when the language of the status is unknown.
+type LanguageSupporter interface {
+	SupportsLanguage(ctx context.Context, source, target string) bool
+}
+
+// TranslationChain translates statuses with the server-side translation of
+// the instance, falling back to other translators when the instance can't
+// translate a status.
+//
+// The instance is used when its translation languages include the pair of
+// the status language and the target language. Fallbacks are tried in order
+// when the instance doesn't support the pair or its translation fails.
+type TranslationChain struct {
+	Client    *Client
+	Fallbacks []Translator
+
+	mu        sync.Mutex
+	languages map[string][]string
+}
+
+// Translate translates status into lang.
+func (t *TranslationChain) Translate(ctx context.Context, status *Status, lang string) (*Translation, error) {
+	if status.Reblog != nil {
+		status = status.Reblog
+	}
+
+	var lastErr error
+	if t.serverSupports(ctx, status.Language, lang) {
+		tr, err := t.Client.TranslateStatus(ctx, status.ID, lang)
+		if err == nil {
+			return tr, nil
+		}
+		lastErr = err
+	}
+	for _, f := range t.Fallbacks {
+		if ls, ok := f.(LanguageSupporter); ok && !ls.SupportsLanguage(ctx, status.Language, lang) {
+			continue
+		}
+		tr, err := f.Translate(ctx, status, lang)
+		if err == nil {
+			return tr, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no translator for %q to %q", status.Language, lang)
+}
+
+// SupportsLanguage reports whether any translator of the chain can
+// translate from source to target.
+func (t *TranslationChain) SupportsLanguage(ctx context.Context, source, target string) bool {
+	if t.serverSupports(ctx, source, target) {
+		return true
+	}
+	for _, f := range t.Fallbacks {
+		if ls, ok := f.(LanguageSupporter); !ok || ls.SupportsLanguage(ctx, source, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TranslationChain) serverSupports(ctx context.Context, source, target string) bool {
+	if t.Client == nil {
+		return false
+	}
+	languages := t.translationLanguages(ctx)
+	if source == "" {
+		return len(languages) > 0
+	}
+	for _, l := range languages[source] {
+		if l == target {
+			return true
+		}
+	}
+	return false
+}
+
+// translationLanguages returns the translation languages of the instance,
+// looking them up the first time. Instances without translation support
+// answer with an empty map or a client error, which are kept; other
+// errors, like network errors or server errors, are not, so the lookup is
+// tried again by the next call.
+func (t *TranslationChain) translationLanguages(ctx context.Context) map[string][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.languages != nil {
+		return t.languages
+	}
+	languages, err := t.Client.GetInstanceTranslationLanguages(ctx)
+	var apiErr *APIError
+	switch {
+	case err == nil && languages != nil:
+		t.languages = languages
+	case err == nil, errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError:
+		t.languages = map[string][]string{}
+	}
+	return t.languages
+}
@@ -0,0 +1,144 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeTranslator struct {
+	provider string
+	sources  []string
+	err      error
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, status *Status, lang string) (*Translation, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Translation{Content: status.Content, Provider: f.provider}, nil
+}
+
+func (f *fakeTranslator) SupportsLanguage(ctx context.Context, source, target string) bool {
+	for _, s := range f.sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTranslationChain(t *testing.T) {
+	var languagesCalls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/instance/translation_languages":
+			languagesCalls++
+			fmt.Fprintln(w, `{"ja": ["en"], "de": ["en"]}`)
+		case "/api/v1/statuses/1/translate":
+			fmt.Fprintln(w, `{"content": "server", "provider": "instance"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	chain := &TranslationChain{
+		Client: NewClient(&Config{Server: ts.URL}),
+		Fallbacks: []Translator{
+			&fakeTranslator{provider: "libre", sources: []string{"fr"}},
+			&fakeTranslator{provider: "deepl", sources: []string{"fr", "de"}},
+		},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		status *Status
+		want   string
+	}{
+		{&Status{ID: "1", Language: "ja"}, "instance"},
+		{&Status{ID: "2", Language: "fr"}, "libre"},
+		{&Status{ID: "3", Language: "de"}, "deepl"},
+		{&Status{ID: "4", Reblog: &Status{ID: "1", Language: "ja"}}, "instance"},
+	}
+	for _, tt := range tests {
+		tr, err := chain.Translate(ctx, tt.status, "en")
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+		if tr.Provider != tt.want {
+			t.Fatalf("want %q but %q", tt.want, tr.Provider)
+		}
+	}
+	if languagesCalls != 1 {
+		t.Fatalf("translation languages should be fetched once: %d", languagesCalls)
+	}
+
+	_, err := chain.Translate(ctx, &Status{ID: "5", Language: "ko"}, "en")
+	if err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	if chain.SupportsLanguage(ctx, "ko", "en") {
+		t.Fatalf("want %v but %v", false, true)
+	}
+	if !chain.SupportsLanguage(ctx, "fr", "en") {
+		t.Fatalf("want %v but %v", true, false)
+	}
+
+	failing := errors.New("quota exceeded")
+	chain.Fallbacks = []Translator{&fakeTranslator{provider: "deepl", sources: []string{"fr"}, err: failing}}
+	_, err = chain.Translate(ctx, &Status{ID: "2", Language: "fr"}, "en")
+	if err != failing {
+		t.Fatalf("want %v but %v", failing, err)
+	}
+}
+
+func TestTranslationChainRetryLanguages(t *testing.T) {
+	var languagesCalls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/instance/translation_languages":
+			languagesCalls++
+			if languagesCalls == 1 {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, `{"ja": ["en"]}`)
+		case "/api/v1/statuses/1/translate":
+			fmt.Fprintln(w, `{"content": "server", "provider": "instance"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(&Config{Server: ts.URL})
+	client.RetryPolicy = &RetryPolicy{}
+	chain := &TranslationChain{
+		Client:    client,
+		Fallbacks: []Translator{&fakeTranslator{provider: "libre", sources: []string{"ja"}}},
+	}
+	ctx := context.Background()
+
+	tr, err := chain.Translate(ctx, &Status{ID: "1", Language: "ja"}, "en")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if tr.Provider != "libre" {
+		t.Fatalf("want %q but %q", "libre", tr.Provider)
+	}
+	tr, err = chain.Translate(ctx, &Status{ID: "1", Language: "ja"}, "en")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if tr.Provider != "instance" {
+		t.Fatalf("want %q but %q", "instance", tr.Provider)
+	}
+	chain.Translate(ctx, &Status{ID: "1", Language: "ja"}, "en")
+	if languagesCalls != 2 {
+		t.Fatalf("want %d but %d", 2, languagesCalls)
+	}
+}
@@ -11,6 +11,9 @@ func (t *Unixtime) UnmarshalJSON(data []byte) error {
 	if len(data) > 0 && data[0] == '"' && data[len(data)-1] == '"' {
 		data = data[1 : len(data)-1]
 	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
 	ts, err := strconv.ParseInt(string(data), 10, 64)
 	if err != nil {
 		return err
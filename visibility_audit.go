@@ -0,0 +1,235 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Reasons of AuditFindings.
+const (
+	AuditPublicKeyword  = "public_keyword"
+	AuditMissingWarning = "missing_content_warning"
+)
+
+// ContentWarningRule requires a content warning on statuses containing any
+// of Keywords.
+type ContentWarningRule struct {
+	Keywords []string
+	// SpoilerText is the content warning added by automatic edits.
+	SpoilerText string
+}
+
+// AuditFinding is a status that needs attention.
+type AuditFinding struct {
+	Status *Status
+	// Reason is AuditPublicKeyword or AuditMissingWarning.
+	Reason string
+	// Keyword is the keyword that matched.
+	Keyword string
+	// Rule is the rule that matched, for AuditMissingWarning.
+	Rule *ContentWarningRule
+	// Fixed reports whether the status was edited to add the content
+	// warning of Rule.
+	Fixed bool
+	// FixErr is the error of the automatic edit, if it failed.
+	FixErr error
+}
+
+// VisibilityReport holds the result of VisibilityAuditor.Audit.
+type VisibilityReport struct {
+	// Statuses is the number of statuses scanned, boosts excluded.
+	Statuses int
+	// Visibility holds the number of statuses per visibility.
+	Visibility map[Visibility]int
+	// Findings is the list of statuses to remediate, newest first.
+	Findings []*AuditFinding
+}
+
+// VisibilityAuditor scans the statuses of an account for public statuses
+// containing sensitive keywords and statuses missing content warnings.
+type VisibilityAuditor struct {
+	Client *Client
+	// PublicKeywords are keywords that shouldn't appear in public or
+	// unlisted statuses. Visibility can't be changed by editing, so these
+	// are only reported.
+	PublicKeywords []string
+	Rules          []ContentWarningRule
+	// Fix edits statuses missing a content warning to add the SpoilerText
+	// of the matching rule. Statuses with polls are not edited, since
+	// editing would reset the votes.
+	Fix bool
+	// MaxStatuses is the maximum number of statuses scanned; zero scans
+	// all of them.
+	MaxStatuses int
+	// Interval is waited between the pages of statuses scanned. Zero means
+	// one second and negative values disable it.
+	Interval time.Duration
+	// Jobs stores the checkpoints of the audits, named after the account,
+	// so an interrupted audit resumes where it left off; nil disables
+	// resuming.
+	Jobs CheckpointStore
+}
+
+// Audit scans the statuses of the account of id.
+func (a *VisibilityAuditor) Audit(ctx context.Context, id ID) (*VisibilityReport, error) {
+	interval := pageInterval(a.Interval)
+
+	r := &VisibilityReport{Visibility: map[Visibility]int{}}
+	job := "visibility-audit-" + string(id)
+	state := &auditState{a: a, r: r}
+	err := walkJob(ctx, a.Jobs, job, state, interval, &Pagination{Limit: 40}, func(pg *Pagination) (bool, error) {
+		statuses, err := a.Client.GetAccountStatuses(ctx, id, pg)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range statuses {
+			if s.Reblog != nil {
+				continue
+			}
+			if a.MaxStatuses > 0 && r.Statuses >= a.MaxStatuses {
+				return false, nil
+			}
+			r.Statuses++
+			r.Visibility[s.Visibility]++
+			if err := a.check(ctx, s, r); err != nil {
+				return false, err
+			}
+		}
+		return len(statuses) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := deleteJobs(ctx, a.Jobs, job); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// auditState is the state of the jobs of VisibilityAuditor. Findings refer
+// to their rule by index and keep the message of their FixErr.
+type auditState struct {
+	a *VisibilityAuditor
+	r *VisibilityReport
+}
+
+type auditStateJSON struct {
+	Statuses   int                `json:"statuses"`
+	Visibility map[Visibility]int `json:"visibility"`
+	Findings   []auditFindingJSON `json:"findings"`
+}
+
+type auditFindingJSON struct {
+	Status  *Status `json:"status"`
+	Reason  string  `json:"reason"`
+	Keyword string  `json:"keyword"`
+	Rule    int     `json:"rule"`
+	Fixed   bool    `json:"fixed"`
+	FixErr  string  `json:"fix_err,omitempty"`
+}
+
+func (s *auditState) MarshalJSON() ([]byte, error) {
+	v := auditStateJSON{Statuses: s.r.Statuses, Visibility: s.r.Visibility}
+	for _, f := range s.r.Findings {
+		fj := auditFindingJSON{Status: f.Status, Reason: f.Reason, Keyword: f.Keyword, Rule: -1, Fixed: f.Fixed}
+		for i := range s.a.Rules {
+			if f.Rule == &s.a.Rules[i] {
+				fj.Rule = i
+			}
+		}
+		if f.FixErr != nil {
+			fj.FixErr = f.FixErr.Error()
+		}
+		v.Findings = append(v.Findings, fj)
+	}
+	return json.Marshal(v)
+}
+
+func (s *auditState) UnmarshalJSON(b []byte) error {
+	var v auditStateJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	s.r.Statuses = v.Statuses
+	if v.Visibility != nil {
+		s.r.Visibility = v.Visibility
+	}
+	s.r.Findings = nil
+	for _, fj := range v.Findings {
+		f := &AuditFinding{Status: fj.Status, Reason: fj.Reason, Keyword: fj.Keyword, Fixed: fj.Fixed}
+		if fj.Rule >= 0 && fj.Rule < len(s.a.Rules) {
+			f.Rule = &s.a.Rules[fj.Rule]
+		}
+		if fj.FixErr != "" {
+			f.FixErr = errors.New(fj.FixErr)
+		}
+		s.r.Findings = append(s.r.Findings, f)
+	}
+	return nil
+}
+
+func (a *VisibilityAuditor) check(ctx context.Context, s *Status, r *VisibilityReport) error {
+	text := strings.ToLower(plainText(s.Content) + " " + s.SpoilerText)
+	if s.Visibility == VisibilityPublic || s.Visibility == VisibilityUnlisted {
+		if kw := matchKeyword(text, a.PublicKeywords); kw != "" {
+			r.Findings = append(r.Findings, &AuditFinding{Status: s, Reason: AuditPublicKeyword, Keyword: kw})
+		}
+	}
+	if s.SpoilerText != "" {
+		return nil
+	}
+	for i := range a.Rules {
+		rule := &a.Rules[i]
+		kw := matchKeyword(text, rule.Keywords)
+		if kw == "" {
+			continue
+		}
+		f := &AuditFinding{Status: s, Reason: AuditMissingWarning, Keyword: kw, Rule: rule}
+		r.Findings = append(r.Findings, f)
+		if a.Fix && rule.SpoilerText != "" && s.Poll == nil {
+			f.FixErr = a.addWarning(ctx, s, rule.SpoilerText)
+			f.Fixed = f.FixErr == nil
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// addWarning edits s to add a content warning, keeping its text, media and
+// language.
+func (a *VisibilityAuditor) addWarning(ctx context.Context, s *Status, spoiler string) error {
+	source, err := a.Client.GetStatusSource(ctx, s.ID)
+	if err != nil {
+		return err
+	}
+	toot := &Toot{
+		Status:      source.Text,
+		Sensitive:   s.Sensitive,
+		SpoilerText: spoiler,
+		Language:    s.Language,
+	}
+	for _, m := range s.MediaAttachments {
+		toot.MediaIDs = append(toot.MediaIDs, m.ID)
+	}
+	edited, err := a.Client.UpdateStatus(ctx, toot, s.ID)
+	if err != nil {
+		return err
+	}
+	*s = *edited
+	return nil
+}
+
+func matchKeyword(text string, keywords []string) string {
+	for _, kw := range keywords {
+		if kw != "" && strings.Contains(text, strings.ToLower(kw)) {
+			return kw
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVisibilityAuditor(t *testing.T) {
+	var edits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/accounts/1/statuses":
+			fmt.Fprintln(w, `[
+				{"id": "5", "visibility": "public", "content": "<p>My home address is 1 Main St</p>"},
+				{"id": "4", "visibility": "private", "content": "<p>Politics again</p>", "language": "en", "media_attachments": [{"id": "9"}]},
+				{"id": "3", "visibility": "public", "content": "<p>Politics poll</p>", "poll": {"id": "1"}},
+				{"id": "2", "visibility": "public", "content": "<p>politics</p>", "spoiler_text": "politics"},
+				{"id": "1", "visibility": "public", "content": "", "reblog": {"id": "0"}}
+			]`)
+		case r.URL.Path == "/api/v1/statuses/4/source":
+			fmt.Fprintln(w, `{"id": "4", "text": "Politics again"}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/statuses/4":
+			r.ParseForm()
+			edits = append(edits, r.Form.Encode())
+			fmt.Fprintln(w, `{"id": "4", "visibility": "private", "spoiler_text": "politics"}`)
+		default:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	a := &VisibilityAuditor{
+		Client:         NewClient(&Config{Server: ts.URL}),
+		PublicKeywords: []string{"address"},
+		Rules:          []ContentWarningRule{{Keywords: []string{"politics"}, SpoilerText: "politics"}},
+		Fix:            true,
+	}
+	r, err := a.Audit(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if r.Statuses != 4 || r.Visibility[VisibilityPublic] != 3 || r.Visibility[VisibilityFollowersOnly] != 1 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	if len(r.Findings) != 3 {
+		t.Fatalf("result should be three: %d", len(r.Findings))
+	}
+	if f := r.Findings[0]; f.Status.ID != "5" || f.Reason != AuditPublicKeyword || f.Keyword != "address" {
+		t.Fatalf("unexpected finding: %+v", f)
+	}
+	if f := r.Findings[1]; f.Status.ID != "4" || f.Reason != AuditMissingWarning || !f.Fixed || f.Status.SpoilerText != "politics" {
+		t.Fatalf("unexpected finding: %+v", f)
+	}
+	if f := r.Findings[2]; f.Status.ID != "3" || f.Fixed {
+		t.Fatalf("status with poll should not be fixed: %+v", f)
+	}
+	want := "language=en&media_ids%5B%5D=9&spoiler_text=politics&status=Politics+again"
+	if len(edits) != 1 || edits[0] != want {
+		t.Fatalf("want %q but %v", want, edits)
+	}
+}
+
+func TestVisibilityAuditorResume(t *testing.T) {
+	var firstPages int
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("max_id") == "" {
+			firstPages++
+			w.Header().Set("Link", `<http://example.com/api/v1/accounts/1/statuses?max_id=2>; rel="next"`)
+			fmt.Fprintln(w, `[{"id": "3", "visibility": "public", "content": "<p>politics</p>"}]`)
+			return
+		}
+		if fail {
+			fail = false
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `[{"id": "2", "visibility": "unlisted", "content": "<p>cats</p>"}]`)
+	}))
+	defer ts.Close()
+
+	a := &VisibilityAuditor{
+		Client:   NewClient(&Config{Server: ts.URL}),
+		Rules:    []ContentWarningRule{{Keywords: []string{"politics"}}},
+		Interval: -1,
+		Jobs:     &MemoryCheckpointStore{},
+	}
+	if _, err := a.Audit(context.Background(), "1"); err == nil {
+		t.Fatalf("should be fail: %v", err)
+	}
+	r, err := a.Audit(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if firstPages != 1 {
+		t.Fatalf("first page should be fetched once: %d", firstPages)
+	}
+	if r.Statuses != 2 || r.Visibility[VisibilityPublic] != 1 || r.Visibility[VisibilityUnlisted] != 1 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	if len(r.Findings) != 1 || r.Findings[0].Status.ID != "3" || r.Findings[0].Rule != &a.Rules[0] {
+		t.Fatalf("unexpected findings: %+v", r.Findings)
+	}
+}
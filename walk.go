@@ -0,0 +1,48 @@
+package mastodon
+
+import (
+	"context"
+	"time"
+)
+
+// defaultPageInterval is the delay between page requests of the helpers
+// reading many pages when they set none.
+const defaultPageInterval = time.Second
+
+// pageInterval returns interval, or defaultPageInterval when it is zero.
+// Negative intervals disable the delay.
+func pageInterval(interval time.Duration) time.Duration {
+	if interval == 0 {
+		return defaultPageInterval
+	}
+	return interval
+}
+
+// walkPages calls fetch with successive pages, starting from pg, until fetch
+// reports there is nothing more to read or the server returns no next page.
+// It waits interval between requests to stay within rate limits.
+func walkPages(ctx context.Context, interval time.Duration, pg *Pagination, fetch func(pg *Pagination) (bool, error)) error {
+	if pg == nil {
+		pg = &Pagination{}
+	}
+	for {
+		prev := pg.MaxID
+		more, err := fetch(pg)
+		if err != nil {
+			return err
+		}
+		if !more || pg.MaxID == "" || pg.MaxID == prev {
+			return nil
+		}
+		pg.SinceID = ""
+		pg.MinID = ""
+
+		if interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}